@@ -19,6 +19,11 @@
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description User API key (amk_...), accepted on endpoints covered by the key's scopes.
 package main
 
 import (
@@ -18,12 +18,21 @@ type Config struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	LogLevel     string        `yaml:"log_level"`
+	// TrustedProxies 受信任的反向代理（IP 或 CIDR），只有来自这些地址的请求才按 X-Forwarded-For/X-Real-IP 取客户端 IP
+	// 为空时不信任任何代理，客户端 IP 为连接的对端地址（限流、会话和 API Key 记录的 IP 均依赖此配置）
+	TrustedProxies []string `yaml:"trusted_proxies"`
 
 	Database  DatabaseConfig  `yaml:"database"`
 	JWT       JWTConfig       `yaml:"jwt"`
+	SIWE      SIWEConfig      `yaml:"siwe"`
+	APIKey    APIKeyConfig    `yaml:"api_key"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Email     EmailConfig     `yaml:"email"`
 	Ethereum  EthereumConfig  `yaml:"ethereum"`
 	Etherscan EtherscanConfig `yaml:"etherscan"`
 	Redis     RedisConfig     `yaml:"redis"`
+	Auction   AuctionConfig   `yaml:"auction"`
+	Admin     AdminConfig     `yaml:"admin"`
 }
 
 type DatabaseConfig struct {
@@ -40,8 +49,81 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string        `yaml:"secret"`
-	Expiration time.Duration `yaml:"expiration"`
+	Secret            string        `yaml:"secret"`
+	Expiration        time.Duration `yaml:"expiration"`         // 访问令牌有效期（默认15分钟）
+	RefreshExpiration time.Duration `yaml:"refresh_expiration"` // 刷新令牌有效期，每次刷新轮换并重新计时（默认7天）
+}
+
+// SIWEConfig Sign-In with Ethereum（EIP-4361）登录消息配置，链 ID 使用 ethereum.chain_id
+type SIWEConfig struct {
+	Domain    string        `yaml:"domain"`    // 请求签名的域名（前端 host，如 app.example.com），校验时必须一致
+	URI       string        `yaml:"uri"`       // 登录资源 URI（如 https://app.example.com），校验时必须一致
+	Statement string        `yaml:"statement"` // 钱包中展示的说明文字
+	NonceTTL  time.Duration `yaml:"nonce_ttl"` // nonce 有效期，同时作为消息的 Expiration Time（默认5分钟）
+}
+
+// APIKeyConfig 用户 API Key 配置
+type APIKeyConfig struct {
+	DefaultRateLimit int `yaml:"default_rate_limit"` // 创建时未指定的每分钟最大请求数（默认60）
+	MaxRateLimit     int `yaml:"max_rate_limit"`     // 每个 Key 允许设置的每分钟最大请求数上限（默认600）
+	MaxKeysPerUser   int `yaml:"max_keys_per_user"`  // 每个用户可用（未撤销且未过期）的 Key 数量上限（默认10）
+}
+
+// 限流身份：按哪种身份计数
+const (
+	RateLimitByIP     = "ip"      // 客户端 IP
+	RateLimitByUser   = "user"    // 用户ID（未认证时按 IP）
+	RateLimitByAPIKey = "api_key" // API Key（未使用 API Key 时按用户ID，未认证时按 IP）
+)
+
+// RateLimitRule 一组路由的限流规则（Redis 滑动窗口），Limit 为 0 表示不限流
+type RateLimitRule struct {
+	Limit    int           `yaml:"limit"`    // 窗口内最大请求数
+	Window   time.Duration `yaml:"window"`   // 窗口长度
+	Identity string        `yaml:"identity"` // 计数身份：ip、user、api_key
+}
+
+// RateLimitConfig 接口限流配置，按路由组命名：
+// auth（登录、刷新令牌）、rpc（触发链上 RPC 调用的公开接口）、user（需要认证的接口）
+type RateLimitConfig struct {
+	Rules map[string]RateLimitRule `yaml:"rules"`
+}
+
+// defaultRateLimitRules 未配置的路由组使用的默认规则
+func defaultRateLimitRules() map[string]RateLimitRule {
+	return map[string]RateLimitRule{
+		"auth": {Limit: 20, Window: time.Minute, Identity: RateLimitByIP},
+		"rpc":  {Limit: 30, Window: time.Minute, Identity: RateLimitByIP},
+		"user": {Limit: 300, Window: time.Minute, Identity: RateLimitByUser},
+	}
+}
+
+// SMTP 连接加密方式
+const (
+	SMTPTLSNone     = "none"     // 不加密（本地 SMTP 调试工具，如 MailHog、Mailpit）
+	SMTPTLSStartTLS = "starttls" // 明文连接后升级为 TLS（通常为 587 端口）
+	SMTPTLSImplicit = "tls"      // 直接建立 TLS 连接（通常为 465 端口）
+)
+
+// EmailConfig 邮件配置：邮箱验证和邮件通知
+type EmailConfig struct {
+	VerifyURL      string        `yaml:"verify_url"`      // 验证邮件中的前端链接，token 作为查询参数附加（默认 http://localhost:3000/verify-email）
+	TokenSecret    string        `yaml:"token_secret"`    // 验证令牌签名密钥（为空时使用 jwt.secret）
+	TokenTTL       time.Duration `yaml:"token_ttl"`       // 验证令牌有效期（默认24小时）
+	ResendInterval time.Duration `yaml:"resend_interval"` // 重新发送验证邮件的最小间隔（默认1分钟）
+	Notifications  bool          `yaml:"notifications"`   // 是否通过邮件发送通知（只发送到已验证的邮箱）
+	SMTP           SMTPConfig    `yaml:"smtp"`
+}
+
+// SMTPConfig SMTP 发信配置，Host 为空时不发送邮件，只记录日志
+type SMTPConfig struct {
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port"`     // 默认587
+	Username string        `yaml:"username"` // 为空时不认证
+	Password string        `yaml:"password"`
+	From     string        `yaml:"from"`    // 发件人（如 My Auction Market <no-reply@example.com>）
+	TLS      string        `yaml:"tls"`     // 加密方式：none、starttls、tls（默认 starttls）
+	Timeout  time.Duration `yaml:"timeout"` // 连接和发送超时（默认10秒）
 }
 
 type EthereumConfig struct {
@@ -69,6 +151,18 @@ type RedisConfig struct {
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 }
 
+type AuctionConfig struct {
+	DutchPriceTickInterval time.Duration   `yaml:"dutch_price_tick_interval"` // 荷兰式拍卖价格推送间隔（默认5秒）
+	EndingSoonReminders    []time.Duration `yaml:"ending_soon_reminders"`     // 拍卖即将结束提醒（结束前的时间点，默认 1h、5m；配置为空列表则关闭）
+	MaxRelistGenerations   int             `yaml:"max_relist_generations"`    // 流拍自动重新上架的最大次数（默认3）
+	PendingTTL             time.Duration   `yaml:"pending_ttl"`               // 待上架拍卖等待卖家签名链上 createAuction 的最长时间，超时标记为 expired 并释放 NFT 在线锁（默认24小时）
+	PendingSweepInterval   time.Duration   `yaml:"pending_sweep_interval"`    // 待上架拍卖过期扫描间隔（默认1分钟）
+}
+
+type AdminConfig struct {
+	Wallets []string `yaml:"wallets"` // 初始管理员钱包地址，启动时将对应用户设为 admin 角色（用户不存在时创建）
+}
+
 func MustLoad() Config {
 	configPath := getEnv("CONFIG_PATH", "config.yaml")
 
@@ -116,7 +210,71 @@ func LoadFromFile(path string) (Config, error) {
 		cfg.JWT.Secret = "your-secret-key-change-in-production"
 	}
 	if cfg.JWT.Expiration == 0 {
-		cfg.JWT.Expiration = 24 * time.Hour
+		cfg.JWT.Expiration = 15 * time.Minute
+	}
+	if cfg.JWT.RefreshExpiration == 0 {
+		cfg.JWT.RefreshExpiration = 7 * 24 * time.Hour
+	}
+
+	if cfg.SIWE.Domain == "" {
+		cfg.SIWE.Domain = "localhost:3000"
+	}
+	if cfg.SIWE.URI == "" {
+		cfg.SIWE.URI = "http://localhost:3000"
+	}
+	if cfg.SIWE.NonceTTL == 0 {
+		cfg.SIWE.NonceTTL = 5 * time.Minute
+	}
+
+	if cfg.APIKey.DefaultRateLimit == 0 {
+		cfg.APIKey.DefaultRateLimit = 60
+	}
+	if cfg.APIKey.MaxRateLimit == 0 {
+		cfg.APIKey.MaxRateLimit = 600
+	}
+	if cfg.APIKey.MaxKeysPerUser == 0 {
+		cfg.APIKey.MaxKeysPerUser = 10
+	}
+
+	// 未配置的限流路由组使用默认规则，已配置的规则补全窗口和身份
+	if cfg.RateLimit.Rules == nil {
+		cfg.RateLimit.Rules = make(map[string]RateLimitRule)
+	}
+	for name, rule := range defaultRateLimitRules() {
+		configured, ok := cfg.RateLimit.Rules[name]
+		if !ok {
+			cfg.RateLimit.Rules[name] = rule
+			continue
+		}
+		if configured.Window == 0 {
+			configured.Window = rule.Window
+		}
+		if configured.Identity == "" {
+			configured.Identity = rule.Identity
+		}
+		cfg.RateLimit.Rules[name] = configured
+	}
+
+	if cfg.Email.VerifyURL == "" {
+		cfg.Email.VerifyURL = "http://localhost:3000/verify-email"
+	}
+	if cfg.Email.TokenSecret == "" {
+		cfg.Email.TokenSecret = cfg.JWT.Secret
+	}
+	if cfg.Email.TokenTTL == 0 {
+		cfg.Email.TokenTTL = 24 * time.Hour
+	}
+	if cfg.Email.ResendInterval == 0 {
+		cfg.Email.ResendInterval = time.Minute
+	}
+	if cfg.Email.SMTP.Port == 0 {
+		cfg.Email.SMTP.Port = 587
+	}
+	if cfg.Email.SMTP.TLS == "" {
+		cfg.Email.SMTP.TLS = SMTPTLSStartTLS
+	}
+	if cfg.Email.SMTP.Timeout == 0 {
+		cfg.Email.SMTP.Timeout = 10 * time.Second
 	}
 
 	// 设置默认 WebSocket 超时时间（60秒，常见值）
@@ -144,6 +302,23 @@ func LoadFromFile(path string) (Config, error) {
 		cfg.Redis.WriteTimeout = 3 * time.Second
 	}
 
+	// 设置拍卖默认值
+	if cfg.Auction.DutchPriceTickInterval == 0 {
+		cfg.Auction.DutchPriceTickInterval = 5 * time.Second
+	}
+	if cfg.Auction.EndingSoonReminders == nil {
+		cfg.Auction.EndingSoonReminders = []time.Duration{time.Hour, 5 * time.Minute}
+	}
+	if cfg.Auction.MaxRelistGenerations == 0 {
+		cfg.Auction.MaxRelistGenerations = 3
+	}
+	if cfg.Auction.PendingTTL == 0 {
+		cfg.Auction.PendingTTL = 24 * time.Hour
+	}
+	if cfg.Auction.PendingSweepInterval == 0 {
+		cfg.Auction.PendingSweepInterval = time.Minute
+	}
+
 	return cfg, nil
 }
 
@@ -168,8 +343,34 @@ func getDefaultConfig() Config {
 			ConnMaxIdleTime: 10 * time.Minute,
 		},
 		JWT: JWTConfig{
-			Secret:     "your-secret-key-change-in-production",
-			Expiration: 24 * time.Hour,
+			Secret:            "your-secret-key-change-in-production",
+			Expiration:        15 * time.Minute,
+			RefreshExpiration: 7 * 24 * time.Hour,
+		},
+		SIWE: SIWEConfig{
+			Domain:    "localhost:3000",
+			URI:       "http://localhost:3000",
+			Statement: "Sign in to My Auction Market",
+			NonceTTL:  5 * time.Minute,
+		},
+		APIKey: APIKeyConfig{
+			DefaultRateLimit: 60,
+			MaxRateLimit:     600,
+			MaxKeysPerUser:   10,
+		},
+		RateLimit: RateLimitConfig{
+			Rules: defaultRateLimitRules(),
+		},
+		Email: EmailConfig{
+			VerifyURL:      "http://localhost:3000/verify-email",
+			TokenSecret:    "your-secret-key-change-in-production",
+			TokenTTL:       24 * time.Hour,
+			ResendInterval: time.Minute,
+			SMTP: SMTPConfig{
+				Port:    587,
+				TLS:     SMTPTLSStartTLS,
+				Timeout: 10 * time.Second,
+			},
 		},
 		Ethereum: EthereumConfig{
 			RPCURL:                 "https://sepolia.infura.io/v3/your-api-key",
@@ -192,6 +393,13 @@ func getDefaultConfig() Config {
 			ReadTimeout:  3 * time.Second,
 			WriteTimeout: 3 * time.Second,
 		},
+		Auction: AuctionConfig{
+			DutchPriceTickInterval: 5 * time.Second,
+			EndingSoonReminders:    []time.Duration{time.Hour, 5 * time.Minute},
+			MaxRelistGenerations:   3,
+			PendingTTL:             24 * time.Hour,
+			PendingSweepInterval:   time.Minute,
+		},
 	}
 }
 
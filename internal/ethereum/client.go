@@ -9,9 +9,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"my-auction-market-api/internal/config"
 	"my-auction-market-api/internal/logger"
@@ -58,6 +60,14 @@ func NewClientWithWSS(cfg config.EthereumConfig) (*Client, error) {
 		config: cfg,
 	}, nil
 }
+// NewClientFromEthClient 使用已建立的连接创建客户端（如进程内节点或模拟链）
+func NewClientFromEthClient(client *ethclient.Client, cfg config.EthereumConfig) *Client {
+	return &Client{
+		client: client,
+		config: cfg,
+	}
+}
+
 func (c *Client) GetClient() *ethclient.Client {
 	return c.client
 }
@@ -98,6 +108,43 @@ func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNu
 	return c.client.CallContract(ctx, msg, blockNumber)
 }
 
+// maxBatchCallSize 单个 JSON-RPC 批量请求包含的最大调用数（多数 RPC 服务商限制为 100~1000）
+const maxBatchCallSize = 100
+
+// BatchCallContract 通过 JSON-RPC 批量请求发送多个 eth_call（latest 区块），按 maxBatchCallSize 分批
+// 返回每个调用的结果和错误（单个调用失败不影响其他调用），请求本身失败时返回 error
+func (c *Client) BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg) ([][]byte, []error, error) {
+	results := make([][]byte, len(msgs))
+	callErrs := make([]error, len(msgs))
+	for start := 0; start < len(msgs); start += maxBatchCallSize {
+		end := min(start+maxBatchCallSize, len(msgs))
+		outputs := make([]hexutil.Bytes, end-start)
+		elems := make([]rpc.BatchElem, end-start)
+		for i, msg := range msgs[start:end] {
+			arg := map[string]interface{}{
+				"to":   msg.To,
+				"data": hexutil.Bytes(msg.Data),
+			}
+			if msg.From != (common.Address{}) {
+				arg["from"] = msg.From
+			}
+			elems[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{arg, "latest"},
+				Result: &outputs[i],
+			}
+		}
+		if err := c.client.Client().BatchCallContext(ctx, elems); err != nil {
+			return nil, nil, fmt.Errorf("failed to send batch call: %w", err)
+		}
+		for i, elem := range elems {
+			results[start+i] = outputs[i]
+			callErrs[start+i] = elem.Error
+		}
+	}
+	return results, callErrs, nil
+}
+
 func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
 	return c.client.PendingNonceAt(ctx, account)
 }
@@ -118,6 +165,11 @@ func (c *Client) GetTransactionByHash(ctx context.Context, txHash common.Hash) (
 	return c.client.TransactionByHash(ctx, txHash)
 }
 
+// CodeAt 获取地址上的合约代码（latest 区块），EOA 返回空
+func (c *Client) CodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.client.CodeAt(ctx, account, nil)
+}
+
 func (c *Client) Close() {
 	if c.client != nil {
 		c.client.Close()
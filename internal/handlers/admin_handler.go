@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type AdminHandler struct {
+	userService          *services.UserService
+	tokenService         *services.TokenService
+	auctionService       *services.AuctionService
+	contractAdminService *services.ContractAdminService
+}
+
+func NewAdminHandler(userService *services.UserService, tokenService *services.TokenService, auctionService *services.AuctionService,
+	contractAdminService *services.ContractAdminService) *AdminHandler {
+	return &AdminHandler{
+		userService:          userService,
+		tokenService:         tokenService,
+		auctionService:       auctionService,
+		contractAdminService: contractAdminService,
+	}
+}
+
+// UpdateUserRole godoc
+// @Summary      Update user role (admin)
+// @Description  Set a user's role to user, moderator or admin. Admins cannot change their own role. All of the user's sessions are revoked so the new role takes effect on their next login
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true  "User ID"
+// @Param        payload  body      models.UpdateUserRolePayload  true  "Role"
+// @Success      200      {object}  response.Response{data=models.UserProfile}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid user id")
+		return
+	}
+
+	var payload models.UpdateUserRolePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	operator, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	profile, err := h.userService.UpdateRole(operator.ID, userID, payload.Role)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 角色记录在 JWT 中，撤销所有会话，避免旧令牌继续携带原角色
+	if err := h.tokenService.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// UpdateUserStatus godoc
+// @Summary      Suspend, ban or reactivate a user (moderator or admin)
+// @Description  Sets a user's account status to active, suspended (optionally until suspendedUntil) or banned. Suspending or banning revokes all of the user's sessions, and authenticated requests (JWT or API key) are rejected with 403. Moderators can only change the status of regular users; nobody can change their own status
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "User ID"
+// @Param        payload  body      models.UpdateUserStatusPayload  true  "Status"
+// @Success      200      {object}  response.Response{data=models.UserProfile}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/users/{id}/status [put]
+func (h *AdminHandler) UpdateUserStatus(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid user id")
+		return
+	}
+
+	var payload models.UpdateUserStatusPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	operator, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	profile, err := h.userService.UpdateStatus(operator.ID, operator.Role, userID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 暂停、封禁后立即撤销所有会话
+	if payload.Status != models.UserStatusActive {
+		if err := h.tokenService.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+			response.Error(c, err)
+			return
+		}
+	}
+
+	response.Success(c, profile)
+}
+
+// CancelAuction godoc
+// @Summary      Cancel auction (moderator or admin)
+// @Description  Cancel any auction regardless of bids. Draft and pending auctions are cancelled immediately; on-chain auctions are cancelled with cancelAuction signed by the platform key, refunding the highest bidder
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                            true  "Auction ID (string)"
+// @Param        payload  body      models.AdminCancelAuctionPayload  true  "Cancel reason"
+// @Success      200      {object}  response.Response{data=models.CancelAuctionResponse}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/auctions/{id}/cancel [post]
+func (h *AdminHandler) CancelAuction(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.AdminCancelAuctionPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	result, err := h.auctionService.AdminCancel(user.ID, auctionID, payload.Reason)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ForceEndAuction godoc
+// @Summary      Force end auction (admin)
+// @Description  Call forceEndAuctionAndClaimNFT with the platform key: the NFT goes to the highest bidder, or back to the seller without bids. The auction is marked ended when the AuctionForceEnded event arrives
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Auction ID (string)"
+// @Param        payload  body      models.ForceEndAuctionPayload  true  "Force end reason"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/auctions/{id}/force-end [post]
+func (h *AdminHandler) ForceEndAuction(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.ForceEndAuctionPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.ForceEndAuction(adminUserID, auctionID, payload.Reason)
+	})
+}
+
+// PauseContract godoc
+// @Summary      Pause auction contract (admin)
+// @Description  Call pause with the platform key; creating auctions and bidding are disabled until unpaused
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.ContractPausePayload  true  "Reason"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/pause [post]
+func (h *AdminHandler) PauseContract(c *gin.Context) {
+	var payload models.ContractPausePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.Pause(adminUserID, payload.Reason)
+	})
+}
+
+// UnpauseContract godoc
+// @Summary      Unpause auction contract (admin)
+// @Description  Call unpause with the platform key
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.ContractPausePayload  true  "Reason"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/unpause [post]
+func (h *AdminHandler) UnpauseContract(c *gin.Context) {
+	var payload models.ContractPausePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.Unpause(adminUserID, payload.Reason)
+	})
+}
+
+// SetPlatformFee godoc
+// @Summary      Set platform fee (admin)
+// @Description  Call setPlatformFee with the platform key (basis points)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.SetPlatformFeePayload  true  "Platform fee"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/platform-fee [post]
+func (h *AdminHandler) SetPlatformFee(c *gin.Context) {
+	var payload models.SetPlatformFeePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.SetPlatformFee(adminUserID, &payload)
+	})
+}
+
+// SetFeeTiers godoc
+// @Summary      Set dynamic fee tiers (admin)
+// @Description  Call setFeeTiers with the platform key, replacing all tiers. Thresholds are USD values with 8 decimals and must be strictly increasing
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.SetFeeTiersPayload  true  "Fee tiers"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/fee-tiers [post]
+func (h *AdminHandler) SetFeeTiers(c *gin.Context) {
+	var payload models.SetFeeTiersPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.SetFeeTiers(adminUserID, &payload)
+	})
+}
+
+// SetDynamicFee godoc
+// @Summary      Enable or disable dynamic fee (admin)
+// @Description  Call setDynamicFeeEnabled with the platform key
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.SetDynamicFeePayload  true  "Dynamic fee switch"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/dynamic-fee [post]
+func (h *AdminHandler) SetDynamicFee(c *gin.Context) {
+	var payload models.SetDynamicFeePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.SetDynamicFee(adminUserID, &payload)
+	})
+}
+
+// SetPriceFeeds godoc
+// @Summary      Set token price feeds (admin)
+// @Description  Call setPriceFeed (one feed) or setPriceFeeds (several) with the platform key. The contract emits no event, so feeds are recorded once the transaction is mined
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.SetPriceFeedsPayload  true  "Price feeds"
+// @Success      200      {object}  response.Response{data=models.ContractAction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/price-feeds [post]
+func (h *AdminHandler) SetPriceFeeds(c *gin.Context) {
+	var payload models.SetPriceFeedsPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.submit(c, func(adminUserID uint64) (*models.ContractAction, error) {
+		return h.contractAdminService.SetPriceFeeds(adminUserID, &payload)
+	})
+}
+
+// ListContractActions godoc
+// @Summary      List contract admin actions (admin)
+// @Description  List transactions sent by admins with the platform key, newest first
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        action    query     string  false  "Action (force_end, pause, unpause, set_platform_fee, set_fee_tiers, set_dynamic_fee, set_price_feeds)"
+// @Param        status    query     string  false  "Status (requested, submitted, confirmed, failed)"
+// @Param        page      query     int     false  "Page number" default(1)
+// @Param        pageSize  query     int     false  "Page size" default(10)
+// @Success      200       {object}  response.Response{data=page.PageData{data=[]models.ContractAction}}
+// @Failure      400       {object}  response.Response
+// @Failure      401       {object}  response.Response
+// @Failure      403       {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/actions [get]
+func (h *AdminHandler) ListContractActions(c *gin.Context) {
+	var query page.PageQuery
+	if err := query.Bind(c); err != nil {
+		return
+	}
+
+	actions, total, err := h.contractAdminService.ListActions(c.Query("action"), c.Query("status"), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, page.NewPageData(query.Page, query.PageSize, total, actions))
+}
+
+// GetContractAction godoc
+// @Summary      Get contract admin action (admin)
+// @Description  Get a transaction sent by an admin, including who triggered it and its receipt status
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Action ID"
+// @Success      200  {object}  response.Response{data=models.ContractAction}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /admin/contract/actions/{id} [get]
+func (h *AdminHandler) GetContractAction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid action id")
+		return
+	}
+
+	action, err := h.contractAdminService.GetAction(id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, action)
+}
+
+// submit 以当前管理员身份发送合约管理交易
+func (h *AdminHandler) submit(c *gin.Context, send func(adminUserID uint64) (*models.ContractAction, error)) {
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	action, err := send(user.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, action)
+}
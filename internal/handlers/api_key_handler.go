@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type APIKeyHandler struct {
+	service *services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: apiKeyService,
+	}
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  Lists the current user's API keys, including revoked and expired ones. Key secrets are never returned
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]models.APIKeyResponse}
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.GetUint64("userId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// Create godoc
+// @Summary      Create API key
+// @Description  Creates an API key with the given scopes (read:auctions, write:auctions, read:nfts, write:nfts) and per-minute rate limit. The key is returned only once; send it in the X-API-Key header
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.CreateAPIKeyPayload  true  "API key payload"
+// @Success      201      {object}  response.Response{data=models.CreateAPIKeyResult}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var payload models.CreateAPIKeyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.Create(c.GetUint64("userId"), payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// Revoke godoc
+// @Summary      Revoke API key
+// @Description  Revokes one of the current user's API keys; requests using it are rejected immediately
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid API key id")
+		return
+	}
+
+	if err := h.service.Revoke(c.GetUint64("userId"), keyID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -52,13 +52,14 @@ func (h *AuctionHandler) List(c *gin.Context) {
 
 // ListPublic godoc
 // @Summary      List public auctions (for home page)
-// @Description  Get a paginated list of public auctions, sorted by status (active first, then ended) and time
+// @Description  Get a paginated list of public auctions, sorted by status (live first, then upcoming, then ended) and time
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
 // @Param        page      query     int     false  "Page number" default(1)
 // @Param        pageSize  query     int     false  "Page size" default(10)
-// @Param        status    query     string  false  "Filter by status (active, ended, all)" default(all)
+// @Param        status    query     string  false  "Filter by status (live, upcoming, ended, all)" default(all)
+// @Param        saleType  query     string  false  "Filter by sale type (auction, listing, all)" default(all)
 // @Success      200       {object}  response.Response
 // @Failure      400       {object}  response.Response
 // @Failure      500       {object}  response.Response
@@ -71,8 +72,10 @@ func (h *AuctionHandler) ListPublic(c *gin.Context) {
 
 	// 获取状态筛选参数
 	statusFilter := c.DefaultQuery("status", "all")
+	// 出售方式筛选：auction（拍卖）、listing（固定价格出售）
+	saleTypeFilter := c.DefaultQuery("saleType", "all")
 
-	auctions, total, err := h.service.ListPublic(query, statusFilter)
+	auctions, total, err := h.service.ListPublic(query, statusFilter, saleTypeFilter)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -84,13 +87,14 @@ func (h *AuctionHandler) ListPublic(c *gin.Context) {
 
 // GetByID godoc
 // @Summary      Get auction by ID
-// @Description  Get a single auction record by AuctionID (string, basic auction information)
+// @Description  Get a single auction record by AuctionID (string, basic auction information); invite-only auctions require a bearer token of the seller or an allowlisted user
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Auction ID (string)"
 // @Success      200  {object}  response.Response{data=models.Auction}
 // @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
 // @Failure      404  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Router       /auctions/{id} [get]
@@ -106,19 +110,62 @@ func (h *AuctionHandler) GetByID(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	if err := h.service.CheckAccess(auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	response.Success(c, auction)
 }
 
+// GetHistory godoc
+// @Summary      Get auction status history
+// @Description  Get the status transitions of an auction (from, to, actor, cause: api/event/task, tx hash) in chronological order; invite-only auctions require a bearer token of the seller or an allowlisted user
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=[]models.AuctionTransition}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /auctions/{id}/history [get]
+func (h *AuctionHandler) GetHistory(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	auction, err := h.service.GetByID(auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.CheckAccess(auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	history, err := h.service.GetHistory(auction.AuctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, history)
+}
+
 // GetDetailByID godoc
 // @Summary      Get auction detail by ID
-// @Description  Get detailed auction information by ID, including seller wallet address (no full user info or bids)
+// @Description  Get detailed auction information by ID, including seller wallet address (no full user info or bids); invite-only auctions require a bearer token of the seller or an allowlisted user
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Auction ID"
 // @Success      200  {object}  response.Response
 // @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
 // @Failure      404  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Router       /auctions/{id}/detail [get]
@@ -134,13 +181,17 @@ func (h *AuctionHandler) GetDetailByID(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	if err := h.service.CheckAccess(&auctionDetail.Auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	response.Success(c, auctionDetail)
 }
 
 // Create godoc
 // @Summary      Create a new auction
-// @Description  Create a new auction with the provided information
+// @Description  Create a new auction with the provided information; pass bundleNfts to auction several NFTs of the same seller as one lot (members must be approved to the platform signer, which transfers them to the winner after settlement), templateId to fill missing fields from a saved template, and draft=true to save a draft that does not lock the NFT
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
@@ -173,6 +224,41 @@ func (h *AuctionHandler) Create(c *gin.Context) {
 	response.Created(c, auction)
 }
 
+// BatchCreate godoc
+// @Summary      Batch create auctions
+// @Description  Create up to 50 single-NFT auctions at once; ownership and approval are read on chain in one batched RPC request, valid auctions are created in one transaction and per-item results are returned with the NFTs that still need approval
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.BatchAuctionPayload  true  "Batch auction payload"
+// @Success      200      {object}  response.Response{data=models.BatchAuctionResponse}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/batch [post]
+func (h *AuctionHandler) BatchCreate(c *gin.Context) {
+	var payload models.BatchAuctionPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	result, err := h.service.BatchCreate(user.ID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // GetUserAuctions godoc
 // @Summary      Get user auctions
 // @Description  Get auctions created by the current user
@@ -181,7 +267,7 @@ func (h *AuctionHandler) Create(c *gin.Context) {
 // @Produce      json
 // @Param        page      query     int      false  "Page number" default(1)
 // @Param        pageSize  query     int      false  "Page size" default(10)
-// @Param        status    query     []string false  "Filter by status (pending, active, ended, cancelled)" collectionFormat(multi)
+// @Param        status    query     []string false  "Filter by status (draft, pending, upcoming, active, ended, cancelled, expired)" collectionFormat(multi)
 // @Success      200       {object}  response.Response
 // @Failure      400       {object}  response.Response
 // @Failure      401       {object}  response.Response
@@ -215,7 +301,7 @@ func (h *AuctionHandler) GetUserAuctions(c *gin.Context) {
 
 // Update godoc
 // @Summary      Update auction
-// @Description  Update an existing auction (only pending auctions can be updated)
+// @Description  Update an existing auction (only draft or pending auctions can be updated)
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
@@ -394,7 +480,7 @@ func (h *AuctionHandler) CheckNFTApproval(c *gin.Context) {
 // @Produce      json
 // @Param        page      query     int      false  "Page number" default(1)
 // @Param        pageSize  query     int      false  "Page size" default(10)
-// @Param        status    query     []string false  "Filter by status (pending, active, ended, cancelled)" collectionFormat(multi)
+// @Param        status    query     []string false  "Filter by status (draft, pending, upcoming, active, ended, cancelled, expired)" collectionFormat(multi)
 // @Success      200       {object}  response.Response
 // @Failure      400       {object}  response.Response
 // @Failure      401       {object}  response.Response
@@ -428,12 +514,12 @@ func (h *AuctionHandler) GetUserAuctionHistory(c *gin.Context) {
 
 // Cancel godoc
 // @Summary      Cancel auction
-// @Description  Cancel an existing auction (only pending or active auctions can be cancelled)
+// @Description  Cancel an auction owned by the current user. Draft and pending auctions are cancelled immediately; upcoming or active auctions without bids return an unsigned cancelUserAuction transaction for the seller to sign, then submit its hash to /auctions/{id}/cancel/tx
 // @Tags         auctions
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Auction ID (string)"
-// @Success      200  {object}  response.Response
+// @Success      200  {object}  response.Response{data=models.CancelAuctionResponse}
 // @Failure      400  {object}  response.Response
 // @Failure      401  {object}  response.Response
 // @Failure      403  {object}  response.Response
@@ -454,7 +540,119 @@ func (h *AuctionHandler) Cancel(c *gin.Context) {
 		return
 	}
 
-	auction, err := h.service.Cancel(user.ID, auctionID)
+	result, err := h.service.Cancel(user.ID, auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// SubmitCancelTx godoc
+// @Summary      Submit cancel transaction
+// @Description  Submit the hash of the signed cancelUserAuction transaction; the backend verifies it and tracks the receipt until the AuctionCancelled event arrives
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "Auction ID (string)"
+// @Param        payload  body      models.SubmitCancelTxPayload  true  "Transaction hash"
+// @Success      200      {object}  response.Response{data=models.AuctionCancellation}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/cancel/tx [post]
+func (h *AuctionHandler) SubmitCancelTx(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.SubmitCancelTxPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	cancellation, err := h.service.SubmitCancelTx(user.ID, auctionID, payload.TxHash)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, cancellation)
+}
+
+// GetCancellation godoc
+// @Summary      Get auction cancellation
+// @Description  Get the latest cancellation request of an auction owned by the current user
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=models.AuctionCancellation}
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/cancellation [get]
+func (h *AuctionHandler) GetCancellation(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	cancellation, err := h.service.GetCancellation(user.ID, auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, cancellation)
+}
+
+// Abandon godoc
+// @Summary      Abandon pending auction
+// @Description  Abandon a pending auction that has not been created on chain yet: mark it expired and release the NFT lock. A late AuctionCreated event still restores the auction
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=models.Auction}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/abandon [post]
+func (h *AuctionHandler) Abandon(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	auction, err := h.service.Abandon(user.ID, auctionID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -463,6 +661,237 @@ func (h *AuctionHandler) Cancel(c *gin.Context) {
 	response.Success(c, auction)
 }
 
+// Commit godoc
+// @Summary      Commit auction draft
+// @Description  Commit a draft auction: verify NFT locks and on-chain approval, lock the NFT and move the auction to pending
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=models.Auction}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/commit [post]
+func (h *AuctionHandler) Commit(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	auction, err := h.service.Commit(user.ID, auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, auction)
+}
+
+// Clone godoc
+// @Summary      Clone auction
+// @Description  Create a new pending auction (or draft) from a past auction owned by the current user
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true   "Auction ID (string)"
+// @Param        payload  body      models.CloneAuctionPayload  false  "Clone options"
+// @Success      201      {object}  response.Response{data=models.Auction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/clone [post]
+func (h *AuctionHandler) Clone(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.CloneAuctionPayload
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	auction, err := h.service.Clone(user.ID, auctionID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, auction)
+}
+
+// Watch godoc
+// @Summary      Watch auction
+// @Description  Watch an auction to receive ending-soon reminders
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/watch [post]
+func (h *AuctionHandler) Watch(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	if err := h.service.Watch(user.ID, auctionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Unwatch godoc
+// @Summary      Unwatch auction
+// @Description  Stop watching an auction
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/watch [delete]
+func (h *AuctionHandler) Unwatch(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	if err := h.service.Unwatch(user.ID, auctionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetAllowlist godoc
+// @Summary      Get auction allowlist
+// @Description  Get the visibility and allowlist of an auction owned by the current user
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=models.AuctionAllowlistResponse}
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/allowlist [get]
+func (h *AuctionHandler) GetAllowlist(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	allowlist, err := h.service.GetAllowlist(user.ID, auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, allowlist)
+}
+
+// UpdateAllowlist godoc
+// @Summary      Update auction allowlist
+// @Description  Change the visibility (public, unlisted, allowlist) of an auction and replace its allowlist
+// @Tags         auctions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true  "Auction ID (string)"
+// @Param        payload  body      models.VisibilityParams  true  "Visibility and allowlist"
+// @Success      200      {object}  response.Response{data=models.AuctionAllowlistResponse}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/allowlist [put]
+func (h *AuctionHandler) UpdateAllowlist(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.VisibilityParams
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	allowlist, err := h.service.UpdateAllowlist(user.ID, auctionID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, allowlist)
+}
+
+// optionalUserID 公开接口中获取已登录用户ID（未携带、token 无效或会话已撤销时返回 0）
+func optionalUserID(c *gin.Context) uint64 {
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		return 0
+	}
+	return user.ID
+}
+
 // GetAuctionSimpleStats godoc
 // @Summary      Get auction simple statistics
 // @Description  Get simple statistics from contract (total auctions, total bids, platform fee, total value locked)
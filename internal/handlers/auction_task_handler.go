@@ -8,25 +8,31 @@ import (
 )
 
 type AuctionTaskHandler struct {
-	scheduler *services.AuctionTaskScheduler
+	scheduler      *services.AuctionTaskScheduler
+	auctionService *services.AuctionService
 }
 
-func NewAuctionTaskHandler(scheduler *services.AuctionTaskScheduler) *AuctionTaskHandler {
+func NewAuctionTaskHandler(scheduler *services.AuctionTaskScheduler, auctionService *services.AuctionService) *AuctionTaskHandler {
 	return &AuctionTaskHandler{
-		scheduler: scheduler,
+		scheduler:      scheduler,
+		auctionService: auctionService,
 	}
 }
 
 // CancelAuctionTask 取消拍卖任务
 // @Summary      Cancel auction end task
-// @Description  Cancel a scheduled auction end task by setting a cancellation marker
+// @Description  Cancel a scheduled auction end task by setting a cancellation marker (moderator or admin only)
 // @Tags         auction-tasks
 // @Accept       json
 // @Produce      json
 // @Param        auctionId  path      string  true  "Auction ID"
 // @Success      200        {object}  response.Response
 // @Failure      400        {object}  response.Response
+// @Failure      401        {object}  response.Response
+// @Failure      403        {object}  response.Response
+// @Failure      404        {object}  response.Response
 // @Failure      500        {object}  response.Response
+// @Security     BearerAuth
 // @Router       /auction-tasks/{auctionId} [delete]
 func (h *AuctionTaskHandler) CancelAuctionTask(c *gin.Context) {
 	auctionID := c.Param("auctionId")
@@ -35,6 +41,12 @@ func (h *AuctionTaskHandler) CancelAuctionTask(c *gin.Context) {
 		return
 	}
 
+	// 角色已由路由上的 RequireRole 校验，这里只确认拍卖存在
+	if _, err := h.auctionService.GetByID(auctionID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	// 取消任务（设置 Redis 取消标记）
 	if err := h.scheduler.CancelAuctionEndTask(auctionID); err != nil {
 		response.Error(c, err)
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type AuctionTemplateHandler struct {
+	service *services.AuctionService
+}
+
+func NewAuctionTemplateHandler(auctionService *services.AuctionService) *AuctionTemplateHandler {
+	return &AuctionTemplateHandler{
+		service: auctionService,
+	}
+}
+
+// List godoc
+// @Summary      List auction templates
+// @Description  List auction templates saved by the current user
+// @Tags         auction-templates
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]models.AuctionTemplate}
+// @Failure      401  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auction-templates [get]
+func (h *AuctionTemplateHandler) List(c *gin.Context) {
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	templates, err := h.service.ListTemplates(user.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, templates)
+}
+
+// Create godoc
+// @Summary      Create auction template
+// @Description  Save reusable auction parameters; pass templateId when creating an auction to apply them
+// @Tags         auction-templates
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.AuctionTemplatePayload  true  "Template payload"
+// @Success      201      {object}  response.Response{data=models.AuctionTemplate}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auction-templates [post]
+func (h *AuctionTemplateHandler) Create(c *gin.Context) {
+	var payload models.AuctionTemplatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	template, err := h.service.CreateTemplate(user.ID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, template)
+}
+
+// Update godoc
+// @Summary      Update auction template
+// @Description  Replace the parameters of an auction template owned by the current user
+// @Tags         auction-templates
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                            true  "Template ID"
+// @Param        payload  body      models.AuctionTemplatePayload  true  "Template payload"
+// @Success      200      {object}  response.Response{data=models.AuctionTemplate}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auction-templates/{id} [put]
+func (h *AuctionTemplateHandler) Update(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid template id")
+		return
+	}
+
+	var payload models.AuctionTemplatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(user.ID, templateID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template)
+}
+
+// Delete godoc
+// @Summary      Delete auction template
+// @Description  Delete an auction template owned by the current user
+// @Tags         auction-templates
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Template ID"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auction-templates/{id} [delete]
+func (h *AuctionTemplateHandler) Delete(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid template id")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteTemplate(user.ID, templateID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
@@ -24,7 +24,7 @@ func NewBidHandler(bidService *services.BidService, auctionService *services.Auc
 
 // GetBidsByAuctionID godoc
 // @Summary      Get bids by auction ID
-// @Description  Get all bids for a specific auction (only wallet address, no full user info)
+// @Description  Get all bids for a specific auction (only wallet address, no full user info); invite-only auctions require a bearer token of the seller or an allowlisted user
 // @Tags         bids
 // @Accept       json
 // @Produce      json
@@ -33,6 +33,7 @@ func NewBidHandler(bidService *services.BidService, auctionService *services.Auc
 // @Param        pageSize   query     int     false  "Page size" default(10)
 // @Success      200        {object}  response.Response
 // @Failure      400        {object}  response.Response
+// @Failure      403        {object}  response.Response
 // @Failure      500        {object}  response.Response
 // @Router       /auctions/{id}/bids [get]
 func (h *BidHandler) GetBidsByAuctionID(c *gin.Context) {
@@ -42,6 +43,16 @@ func (h *BidHandler) GetBidsByAuctionID(c *gin.Context) {
 		return
 	}
 
+	auction, err := h.auctionService.GetByID(auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.auctionService.CheckAccess(auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	var query page.PageQuery
 	if err := query.Bind(c); err != nil {
 		return
@@ -59,13 +70,14 @@ func (h *BidHandler) GetBidsByAuctionID(c *gin.Context) {
 
 // GetByID godoc
 // @Summary      Get bid by ID
-// @Description  Get a single bid by ID
+// @Description  Get a single bid by ID; bids of invite-only auctions require a bearer token of the seller or an allowlisted user
 // @Tags         bids
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Bid ID"
 // @Success      200  {object}  response.Response
 // @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
 // @Failure      404  {object}  response.Response
 // @Router       /bids/{id} [get]
 func (h *BidHandler) GetByID(c *gin.Context) {
@@ -80,6 +92,10 @@ func (h *BidHandler) GetByID(c *gin.Context) {
 		response.NotFound(c, err.Error())
 		return
 	}
+	if err := h.auctionService.CheckAccess(&bid.Auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	response.Success(c, bid)
 }
@@ -1,19 +1,62 @@
 package handlers
 
 import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gin-gonic/gin"
 
 	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
 	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
 )
 
 // EthereumConfigResponse 以太坊配置响应
 type EthereumConfigResponse struct {
 	RPCURL                 string `json:"rpcUrl"`
 	AuctionContractAddress string `json:"auctionContractAddress"`
+	PlatformSignerAddress  string `json:"platformSignerAddress,omitempty"` // 平台签名账户（打包拍卖成员需授权给该地址）
 	ChainID                int64  `json:"chainId"`
 }
 
+// ConfigResponse 公开配置响应
+type ConfigResponse struct {
+	Ethereum EthereumConfigResponse           `json:"ethereum"`
+	Contract *models.ContractSettingsResponse `json:"contract"` // 合约当前设置（由合约事件同步）
+}
+
+// GetConfig godoc
+// @Summary      Get public configuration
+// @Description  Get Ethereum network configuration and the current auction contract settings (paused state, platform fee, dynamic fee tiers, price feeds) synced from contract events
+// @Tags         config
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=ConfigResponse}
+// @Failure      500  {object}  response.Response
+// @Router       /config [get]
+func GetConfig(cfg config.Config, contractAdminService *services.ContractAdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings, err := contractAdminService.GetSettings()
+		if err != nil {
+			logger.Error("failed to get contract settings: %v", err)
+			response.Error(c, err)
+			return
+		}
+
+		response.Success(c, ConfigResponse{
+			Ethereum: EthereumConfigResponse{
+				RPCURL:                 cfg.Ethereum.RPCURL,
+				AuctionContractAddress: cfg.Ethereum.AuctionContractAddress,
+				PlatformSignerAddress:  platformSignerAddress(cfg.Ethereum.PlatformPrivateKey),
+				ChainID:                cfg.Ethereum.ChainID,
+			},
+			Contract: settings,
+		})
+	}
+}
+
 // GetEthereumConfig godoc
 // @Summary      Get Ethereum configuration
 // @Description  Get Ethereum network configuration including RPC URL, contract address, and chain ID
@@ -28,7 +71,17 @@ func GetEthereumConfig(cfg config.Config) gin.HandlerFunc {
 		response.Success(c, EthereumConfigResponse{
 			RPCURL:                 cfg.Ethereum.RPCURL,
 			AuctionContractAddress: cfg.Ethereum.AuctionContractAddress,
+			PlatformSignerAddress:  platformSignerAddress(cfg.Ethereum.PlatformPrivateKey),
 			ChainID:                cfg.Ethereum.ChainID,
 		})
 	}
 }
+
+// platformSignerAddress 平台私钥对应的账户地址，未配置或格式错误时为空
+func platformSignerAddress(privateKey string) string {
+	key, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type ListingHandler struct {
+	service *services.AuctionService
+}
+
+func NewListingHandler(auctionService *services.AuctionService) *ListingHandler {
+	return &ListingHandler{
+		service: auctionService,
+	}
+}
+
+// Create godoc
+// @Summary      Create fixed-price listing
+// @Description  Create a fixed-price listing (auctionType=fixed); the first on-chain bid reaching the price buys the NFT
+// @Tags         listings
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.ListingPayload  true  "Listing payload"
+// @Success      201      {object}  response.Response{data=models.Auction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /listings [post]
+func (h *ListingHandler) Create(c *gin.Context) {
+	var payload models.ListingPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	listing, err := h.service.CreateListing(user.ID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, listing)
+}
+
+// UpdatePrice godoc
+// @Summary      Update listing price
+// @Description  Change the price of a pending fixed-price listing
+// @Tags         listings
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                            true  "Auction ID (string)"
+// @Param        payload  body      models.UpdateListingPricePayload  true  "New price"
+// @Success      200      {object}  response.Response{data=models.Auction}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      403      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /listings/{id}/price [put]
+func (h *ListingHandler) UpdatePrice(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "listing id is required")
+		return
+	}
+
+	var payload models.UpdateListingPricePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	listing, err := h.service.UpdateListingPrice(user.ID, auctionID, payload.Price)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, listing)
+}
+
+// Delist godoc
+// @Summary      Delist fixed-price listing
+// @Description  Delist a fixed-price listing; on-chain listings are cancelled by the platform and updated when the AuctionCancelled event arrives
+// @Tags         listings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Auction ID (string)"
+// @Success      200  {object}  response.Response{data=models.Auction}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /listings/{id}/delist [post]
+func (h *ListingHandler) Delist(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "listing id is required")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	listing, err := h.service.Delist(user.ID, auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, listing)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type OfferHandler struct {
+	service *services.OfferService
+}
+
+func NewOfferHandler(offerService *services.OfferService) *OfferHandler {
+	return &OfferHandler{
+		service: offerService,
+	}
+}
+
+// Create godoc
+// @Summary      Make an offer on an NFT
+// @Description  Submit an EIP-712 signed offer on an NFT that is not on sale; the buyer must hold enough ERC20 balance and allowance to the auction contract
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true  "NFT ID (string)"
+// @Param        payload  body      models.OfferPayload  true  "Offer payload"
+// @Success      201      {object}  response.Response{data=models.Offer}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /nfts/{id}/offers [post]
+func (h *OfferHandler) Create(c *gin.Context) {
+	nftID := c.Param("id")
+	if nftID == "" {
+		response.BadRequest(c, "nft id is required")
+		return
+	}
+
+	var payload models.OfferPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	offer, err := h.service.Create(user.ID, nftID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, offer)
+}
+
+// ListReceived godoc
+// @Summary      List received offers
+// @Description  List offers made on NFTs held by the current user
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        status     query     string  false  "Offer status (pending, accepted, rejected, expired, cancelled)"
+// @Param        page       query     int     false  "Page number" default(1)
+// @Param        pageSize   query     int     false  "Page size" default(10)
+// @Success      200        {object}  response.Response
+// @Failure      401        {object}  response.Response
+// @Security     BearerAuth
+// @Router       /offers/received [get]
+func (h *OfferHandler) ListReceived(c *gin.Context) {
+	h.list(c, h.service.ListReceived)
+}
+
+// ListMade godoc
+// @Summary      List made offers
+// @Description  List offers submitted by the current user
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        status     query     string  false  "Offer status (pending, accepted, rejected, expired, cancelled)"
+// @Param        page       query     int     false  "Page number" default(1)
+// @Param        pageSize   query     int     false  "Page size" default(10)
+// @Success      200        {object}  response.Response
+// @Failure      401        {object}  response.Response
+// @Security     BearerAuth
+// @Router       /offers/made [get]
+func (h *OfferHandler) ListMade(c *gin.Context) {
+	h.list(c, h.service.ListMade)
+}
+
+func (h *OfferHandler) list(c *gin.Context, listFn func(uint64, string, page.PageQuery) ([]models.Offer, int64, error)) {
+	var query page.PageQuery
+	if err := query.Bind(c); err != nil {
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	offers, total, err := listFn(user.ID, c.Query("status"), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pageData := page.NewPageData(query.Page, query.PageSize, total, offers)
+	response.Success(c, pageData)
+}
+
+// Accept godoc
+// @Summary      Accept an offer
+// @Description  Accept a pending offer; a fixed-price listing at the offer amount is created for the NFT holder to sign on chain
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Offer ID"
+// @Success      200  {object}  response.Response{data=models.Offer}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /offers/{id}/accept [post]
+func (h *OfferHandler) Accept(c *gin.Context) {
+	h.act(c, h.service.Accept)
+}
+
+// Reject godoc
+// @Summary      Reject an offer
+// @Description  Reject a pending offer received by the current user
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Offer ID"
+// @Success      200  {object}  response.Response{data=models.Offer}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /offers/{id}/reject [post]
+func (h *OfferHandler) Reject(c *gin.Context) {
+	h.act(c, h.service.Reject)
+}
+
+// Cancel godoc
+// @Summary      Cancel an offer
+// @Description  Withdraw a pending offer submitted by the current user
+// @Tags         offers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Offer ID"
+// @Success      200  {object}  response.Response{data=models.Offer}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /offers/{id}/cancel [post]
+func (h *OfferHandler) Cancel(c *gin.Context) {
+	h.act(c, h.service.Cancel)
+}
+
+func (h *OfferHandler) act(c *gin.Context, actionFn func(uint64, uint64) (*models.Offer, error)) {
+	offerID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid offer id")
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	offer, err := actionFn(user.ID, offerID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, offer)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+	"my-auction-market-api/internal/response"
+	"my-auction-market-api/internal/services"
+)
+
+type SealedBidHandler struct {
+	service        *services.SealedBidService
+	auctionService *services.AuctionService
+}
+
+func NewSealedBidHandler(sealedBidService *services.SealedBidService, auctionService *services.AuctionService) *SealedBidHandler {
+	return &SealedBidHandler{
+		service:        sealedBidService,
+		auctionService: auctionService,
+	}
+}
+
+// Commit godoc
+// @Summary      Commit a sealed bid
+// @Description  Submit a signed bid commitment keccak256(auctionId|bidder|paymentToken|amount|salt) during the commit window
+// @Tags         sealed-bids
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Auction ID"
+// @Param        payload  body      models.SealedBidCommitPayload  true  "Commitment payload"
+// @Success      201      {object}  response.Response{data=models.SealedBid}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/sealed-bids/commit [post]
+func (h *SealedBidHandler) Commit(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.SealedBidCommitPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	sealedBid, err := h.service.Commit(user.ID, auctionID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, sealedBid)
+}
+
+// Reveal godoc
+// @Summary      Reveal a sealed bid
+// @Description  Reveal the amount, payment token and salt of a committed bid during the reveal window
+// @Tags         sealed-bids
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Auction ID"
+// @Param        payload  body      models.SealedBidRevealPayload  true  "Reveal payload"
+// @Success      200      {object}  response.Response{data=models.SealedBid}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auctions/{id}/sealed-bids/reveal [post]
+func (h *SealedBidHandler) Reveal(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	var payload models.SealedBidRevealPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := jwt.ExtractUserFromContext(c)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	sealedBid, err := h.service.Reveal(user.ID, auctionID, payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sealedBid)
+}
+
+// List godoc
+// @Summary      List sealed bids
+// @Description  List commitments of a sealed-bid auction; amounts are hidden until the reveal window closes; invite-only auctions require a bearer token of the seller or an allowlisted user
+// @Tags         sealed-bids
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true   "Auction ID"
+// @Param        page       query     int     false  "Page number" default(1)
+// @Param        pageSize   query     int     false  "Page size" default(10)
+// @Success      200        {object}  response.Response
+// @Failure      400        {object}  response.Response
+// @Failure      403        {object}  response.Response
+// @Failure      404        {object}  response.Response
+// @Router       /auctions/{id}/sealed-bids [get]
+func (h *SealedBidHandler) List(c *gin.Context) {
+	auctionID := c.Param("id")
+	if auctionID == "" {
+		response.BadRequest(c, "auction id is required")
+		return
+	}
+
+	auction, err := h.auctionService.GetByID(auctionID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.auctionService.CheckAccess(auction, optionalUserID(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query page.PageQuery
+	if err := query.Bind(c); err != nil {
+		return
+	}
+
+	bids, total, err := h.service.List(auctionID, query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pageData := page.NewPageData(query.Page, query.PageSize, total, bids)
+	response.Success(c, pageData)
+}
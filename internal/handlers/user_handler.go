@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 
+	"my-auction-market-api/internal/errors"
 	appJWT "my-auction-market-api/internal/jwt"
 	"my-auction-market-api/internal/logger"
 	"my-auction-market-api/internal/models"
@@ -12,14 +15,19 @@ import (
 )
 
 type UserHandler struct {
-	service         *services.UserService
-	listenerService *services.ListenerService
+	service           *services.UserService
+	tokenService      *services.TokenService
+	emailVerification *services.EmailVerificationService
+	listenerService   *services.ListenerService
 }
 
-func NewUserHandler(userService *services.UserService, listenerService *services.ListenerService) *UserHandler {
+func NewUserHandler(userService *services.UserService, tokenService *services.TokenService,
+	emailVerification *services.EmailVerificationService, listenerService *services.ListenerService) *UserHandler {
 	return &UserHandler{
-		service:         userService,
-		listenerService: listenerService,
+		service:           userService,
+		tokenService:      tokenService,
+		emailVerification: emailVerification,
+		listenerService:   listenerService,
 	}
 }
 
@@ -52,7 +60,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 
 // RequestNonce godoc
 // @Summary      Request a nonce for wallet login
-// @Description  Generates a unique nonce for a given wallet address to be signed by the user
+// @Description  Generates a single-use nonce for the wallet address and returns the Sign-In with Ethereum (EIP-4361) message to be signed by the user
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -79,7 +87,7 @@ func (h *UserHandler) RequestNonce(c *gin.Context) {
 
 // VerifyWalletLogin godoc
 // @Summary      Verify wallet signature and login
-// @Description  Verifies the signed message and logs in the user, returning a JWT token
+// @Description  Verifies the signed SIWE message (domain, URI, chain ID, nonce, validity window) and signature (ECDSA for EOAs, EIP-1271 for contract wallets, ERC-6492 for undeployed wallets), then logs in the user, returning a JWT token. The account is created on the wallet's first successful login (user.onboarded is false until onboarding is completed). Suspended and banned accounts get 403
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -87,6 +95,7 @@ func (h *UserHandler) RequestNonce(c *gin.Context) {
 // @Success      200          {object}  response.Response{data=models.LoginResult}
 // @Failure      400          {object}  response.Response
 // @Failure      401          {object}  response.Response
+// @Failure      403          {object}  response.Response
 // @Failure      500          {object}  response.Response
 // @Router       /auth/wallet/verify [post]
 func (h *UserHandler) VerifyWalletLogin(c *gin.Context) {
@@ -98,48 +107,279 @@ func (h *UserHandler) VerifyWalletLogin(c *gin.Context) {
 
 	user, err := h.service.VerifyWalletLogin(payload)
 	if err != nil {
-		response.Unauthorized(c, err.Error())
+		// 账户状态错误（403）原样返回，其他验证失败返回 401
+		if _, ok := errors.IsAppError(err); ok {
+			response.Error(c, err)
+		} else {
+			response.Unauthorized(c, err.Error())
+		}
 		return
 	}
 
+	// 登录使用的钱包可能是主钱包，也可能是已关联的钱包
+	loginWallet := strings.ToLower(payload.WalletAddress)
+
 	// 验证签名成功后，立即将钱包地址添加到监听服务
-	if h.listenerService != nil && h.listenerService.IsRunning() {
-		walletAddr := common.HexToAddress(user.WalletAddress)
-		if walletAddr != (common.Address{}) {
-			if err := h.listenerService.AddWalletAddress(walletAddr); err != nil {
-				// 添加监听失败不影响登录，只记录日志
-				logger.Warn("failed to add wallet address to listener after login: %v", err)
-			} else {
-				logger.Info("wallet address added to listener after login: %s", user.WalletAddress)
-			}
-		}
-	}
+	h.addWalletToListener(loginWallet)
 
-	// Generate JWT token
-	token, err := appJWT.GenerateToken(
-		user.ID,
-		user.Username,
-		user.Email,
-	)
+	// 创建会话并签发访问令牌和刷新令牌
+	tokens, err := h.tokenService.IssueTokens(c.Request.Context(), user, loginWallet, sessionClient(c))
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
 	response.Success(c, models.LoginResult{
-		Token: token,
-		User: models.UserProfile{
-			ID:            user.ID,
-			Username:      user.Username,
-			Email:         user.Email,
-			WalletAddress: user.WalletAddress,
-		},
+		TokenPair: *tokens,
+		User:      models.NewUserProfile(user),
 	})
 }
 
+// RefreshToken godoc
+// @Summary      Refresh access token
+// @Description  Exchanges a refresh token for a new access token and a new refresh token (the old refresh token becomes invalid; reusing it revokes the session)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        refresh  body      models.RefreshTokenPayload  true  "Refresh token"
+// @Success      200      {object}  response.Response{data=models.TokenPair}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Router       /auth/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var payload models.RefreshTokenPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	tokens, err := h.tokenService.Refresh(c.Request.Context(), payload.RefreshToken, sessionClient(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Revokes the current session (or all sessions of the user when allSessions is true); revoked access tokens are rejected immediately
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        logout  body      models.LogoutPayload  false  "Logout options"
+// @Success      200     {object}  response.Response
+// @Failure      401     {object}  response.Response
+// @Failure      500     {object}  response.Response
+// @Security     BearerAuth
+// @Router       /auth/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	var payload models.LogoutPayload
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	var err error
+	if payload.AllSessions {
+		err = h.tokenService.RevokeAllSessions(c.Request.Context(), c.GetUint64("userId"))
+	} else {
+		err = h.tokenService.RevokeSession(c.Request.Context(), c.GetString("sessionId"))
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListSessions godoc
+// @Summary      List login sessions
+// @Description  Lists the current user's active login sessions (devices), most recently active first
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]models.UserSession}
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/sessions [get]
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.tokenService.ListSessions(c.Request.Context(), c.GetUint64("userId"), c.GetString("sessionId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a login session
+// @Description  Revokes one of the current user's sessions; its refresh token and access tokens stop working immediately
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/sessions/{id} [delete]
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	if err := h.tokenService.RevokeUserSession(c.Request.Context(), c.GetUint64("userId"), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ListWallets godoc
+// @Summary      List wallets
+// @Description  Lists the current user's primary wallet and linked wallets
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]models.UserWalletResponse}
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/wallets [get]
+func (h *UserHandler) ListWallets(c *gin.Context) {
+	wallets, err := h.service.ListWallets(c.GetUint64("userId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, wallets)
+}
+
+// RequestLinkWalletNonce godoc
+// @Summary      Request a nonce for linking a wallet
+// @Description  Returns the SIWE message the wallet to be linked must sign; the nonce is bound to the current user and that wallet
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        wallet  body      models.LinkWalletNoncePayload  true  "Wallet address to link"
+// @Success      200     {object}  response.Response{data=models.WalletLoginRequestNonceResult}
+// @Failure      400     {object}  response.Response
+// @Failure      401     {object}  response.Response
+// @Failure      500     {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/wallets/link-nonce [post]
+func (h *UserHandler) RequestLinkWalletNonce(c *gin.Context) {
+	var payload models.LinkWalletNoncePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.RequestLinkWalletNonce(c.GetUint64("userId"), payload.WalletAddress)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// LinkWallet godoc
+// @Summary      Link a wallet
+// @Description  Verifies the wallet's signature over the link message and links the wallet to the current user. If the wallet is the primary wallet of another account, that account's wallets, NFTs, auctions, bids and offers are merged into the current user and the other account is deleted (mergedUserId); it must be an active regular account with no trades in progress. Wallets linked to another account must be unlinked there first
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        wallet  body      models.LinkWalletPayload  true  "Link wallet payload"
+// @Success      200     {object}  response.Response{data=models.UserWalletResponse}
+// @Failure      400     {object}  response.Response
+// @Failure      401     {object}  response.Response
+// @Failure      500     {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/wallets [post]
+func (h *UserHandler) LinkWallet(c *gin.Context) {
+	var payload models.LinkWalletPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	wallet, err := h.service.LinkWallet(c.GetUint64("userId"), payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 被合并的账户已注销，撤销其所有会话
+	if wallet.MergedUserID != 0 {
+		if err := h.tokenService.RevokeAllSessions(c.Request.Context(), wallet.MergedUserID); err != nil {
+			response.Error(c, err)
+			return
+		}
+	}
+
+	h.addWalletToListener(wallet.WalletAddress)
+
+	response.Success(c, wallet)
+}
+
+// UnlinkWallet godoc
+// @Summary      Unlink a wallet
+// @Description  Unlinks a linked wallet. The primary wallet cannot be unlinked, nor can wallets with auctions in progress, NFTs listed for sale, or outstanding bids
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        address  path      string  true  "Wallet address"
+// @Success      200      {object}  response.Response
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      404      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/wallets/{address} [delete]
+func (h *UserHandler) UnlinkWallet(c *gin.Context) {
+	if err := h.service.UnlinkWallet(c.GetUint64("userId"), c.Param("address")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// addWalletToListener 将钱包地址添加到监听服务，失败只记录日志
+func (h *UserHandler) addWalletToListener(walletAddress string) {
+	if h.listenerService == nil || !h.listenerService.IsRunning() {
+		return
+	}
+	walletAddr := common.HexToAddress(walletAddress)
+	if walletAddr == (common.Address{}) {
+		return
+	}
+	if err := h.listenerService.AddWalletAddress(walletAddr); err != nil {
+		logger.Warn("failed to add wallet address to listener: %v", err)
+	} else {
+		logger.Info("wallet address added to listener: %s", walletAddress)
+	}
+}
+
+// sessionClient 请求的客户端信息（记录到登录会话）
+func sessionClient(c *gin.Context) services.SessionClient {
+	return services.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
+
 // UpdateProfile godoc
 // @Summary      Update user profile
-// @Description  Update user profile information (username and/or email)
+// @Description  Update user profile information (username, email, display name, avatar URL and/or bio). Omitted fields are left unchanged; an empty displayName, avatarUrl or bio clears it. Changing the email marks it unverified and sends a verification email
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -164,8 +404,8 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	// 验证至少提供一个字段
-	if payload.Username == "" && payload.Email == "" {
-		response.BadRequest(c, "at least one field (username or email) must be provided")
+	if payload.Username == "" && payload.Email == "" && payload.DisplayName == nil && payload.AvatarURL == nil && payload.Bio == nil {
+		response.BadRequest(c, "at least one field (username, email, displayName, avatarUrl or bio) must be provided")
 		return
 	}
 
@@ -178,6 +418,111 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	response.Success(c, profile)
 }
 
+// CompleteOnboarding godoc
+// @Summary      Complete onboarding
+// @Description  Sets the username and optional profile fields (display name, avatar URL, bio, email) after the first wallet login. Can only be completed once; use PUT /users/profile afterwards
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.OnboardingPayload  true  "Onboarding payload"
+// @Success      200      {object}  response.Response{data=models.UserProfile}
+// @Failure      400      {object}  response.Response
+// @Failure      401      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/onboarding [post]
+func (h *UserHandler) CompleteOnboarding(c *gin.Context) {
+	var payload models.OnboardingPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	profile, err := h.service.CompleteOnboarding(c.GetUint64("userId"), payload)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// DeleteAccount godoc
+// @Summary      Delete account
+// @Description  Deletes the current user's account: personal data is cleared, all wallets are unlinked, API keys and sessions are revoked, and pending offers are withdrawn or rejected. Auction and bid history is kept. Fails while the user has auctions in progress, NFTs listed for sale, or outstanding bids
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/account [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID := c.GetUint64("userId")
+	if err := h.service.DeleteAccount(userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 账户已注销，撤销所有会话失败只记录日志（账户状态检查仍会拒绝这些会话）
+	if err := h.tokenService.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+		logger.Warn("failed to revoke sessions of deleted account: userID=%d, error=%v", userID, err)
+	}
+
+	response.Success(c, nil)
+}
+
+// ResendEmailVerification godoc
+// @Summary      Resend verification email
+// @Description  Sends a new verification email to the current user's unverified email address. Limited to one request per email.resend_interval
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      429  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     BearerAuth
+// @Router       /users/email/verification [post]
+func (h *UserHandler) ResendEmailVerification(c *gin.Context) {
+	if err := h.emailVerification.ResendVerification(c.Request.Context(), c.GetUint64("userId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Verifies the email address with the signed token from the verification email link. The token expires after email.token_ttl and only works while the address is still the account's email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      models.VerifyEmailPayload  true  "Verification token"
+// @Success      200      {object}  response.Response{data=models.UserProfile}
+// @Failure      400      {object}  response.Response
+// @Failure      500      {object}  response.Response
+// @Router       /auth/email/verify [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var payload models.VerifyEmailPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	profile, err := h.emailVerification.VerifyEmail(c.Request.Context(), payload.Token)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
 // GetPlatformStats godoc
 // @Summary      Get platform statistics
 // @Description  Get platform statistics including total users, total auctions, and total bids
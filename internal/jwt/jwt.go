@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
@@ -12,9 +13,11 @@ import (
 )
 
 type Claims struct {
-	UserID   uint64 `json:"userId"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID    uint64 `json:"userId"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"` // 登录会话ID，撤销会话后该会话签发的访问令牌全部失效
 	jwtlib.RegisteredClaims
 }
 
@@ -22,6 +25,7 @@ type UserInfo struct {
 	ID       uint64 `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Role     string `json:"role"`
 }
 
 var cfg config.JWTConfig
@@ -37,20 +41,23 @@ func getSecret() string {
 	return cfg.Secret
 }
 
-func getExpiration() time.Duration {
+// Expiration 访问令牌有效期
+func Expiration() time.Duration {
 	if cfg.Expiration == 0 {
-		return 24 * time.Hour
+		return 15 * time.Minute
 	}
 	return cfg.Expiration
 }
 
-func GenerateToken(userID uint64, username, email string) (string, error) {
+func GenerateToken(userID uint64, username, email, role, sessionID string) (string, error) {
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:    userID,
+		Username:  username,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwtlib.RegisteredClaims{
-			ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(getExpiration())),
+			ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(Expiration())),
 			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
 			NotBefore: jwtlib.NewNumericDate(time.Now()),
 		},
@@ -79,20 +86,8 @@ func ParseToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
-func ExtractUser(tokenString string) (*UserInfo, error) {
-	claims, err := ParseToken(tokenString)
-	if err != nil {
-		return nil, err
-	}
-
-	return &UserInfo{
-		ID:       claims.UserID,
-		Username: claims.Username,
-		Email:    claims.Email,
-	}, nil
-}
-
-func ExtractUserFromHeader(authHeader string) (*UserInfo, error) {
+// ExtractUserFromHeader 解析 Authorization 请求头中的 Bearer 令牌，与认证中间件一样校验令牌是否已被撤销
+func ExtractUserFromHeader(ctx context.Context, authHeader string) (*UserInfo, error) {
 	if authHeader == "" {
 		return nil, errors.New("authorization header missing")
 	}
@@ -102,9 +97,28 @@ func ExtractUserFromHeader(authHeader string) (*UserInfo, error) {
 		return nil, errors.New("invalid authorization header format")
 	}
 
-	return ExtractUser(parts[1])
+	claims, err := ValidateToken(ctx, parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserInfo{
+		ID:       claims.UserID,
+		Username: claims.Username,
+		Email:    claims.Email,
+		Role:     claims.Role,
+	}, nil
 }
 
+// ExtractUserFromContext 获取当前用户：优先使用认证中间件写入的用户信息（JWT 或 API Key），否则解析 Authorization 请求头
 func ExtractUserFromContext(c *gin.Context) (*UserInfo, error) {
-	return ExtractUserFromHeader(c.GetHeader("Authorization"))
+	if userID := c.GetUint64("userId"); userID != 0 {
+		return &UserInfo{
+			ID:       userID,
+			Username: c.GetString("username"),
+			Email:    c.GetString("email"),
+			Role:     c.GetString("role"),
+		}, nil
+	}
+	return ExtractUserFromHeader(c.Request.Context(), c.GetHeader("Authorization"))
 }
@@ -0,0 +1,39 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RevocationChecker 检查访问令牌是否已被撤销（登出、撤销会话）
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+var revocationChecker RevocationChecker
+
+// SetRevocationChecker 设置撤销检查器，未设置时只校验签名和有效期
+func SetRevocationChecker(checker RevocationChecker) {
+	revocationChecker = checker
+}
+
+// ValidateToken 解析访问令牌并检查是否已被撤销，撤销检查失败时拒绝令牌
+func ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if revocationChecker == nil {
+		return claims, nil
+	}
+
+	revoked, err := revocationChecker.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+	return claims, nil
+}
@@ -0,0 +1,179 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/logger"
+)
+
+// headerReplacer 去除邮件头中的换行，防止头部注入
+var headerReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+// Message 一封纯文本邮件
+type Message struct {
+	To      string // 收件人地址
+	Subject string
+	Body    string // 纯文本正文
+}
+
+// Sender 邮件发送器，新的发信方式（如第三方邮件 API）实现该接口即可替换 SMTP
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewSender 根据配置创建发送器：未配置 SMTP 主机时返回只记录日志的 LogSender
+func NewSender(cfg config.SMTPConfig) (Sender, error) {
+	if cfg.Host == "" {
+		return LogSender{}, nil
+	}
+	return NewSMTPSender(cfg)
+}
+
+// LogSender 不发送邮件，只记录日志（开发环境未配置 SMTP 时使用）
+type LogSender struct{}
+
+// linkQueryPattern 链接中的查询参数（如验证令牌）
+var linkQueryPattern = regexp.MustCompile(`(https?://[^\s?#]+)\?[^\s#]*`)
+
+// redactLinks 隐去正文中链接的查询参数，避免验证令牌等凭据写入日志
+func redactLinks(body string) string {
+	return linkQueryPattern.ReplaceAllString(body, "$1?[redacted]")
+}
+
+// Send 记录邮件内容（链接的查询参数已隐去）
+func (LogSender) Send(ctx context.Context, message *Message) error {
+	logger.Info("email not sent (smtp not configured): to=%s, subject=%s, body=%s", message.To, message.Subject, redactLinks(message.Body))
+	return nil
+}
+
+// SMTPSender 通过 SMTP 发送邮件，每封邮件使用一个新连接
+type SMTPSender struct {
+	cfg  config.SMTPConfig
+	from *netmail.Address
+}
+
+func NewSMTPSender(cfg config.SMTPConfig) (*SMTPSender, error) {
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address %q: %w", cfg.From, err)
+	}
+	switch cfg.TLS {
+	case config.SMTPTLSNone, config.SMTPTLSStartTLS, config.SMTPTLSImplicit:
+	default:
+		return nil, fmt.Errorf("invalid smtp tls mode: %s", cfg.TLS)
+	}
+	return &SMTPSender{cfg: cfg, from: from}, nil
+}
+
+// Send 连接 SMTP 服务器发送邮件（按 tls 配置加密，配置了用户名时使用 PLAIN 认证）
+func (s *SMTPSender) Send(ctx context.Context, message *Message) error {
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", message.To, err)
+	}
+	data, err := s.build(to, message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.TLS == config.SMTPTLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if s.cfg.TLS == config.SMTPTLSStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+// build 生成邮件内容（UTF-8 纯文本，正文使用 quoted-printable 编码）
+func (s *SMTPSender) build(to *netmail.Address, message *Message) ([]byte, error) {
+	messageID := make([]byte, 16)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, fmt.Errorf("failed to generate message id: %w", err)
+	}
+	domain := s.from.Address[strings.LastIndex(s.from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", s.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", headerReplacer.Replace(message.Subject))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + hex.EncodeToString(messageID) + "@" + domain + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		buf.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(message.Body, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+package mail
+
+import "testing"
+
+func TestRedactLinks(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{
+			"Open the link below:\n\nhttps://app.example.com/verify-email?token=abc.def\n\nThanks",
+			"Open the link below:\n\nhttps://app.example.com/verify-email?[redacted]\n\nThanks",
+		},
+		{"http://localhost:3000/verify-email?token=abc&x=1#top", "http://localhost:3000/verify-email?[redacted]#top"},
+		{"See https://app.example.com/auctions/1 for details", "See https://app.example.com/auctions/1 for details"},
+		{"no links here?token=abc", "no links here?token=abc"},
+	}
+	for _, tt := range tests {
+		if got := redactLinks(tt.body); got != tt.want {
+			t.Errorf("redactLinks(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
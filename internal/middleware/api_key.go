@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/response"
+)
+
+// APIKeyHeader API Key 请求头，也可以使用 Authorization: Bearer <API Key>
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator 验证 API Key
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string, ip string) (*models.APIKeyPrincipal, error)
+}
+
+var apiKeyAuthenticator APIKeyAuthenticator
+
+// SetAPIKeyAuthenticator 设置 API Key 验证器，未设置时不接受 API Key
+func SetAPIKeyAuthenticator(authenticator APIKeyAuthenticator) {
+	apiKeyAuthenticator = authenticator
+}
+
+// APIKeyScopes 接口允许 API Key 访问时所需的权限范围：GET/HEAD 请求需要 Read，其他请求需要 Write
+type APIKeyScopes struct {
+	Read  string
+	Write string
+}
+
+// required 当前请求所需的权限范围
+func (s APIKeyScopes) required(method string) string {
+	if method == http.MethodGet || method == http.MethodHead {
+		return s.Read
+	}
+	return s.Write
+}
+
+// requestAPIKey 从请求头中取出 API Key（X-API-Key，或以 API Key 前缀开头的 Bearer 令牌）
+func requestAPIKey(c *gin.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if ok && strings.HasPrefix(token, models.APIKeyPrefix) {
+		return token
+	}
+	return ""
+}
+
+// authenticateAPIKey 使用 API Key 认证：校验 Key、接口是否接受 API Key 及权限范围、请求频率
+// 认证成功时写入与 JWT 相同的用户信息（不含会话ID），失败时中止请求并返回 false
+func authenticateAPIKey(c *gin.Context, key string, scopes []APIKeyScopes) bool {
+	if apiKeyAuthenticator == nil || len(scopes) == 0 {
+		response.Forbidden(c, "API keys are not accepted for this endpoint")
+		c.Abort()
+		return false
+	}
+
+	principal, err := apiKeyAuthenticator.AuthenticateAPIKey(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		c.Abort()
+		return false
+	}
+
+	required := scopes[0].required(c.Request.Method)
+	if required == "" {
+		response.Forbidden(c, "API keys are not accepted for this endpoint")
+		c.Abort()
+		return false
+	}
+	if !slices.Contains(principal.Scopes, required) {
+		response.Forbidden(c, "API key is missing required scope: "+required)
+		c.Abort()
+		return false
+	}
+
+	// 每个 Key 按自己的每分钟请求数限流
+	if rateLimiter != nil {
+		key := "apikey:" + strconv.FormatUint(principal.KeyID, 10)
+		if !allowRequest(c, key, principal.RateLimit, time.Minute, "API key rate limit exceeded") {
+			return false
+		}
+	}
+
+	c.Set("userId", principal.UserID)
+	c.Set("username", principal.Username)
+	c.Set("email", principal.Email)
+	c.Set("role", principal.Role)
+	c.Set("apiKeyId", principal.KeyID)
+	return true
+}
@@ -1,16 +1,70 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"my-auction-market-api/internal/errors"
 	appJWT "my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/logger"
 	"my-auction-market-api/internal/response"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// SessionTracker 记录登录会话的最近活跃时间和客户端信息
+type SessionTracker interface {
+	TouchSession(ctx context.Context, sessionID string, userAgent string, ip string) error
+}
+
+var sessionTracker SessionTracker
+
+// SetSessionTracker 设置会话活跃记录器，未设置时不记录
+func SetSessionTracker(tracker SessionTracker) {
+	sessionTracker = tracker
+}
+
+// AccountStatusChecker 检查账户状态，暂停、封禁或已注销的账户返回应用错误
+type AccountStatusChecker interface {
+	CheckAccountStatus(ctx context.Context, userID uint64) error
+}
+
+var accountStatusChecker AccountStatusChecker
+
+// SetAccountStatusChecker 设置账户状态检查器，未设置时不检查
+func SetAccountStatusChecker(checker AccountStatusChecker) {
+	accountStatusChecker = checker
+}
+
+// checkAccountStatus 检查已认证用户的账户状态，账户不可用时中止请求并返回 false
+// 检查本身失败（如 Redis 不可用）时放行，只记录日志
+func checkAccountStatus(c *gin.Context) bool {
+	if accountStatusChecker == nil {
+		return true
+	}
+	userID := c.GetUint64("userId")
+	if err := accountStatusChecker.CheckAccountStatus(c.Request.Context(), userID); err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			response.Error(c, err)
+			c.Abort()
+			return false
+		}
+		logger.Warn("failed to check account status, allowing request: userID=%d, error=%v", userID, err)
+	}
+	return true
+}
+
+// AuthMiddleware 认证中间件：接受 Bearer JWT；传入 apiKeyScopes 时同时接受 API Key（按请求方法校验权限范围），
+// 未传入时拒绝 API Key
+func AuthMiddleware(apiKeyScopes ...APIKeyScopes) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := requestAPIKey(c); apiKey != "" {
+			if authenticateAPIKey(c, apiKey, apiKeyScopes) && checkAccountStatus(c) {
+				c.Next()
+			}
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			response.Unauthorized(c, "authorization header is required")
@@ -26,7 +80,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims, err := appJWT.ParseToken(tokenString)
+		claims, err := appJWT.ValidateToken(c.Request.Context(), tokenString)
 		if err != nil {
 			response.Unauthorized(c, "invalid or expired token")
 			c.Abort()
@@ -37,6 +91,19 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("userId", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
+		c.Set("sessionId", claims.SessionID)
+
+		if !checkAccountStatus(c) {
+			return
+		}
+
+		// 更新会话最近活跃时间（失败不影响请求）
+		if sessionTracker != nil {
+			if err := sessionTracker.TouchSession(c.Request.Context(), claims.SessionID, c.Request.UserAgent(), c.ClientIP()); err != nil {
+				logger.Warn("failed to touch session: sessionID=%s, error=%v", claims.SessionID, err)
+			}
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/redisdb"
+	"my-auction-market-api/internal/response"
+)
+
+// rateLimitKeyPrefix Redis 中限流窗口的键前缀：ratelimit:<路由组>:<身份类型>:<身份>
+const rateLimitKeyPrefix = "ratelimit:"
+
+// slidingWindowScript 滑动窗口限流：有序集合中保存窗口内每个请求的时间（毫秒），先清理窗口外的请求再计数
+// KEYS[1] 窗口键；ARGV[1] 当前时间（毫秒）；ARGV[2] 窗口长度（毫秒）；ARGV[3] 上限；ARGV[4] 本次请求的唯一成员
+// 返回 {是否允许, 窗口内请求数, 距离最早的请求移出窗口的毫秒数}
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	redis.call("PEXPIRE", KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if #oldest > 0 then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// RateLimitResult 一次限流检查的结果
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int           // 窗口内剩余请求数
+	Reset     time.Duration // 距离窗口内最早的请求移出窗口的时长，被拒绝时即为需要等待的时长
+}
+
+// RateLimiter 基于 Redis 有序集合的滑动窗口限流器，多个实例共享计数
+type RateLimiter struct {
+	redis *redisdb.Client
+}
+
+func NewRateLimiter(redisClient *redisdb.Client) *RateLimiter {
+	return &RateLimiter{redis: redisClient}
+}
+
+// Allow 在 key 对应的窗口内计数一次请求，超过上限时不计数并返回 Allowed=false
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	member := make([]byte, 8)
+	if _, err := rand.Read(member); err != nil {
+		return nil, fmt.Errorf("failed to generate rate limit member: %w", err)
+	}
+	now := time.Now().UnixMilli()
+
+	values, err := slidingWindowScript.Run(ctx, l.redis, []string{rateLimitKeyPrefix + key},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+hex.EncodeToString(member)).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	return &RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: max(limit-int(values[1]), 0),
+		Reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+var rateLimiter *RateLimiter
+
+// SetRateLimiter 设置限流器（接口限流和 API Key 限流共用），未设置时不限流
+func SetRateLimiter(limiter *RateLimiter) {
+	rateLimiter = limiter
+}
+
+// rateLimitIdentity 按规则的身份类型取出计数身份
+// user、api_key 依赖认证中间件写入的 userId、apiKeyId，需放在 AuthMiddleware 之后；未认证时退回按 IP 计数
+func rateLimitIdentity(c *gin.Context, identity string) string {
+	switch identity {
+	case config.RateLimitByAPIKey:
+		if keyID := c.GetUint64("apiKeyId"); keyID != 0 {
+			return "key:" + strconv.FormatUint(keyID, 10)
+		}
+		fallthrough
+	case config.RateLimitByUser:
+		if userID := c.GetUint64("userId"); userID != 0 {
+			return "user:" + strconv.FormatUint(userID, 10)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// allowRequest 检查请求是否超过限额并设置限流响应头，超过时返回 429 并中止请求
+// Redis 不可用时放行，只记录日志
+func allowRequest(c *gin.Context, key string, limit int, window time.Duration, message string) bool {
+	result, err := rateLimiter.Allow(c.Request.Context(), key, limit, window)
+	if err != nil {
+		logger.Warn("rate limit check failed, allowing request: key=%s, error=%v", key, err)
+		return true
+	}
+
+	response.SetRateLimitHeaders(c, result.Limit, result.Remaining, result.Reset)
+	if !result.Allowed {
+		response.TooManyRequests(c, result.Reset, message)
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// RateLimit 路由组限流中间件，name 为路由组名称（同名路由组共享计数），规则的 Limit 为 0 时不限流
+func RateLimit(name string, rule config.RateLimitRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rateLimiter == nil || rule.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		key := name + ":" + rateLimitIdentity(c, rule.Identity)
+		if allowRequest(c, key, rule.Limit, rule.Window, "rate limit exceeded, please retry later") {
+			c.Next()
+		}
+	}
+}
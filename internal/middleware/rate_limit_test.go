@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/config"
+)
+
+func TestRateLimitIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		identity       string
+		userID         uint64
+		apiKeyID       uint64
+		trustedProxies []string
+		forwardedFor   string
+		want           string
+	}{
+		{name: "ip", identity: config.RateLimitByIP, userID: 7, apiKeyID: 3, want: "ip:192.0.2.10"},
+		{name: "user", identity: config.RateLimitByUser, userID: 7, apiKeyID: 3, want: "user:7"},
+		{name: "user unauthenticated", identity: config.RateLimitByUser, want: "ip:192.0.2.10"},
+		{name: "api key", identity: config.RateLimitByAPIKey, userID: 7, apiKeyID: 3, want: "key:3"},
+		{name: "api key falls back to user", identity: config.RateLimitByAPIKey, userID: 7, want: "user:7"},
+		{name: "api key falls back to ip", identity: config.RateLimitByAPIKey, want: "ip:192.0.2.10"},
+		{name: "untrusted forwarded for", identity: config.RateLimitByIP, forwardedFor: "203.0.113.5", want: "ip:192.0.2.10"},
+		{name: "trusted proxy forwarded for", identity: config.RateLimitByIP, trustedProxies: []string{"192.0.2.0/24"},
+			forwardedFor: "203.0.113.5", want: "ip:203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, engine := gin.CreateTestContext(httptest.NewRecorder())
+			if err := engine.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+			c.Request = httptest.NewRequest("GET", "/api/auctions", nil)
+			c.Request.RemoteAddr = "192.0.2.10:54321"
+			if tt.forwardedFor != "" {
+				c.Request.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.userID != 0 {
+				c.Set("userId", tt.userID)
+			}
+			if tt.apiKeyID != 0 {
+				c.Set("apiKeyId", tt.apiKeyID)
+			}
+
+			if got := rateLimitIdentity(c, tt.identity); got != tt.want {
+				t.Errorf("rateLimitIdentity(%q) = %q, want %q", tt.identity, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"my-auction-market-api/internal/response"
+)
+
+// RequireRole 角色校验（需在 AuthMiddleware 之后使用），用户角色来自 JWT，修改角色时会撤销该用户所有会话
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.GetUint64("userId") == 0 {
+			response.Unauthorized(c, "authentication required")
+			c.Abort()
+			return
+		}
+
+		if !allowed[c.GetString("role")] {
+			response.Forbidden(c, "insufficient permission")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix API Key 明文的固定前缀，格式：amk_<12位十六进制标识>_<64位十六进制密钥>
+const APIKeyPrefix = "amk_"
+
+// API Key 权限范围
+const (
+	APIKeyScopeReadAuctions  = "read:auctions"  // 查询自己的拍卖、模板、取消请求和任务状态
+	APIKeyScopeWriteAuctions = "write:auctions" // 创建、修改、取消拍卖和固定价格出售，提交密封出价
+	APIKeyScopeReadNFTs      = "read:nfts"      // 查询自己的 NFT 和报价
+	APIKeyScopeWriteNFTs     = "write:nfts"     // 同步 NFT，提交、接受、拒绝和取消报价
+)
+
+// APIKeyScopes 所有可分配的权限范围
+var APIKeyScopes = []string{
+	APIKeyScopeReadAuctions,
+	APIKeyScopeWriteAuctions,
+	APIKeyScopeReadNFTs,
+	APIKeyScopeWriteNFTs,
+}
+
+// APIKey 用户创建的 API Key，用于机器人、看板等程序化访问
+// 只保存 Key 的 SHA-256 哈希，明文只在创建时返回一次；KeyPrefix 用于识别和查找
+type APIKey struct {
+	ID         uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:ID"`
+	UserID     uint64     `json:"userId" gorm:"type:bigint(20) unsigned;not null;index:idx_api_keys_user_id;comment:用户ID"`
+	Name       string     `json:"name" gorm:"type:varchar(64);not null;comment:名称"`
+	KeyPrefix  string     `json:"keyPrefix" gorm:"type:varchar(20);not null;uniqueIndex:uk_api_keys_key_prefix;comment:Key 前缀(用于识别和查找)"`
+	KeyHash    string     `json:"-" gorm:"type:varchar(64);not null;comment:Key 的 SHA-256 哈希"`
+	Scopes     string     `json:"-" gorm:"type:varchar(255);not null;comment:权限范围(逗号分隔)"`
+	RateLimit  int        `json:"rateLimit" gorm:"type:int(11);not null;comment:每分钟最大请求数"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" gorm:"type:datetime;comment:最近使用时间"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty" gorm:"type:varchar(45);not null;default:'';comment:最近使用 IP"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" gorm:"type:datetime;comment:过期时间(为空表示不过期)"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" gorm:"type:datetime;comment:撤销时间"`
+	CreatedAt  *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt  *time.Time `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp;comment:更新时间"`
+}
+
+// ScopeList 权限范围列表
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// Active 是否可用（未撤销且未过期）
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyResponse API Key 信息（不含 Key 明文）
+type APIKeyResponse struct {
+	ID         uint64     `json:"id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"keyPrefix"` // Key 前缀，用于识别是哪个 Key
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rateLimit"` // 每分钟最大请求数
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+// NewAPIKeyResponse 转换为 API Key 响应
+func NewAPIKeyResponse(key *APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     key.ScopeList(),
+		RateLimit:  key.RateLimit,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// CreateAPIKeyPayload 创建 API Key 的请求体
+type CreateAPIKeyPayload struct {
+	Name      string     `json:"name" binding:"required,max=64"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`     // 权限范围：read:auctions、write:auctions、read:nfts、write:nfts
+	RateLimit int        `json:"rateLimit" binding:"omitempty,min=1"` // 每分钟最大请求数，默认 api_key.default_rate_limit
+	ExpiresAt *time.Time `json:"expiresAt"`                           // 过期时间（可选）
+}
+
+// CreateAPIKeyResult 创建 API Key 的结果，Key 明文只返回这一次
+type CreateAPIKeyResult struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyPrincipal 通过 API Key 认证的调用方
+type APIKeyPrincipal struct {
+	KeyID     uint64
+	UserID    uint64
+	Username  string
+	Email     string
+	Role      string
+	Scopes    []string
+	RateLimit int
+}
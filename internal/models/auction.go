@@ -8,6 +8,21 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// 拍卖类型常量
+const (
+	AuctionTypeEnglish = "english" // 英式拍卖（价高者得）
+	AuctionTypeDutch   = "dutch"   // 荷兰式拍卖（价格从起拍价递减到底价，首个达到当前价的出价成交）
+	AuctionTypeSealed  = "sealed"  // 密封拍卖（提交-揭示，最高价者以第二高价成交）
+	AuctionTypeFixed   = "fixed"   // 固定价格出售（首个达到标价的链上出价直接成交）
+)
+
+// 密封拍卖阶段常量
+const (
+	SealedPhaseCommit     = "commit"     // 提交承诺阶段（StartTime ~ CommitEndTime）
+	SealedPhaseReveal     = "reveal"     // 揭示阶段（CommitEndTime ~ RevealEndTime）
+	SealedPhaseSettlement = "settlement" // 结算阶段（RevealEndTime ~ EndTime，获胜者链上按成交价出价）
+)
+
 type Auction struct {
 	ID                     uint64           `json:"-" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned"`
 	AuctionID              string           `json:"auctionId" gorm:"type:varchar(50);uniqueIndex:auction_id;comment:拍卖ID(使用snowflake生成ID，避免自增ID)"`
@@ -24,7 +39,8 @@ type Auction struct {
 	Image                  string           `json:"image" gorm:"type:text;comment:NFT图片URL"`
 	Description            string           `json:"description" gorm:"type:text;comment:NFT描述"`
 	Metadata               string           `json:"metadata" gorm:"type:longtext;comment:完整元数据JSON"`
-	Status                 string           `json:"status" gorm:"type:varchar(20);not null;default:'pending';index:idx_auctions_status;comment:状态(pending,active,ended,cancelled)"`
+	AuctionType            string           `json:"auctionType" gorm:"type:varchar(20);not null;default:'english';index:idx_auctions_type;comment:拍卖类型(english,dutch,sealed,fixed)"`
+	Status                 string           `json:"status" gorm:"type:varchar(20);not null;default:'pending';index:idx_auctions_status;comment:状态(draft,pending,upcoming,active,ended,cancelled,expired)"`
 	OnlineLock             string           `json:"onlineLock" gorm:"column:online_lock;type:varchar(76);uniqueIndex:nft_online_id;comment:NFT在线标志 nft_id:1,也作为一个锁字段，解锁就改成其他值"`
 	Online                 uint64           `json:"online" gorm:"type:bigint(20);index:online;comment:1表示在线 其他值表示下线"`
 	StartTime              *time.Time       `json:"startTime" gorm:"type:datetime;not null;comment:开始时间"`
@@ -34,7 +50,24 @@ type Auction struct {
 	StartPrice             *decimal.Decimal `json:"startPrice" gorm:"type:decimal(65,30);comment:起拍价(单位由PaymentToken指定:0x0=ETH,其他=ERC20代币)"`
 	PaymentToken           string           `json:"paymentToken" gorm:"type:varchar(42);comment:起拍价链上交易代币地址(0x0表示ETH,其他地址表示ERC20代币)"`
 	StartPriceUSD          *decimal.Decimal `json:"startPriceUSD" gorm:"type:decimal(65,30);comment:起拍价USD"`
-	StartPriceUnitUSD      uint64           `json:"startPriceUnitUSD" gorm:"column:start_price_unit_usd;type:bigint(20);comment:起拍价USD预言机价格（小数点起拍价USD*10**8）"`
+	StartPriceUnitUSD      uint64           `json:"startPriceUnitUSD" gorm:"column:start_price_unit_usd;type:bigint(20);comment:起拍价USD预言机价格（小数点起拍价USD*10**8，作为合约 createAuction 的起拍价，荷兰式拍卖按底价换算）"`
+	FloorPrice             *decimal.Decimal `json:"floorPrice,omitempty" gorm:"type:decimal(65,30);comment:荷兰式拍卖底价(单位由PaymentToken指定)"`
+	PriceDecayInterval     uint64           `json:"priceDecayInterval" gorm:"type:bigint(20) unsigned;not null;default:0;comment:荷兰式拍卖降价间隔(秒,0表示连续降价)"`
+	ReservePrice           *decimal.Decimal `json:"reservePrice,omitempty" gorm:"type:decimal(65,30);comment:保留价(英式和密封拍卖,单位由PaymentToken指定,最高出价低于保留价时不成交)"`
+	BidIncrementPercent    uint64           `json:"bidIncrementPercent" gorm:"type:bigint(20) unsigned;not null;default:0;comment:英式拍卖最小加价幅度(相对上一个最高出价的百分比,0表示不限制)"`
+	CommitEndTime          *time.Time       `json:"commitEndTime,omitempty" gorm:"type:datetime;comment:密封拍卖提交承诺截止时间"`
+	RevealEndTime          *time.Time       `json:"revealEndTime,omitempty" gorm:"type:datetime;comment:密封拍卖揭示截止时间"`
+	SealedWinner           string           `json:"sealedWinner,omitempty" gorm:"type:varchar(42);comment:密封拍卖获胜者地址(揭示结束后确定)"`
+	SealedPaymentToken     string           `json:"sealedPaymentToken,omitempty" gorm:"type:varchar(42);comment:密封拍卖获胜者出价代币地址"`
+	SealedClearingPrice    *decimal.Decimal `json:"sealedClearingPrice,omitempty" gorm:"type:decimal(65,30);comment:密封拍卖成交价(第二高价,单位由SealedPaymentToken指定)"`
+	SealedClearingPriceUSD *decimal.Decimal `json:"sealedClearingPriceUSD,omitempty" gorm:"type:decimal(65,30);comment:密封拍卖成交价USD"`
+	AutoRelist             bool             `json:"autoRelist" gorm:"type:tinyint(1);not null;default:0;comment:流拍后是否自动重新上架"`
+	RelistDuration         uint64           `json:"relistDuration" gorm:"type:bigint(20) unsigned;not null;default:0;comment:重新上架的拍卖时长(秒,0表示沿用原时长)"`
+	RelistPriceReduction   uint64           `json:"relistPriceReduction" gorm:"type:bigint(20) unsigned;not null;default:0;comment:重新上架的起拍价下调百分比(0-90)"`
+	RelistGeneration       uint64           `json:"relistGeneration" gorm:"type:bigint(20) unsigned;not null;default:0;comment:重新上架代数(0表示首次上架)"`
+	RelistedFrom           string           `json:"relistedFrom,omitempty" gorm:"type:varchar(50);index:idx_auctions_relisted_from;comment:前一代拍卖ID(自动重新上架时设置)"`
+	BundleSize             uint64           `json:"bundleSize" gorm:"type:bigint(20) unsigned;not null;default:0;comment:打包拍卖的NFT数量(0表示单个NFT拍卖)"`
+	Visibility             string           `json:"visibility" gorm:"type:varchar(20);not null;default:'public';index:idx_auctions_visibility;comment:可见性(public,unlisted,allowlist)"`
 	HighestBidder          string           `json:"highestBidder" gorm:"type:varchar(42);comment:最高出价者地址"`
 	HighestBidPaymentToken string           `json:"highestBidPaymentToken" gorm:"type:varchar(42);comment:最高出价使用链上交易代币地址(0x0=ETH,其他=ERC20代币,可能与拍卖PaymentToken不同)"`
 	HighestBid             *decimal.Decimal `json:"highestBid" gorm:"type:decimal(65,30) unsigned;comment:最高出价金额(单位由HighestBidPaymentToken指定,可能与拍卖PaymentToken不同)"`
@@ -46,6 +79,10 @@ type Auction struct {
 
 	User User  `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT"`
 	Bids []Bid `json:"bids,omitempty" gorm:"foreignKey:AuctionID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT"`
+
+	BundleItems []AuctionBundleItem `json:"bundleItems,omitempty" gorm:"foreignKey:AuctionID;references:AuctionID"` // 打包拍卖的全部 NFT（按 Position 排序）
+
+	Outcome string `json:"outcome,omitempty" gorm:"-"` // 已结束时的出售结果（sold/purchased/unsold），查询时计算，不入库
 }
 
 // IsETH 判断支付代币是否为ETH
@@ -73,22 +110,180 @@ func (a *Auction) IsBidTokenSameAsAuction(bidPaymentToken string) bool {
 	return a.PaymentToken == bidPaymentToken
 }
 
+// IsEnglish 判断是否为英式拍卖（未设置类型的历史拍卖按英式处理）
+func (a *Auction) IsEnglish() bool {
+	return a.AuctionType == AuctionTypeEnglish || a.AuctionType == ""
+}
+
+// IsDutch 判断是否为荷兰式拍卖
+func (a *Auction) IsDutch() bool {
+	return a.AuctionType == AuctionTypeDutch
+}
+
+// IsSealed 判断是否为密封拍卖
+func (a *Auction) IsSealed() bool {
+	return a.AuctionType == AuctionTypeSealed
+}
+
+// IsFixedPrice 判断是否为固定价格出售
+func (a *Auction) IsFixedPrice() bool {
+	return a.AuctionType == AuctionTypeFixed
+}
+
+// IsInstantSale 首个达到当前价格的链上出价即成交（荷兰式拍卖和固定价格出售）
+func (a *Auction) IsInstantSale() bool {
+	return a.IsDutch() || a.IsFixedPrice()
+}
+
+// SaleType 出售方式：固定价格出售为 listing，其余为 auction
+func (a *Auction) SaleType() string {
+	if a.IsFixedPrice() {
+		return SaleTypeListing
+	}
+	return SaleTypeAuction
+}
+
+// IsPrivate 判断是否为非公开拍卖（unlisted 或 allowlist，不出现在拍卖列表中）
+func (a *Auction) IsPrivate() bool {
+	return a.Visibility == AuctionVisibilityUnlisted || a.Visibility == AuctionVisibilityAllowlist
+}
+
+// RequiresMembership 判断是否为邀请制拍卖（仅卖家和白名单成员可访问）
+func (a *Auction) RequiresMembership() bool {
+	return a.Visibility == AuctionVisibilityAllowlist
+}
+
+// SealedPhase 返回密封拍卖在指定时刻所处的阶段，不在任何阶段时返回空字符串
+func (a *Auction) SealedPhase(now time.Time) string {
+	if !a.IsSealed() || a.StartTime == nil || a.CommitEndTime == nil || a.RevealEndTime == nil || a.EndTime == nil {
+		return ""
+	}
+	switch {
+	case now.Before(*a.StartTime):
+		return ""
+	case now.Before(*a.CommitEndTime):
+		return SealedPhaseCommit
+	case now.Before(*a.RevealEndTime):
+		return SealedPhaseReveal
+	case now.Before(*a.EndTime):
+		return SealedPhaseSettlement
+	default:
+		return ""
+	}
+}
+
+// CurrentPrice 计算指定时刻的当前价格
+// 英式拍卖始终返回起拍价；荷兰式拍卖在开始前为起拍价，结束后为底价，
+// 期间按 PriceDecayInterval 分段（0 表示连续）从起拍价线性递减到底价
+func (a *Auction) CurrentPrice(now time.Time) decimal.Decimal {
+	if a.StartPrice == nil {
+		return decimal.Zero
+	}
+	if !a.IsDutch() || a.FloorPrice == nil || a.StartTime == nil || a.EndTime == nil {
+		return *a.StartPrice
+	}
+	if !now.After(*a.StartTime) {
+		return *a.StartPrice
+	}
+	if !now.Before(*a.EndTime) {
+		return *a.FloorPrice
+	}
+
+	total := int64(a.EndTime.Sub(*a.StartTime) / time.Second)
+	elapsed := int64(now.Sub(*a.StartTime) / time.Second)
+	if total <= 0 {
+		return *a.FloorPrice
+	}
+	if a.PriceDecayInterval > 0 {
+		step := int64(a.PriceDecayInterval)
+		elapsed = elapsed / step * step
+	}
+
+	drop := a.StartPrice.Sub(*a.FloorPrice).Mul(decimal.NewFromInt(elapsed)).Div(decimal.NewFromInt(total))
+	return a.StartPrice.Sub(drop)
+}
+
+// CurrentPriceUSD 按起拍价USD等比例换算当前价格的USD价值
+func (a *Auction) CurrentPriceUSD(now time.Time) decimal.Decimal {
+	if a.StartPriceUSD == nil || a.StartPrice == nil || a.StartPrice.IsZero() {
+		return decimal.Zero
+	}
+	return a.StartPriceUSD.Mul(a.CurrentPrice(now)).Div(*a.StartPrice)
+}
+
+// ReservePriceUSD 按起拍价USD等比例换算保留价的USD价值（未设置保留价时返回 0）
+func (a *Auction) ReservePriceUSD() decimal.Decimal {
+	if a.ReservePrice == nil || a.StartPriceUSD == nil || a.StartPrice == nil || a.StartPrice.IsZero() {
+		return decimal.Zero
+	}
+	return a.StartPriceUSD.Mul(*a.ReservePrice).Div(*a.StartPrice)
+}
+
+// NextPriceDropAt 荷兰式拍卖下一次降价的时间（连续降价或已到底价时返回 nil）
+func (a *Auction) NextPriceDropAt(now time.Time) *time.Time {
+	if !a.IsDutch() || a.PriceDecayInterval == 0 || a.StartTime == nil || a.EndTime == nil {
+		return nil
+	}
+	if !now.Before(*a.EndTime) {
+		return nil
+	}
+	step := time.Duration(a.PriceDecayInterval) * time.Second
+	next := *a.StartTime
+	if now.After(*a.StartTime) {
+		next = a.StartTime.Add((now.Sub(*a.StartTime)/step + 1) * step)
+	}
+	if next.After(*a.EndTime) {
+		next = *a.EndTime
+	}
+	return &next
+}
+
 // CompareBids 比较两个出价的USD价值(用于判断哪个出价更高)
 // 返回: 1表示bid1更高, -1表示bid2更高, 0表示相等
 func CompareBids(bid1AmountUSD, bid2AmountUSD decimal.Decimal) int {
 	return bid1AmountUSD.Cmp(bid2AmountUSD)
 }
 
+// AuctionTypeParams 拍卖类型相关参数（创建和更新拍卖共用）
+type AuctionTypeParams struct {
+	FloorPrice          *decimal.Decimal `json:"floorPrice"`                                      // 荷兰式拍卖底价（dutch 必填）
+	PriceDecayInterval  uint64           `json:"priceDecayInterval"`                              // 荷兰式拍卖降价间隔（秒，0表示连续降价）
+	CommitEndTime       *time.Time       `json:"commitEndTime"`                                   // 密封拍卖提交承诺截止时间（sealed 必填）
+	RevealEndTime       *time.Time       `json:"revealEndTime"`                                   // 密封拍卖揭示截止时间（sealed 必填，之后到 endTime 为结算阶段）
+	ReservePrice        *decimal.Decimal `json:"reservePrice"`                                    // 保留价（english、sealed 可选，不低于起拍价；最高出价低于保留价时不成交）
+	BidIncrementPercent uint64           `json:"bidIncrementPercent" binding:"omitempty,max=100"` // 英式拍卖最小加价幅度（相对上一个最高出价的百分比，0表示不限制）
+}
+
+// RelistParams 流拍自动重新上架设置
+type RelistParams struct {
+	AutoRelist           bool   `json:"autoRelist"`                                      // 流拍（无人出价）后是否自动重新上架
+	RelistDuration       uint64 `json:"relistDuration" binding:"omitempty,min=60"`       // 重新上架的拍卖时长（秒，0表示沿用原时长）
+	RelistPriceReduction uint64 `json:"relistPriceReduction" binding:"omitempty,max=90"` // 重新上架的起拍价下调百分比（0-90）
+}
+
 type AuctionPayload struct {
-	NFTID        string          `json:"nftId" binding:"required"` // NFT唯一标识（从前端传入）
-	NFTAddress   string          `json:"nftAddress" binding:"required"`
-	TokenID      uint64          `json:"tokenId" binding:"required"`
-	PaymentToken string          `json:"paymentToken" binding:"required"` // 支付代币地址(0x0表示ETH,其他表示ERC20代币)
-	StartPrice   decimal.Decimal `json:"startPrice" binding:"required"`   // 起拍价(单位由PaymentToken指定)
-	StartTime    *time.Time      `json:"startTime" binding:"required"`    // ISO 8601 格式
-	EndTime      *time.Time      `json:"endTime" binding:"required"`      // ISO 8601 格式
+	NFTID        string             `json:"nftId" binding:"required"` // NFT唯一标识（从前端传入）
+	NFTAddress   string             `json:"nftAddress" binding:"required"`
+	TokenID      uint64             `json:"tokenId" binding:"required"`
+	AuctionType  string             `json:"auctionType" binding:"omitempty,oneof=english dutch sealed"` // 拍卖类型(english,dutch,sealed)，默认 english
+	PaymentToken string             `json:"paymentToken" binding:"required_without=TemplateID"`         // 支付代币地址(0x0表示ETH,其他表示ERC20代币)
+	StartPrice   decimal.Decimal    `json:"startPrice" binding:"required_without=TemplateID"`           // 起拍价(单位由PaymentToken指定)
+	StartTime    *time.Time         `json:"startTime" binding:"required"`                               // ISO 8601 格式
+	EndTime      *time.Time         `json:"endTime" binding:"required_without=TemplateID"`              // ISO 8601 格式（使用模板时默认为 startTime + 模板时长）
+	BundleNFTs   []BundleNFTPayload `json:"bundleNfts" binding:"omitempty,max=9,dive"`                  // 打包拍卖的其他成员 NFT（为空表示单个 NFT 拍卖，最多 9 个）
+	TemplateID   uint64             `json:"templateId"`                                                 // 拍卖模板ID（可选，未传入的字段从模板填充）
+	Draft        bool               `json:"draft"`                                                      // 是否保存为草稿（不占用 NFT 在线锁，提交后才进入 pending）
+	AuctionTypeParams
+	RelistParams
+	VisibilityParams
 }
 
+// 出价被标记的原因（合约无法拒绝这些出价，由后端标记，结算时链上最高出价被标记的拍卖不成交）
+const (
+	BidFlagNotAllowlisted    = "not_allowlisted"     // 邀请制拍卖中非白名单钱包的出价
+	BidFlagBelowBidIncrement = "below_bid_increment" // 英式拍卖中未达到最小加价幅度的出价
+)
+
 type Bid struct {
 	ID                uint64           `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:出价ID"`
 	AuctionID         string           `json:"auctionId" gorm:"type:varchar(50);comment:拍卖ID"`
@@ -106,6 +301,8 @@ type Bid struct {
 	Timestamp         uint64           `json:"timestamp" gorm:"type:bigint(20) unsigned;comment:链上时间"`
 	BidCount          uint64           `json:"bidCount" gorm:"type:bigint(20);comment:出价总数"`
 	IsHighest         bool             `json:"isHighest" gorm:"type:tinyint(1);default:0;index:idx_bids_is_highest;comment:是否为最高出价"`
+	Flagged           bool             `json:"flagged" gorm:"type:tinyint(1);not null;default:0;comment:是否被标记(邀请制拍卖中非白名单钱包的出价、英式拍卖中未达到加价幅度的出价)"`
+	FlagReason        string           `json:"flagReason,omitempty" gorm:"type:varchar(32);not null;default:'';comment:标记原因(not_allowlisted,below_bid_increment)"`
 	MinBidder         string           `json:"minBidder" gorm:"type:varchar(42);comment:上一个最高出价值地址（当前出价起码要超过的最小金额地址）"`
 	MinBidUnitUSD     uint64           `json:"minBidUnitUSD" gorm:"column:min_bid_unit_usd;type:bigint(20);comment:上一个最高出价值（当前出价起码要超过的最小金额数）"`
 	CreatedAt         *time.Time       `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;index:idx_bids_created_at;comment:创建时间"`
@@ -133,6 +330,8 @@ type UpdateAuctionPayload struct {
 	StartPrice   decimal.Decimal `json:"startPrice" binding:"required"`   // 起拍价(单位由PaymentToken指定)
 	StartTime    *time.Time      `json:"startTime" binding:"required"`    // ISO 8601 格式
 	EndTime      *time.Time      `json:"endTime" binding:"required"`      // ISO 8601 格式
+	AuctionTypeParams
+	RelistParams
 }
 
 // ConvertToUSDPayload 转换金额为美元的请求体
@@ -195,12 +394,28 @@ type AuctionNFTItem struct {
 	TokenURI       string `json:"tokenURI"`       // Token URI
 	Description    string `json:"description"`    // NFT描述
 	AuctionCount   int64  `json:"auctionCount"`   // 该NFT的拍卖数量
+	AuctionType    string `json:"auctionType"`    // 最近一次出售的拍卖类型（fixed 表示固定价格出售）
+	SaleType       string `json:"saleType"`       // 最近一次出售的方式（auction/listing）
 }
 
 // AuctionDetailResponse 拍卖详情响应（只包含钱包地址，不包含完整User信息）
 type AuctionDetailResponse struct {
 	Auction
-	SellerWalletAddress string `json:"sellerWalletAddress"` // 卖家钱包地址（只返回钱包地址，不返回完整User信息）
+	SellerWalletAddress string           `json:"sellerWalletAddress"`       // 卖家钱包地址（只返回钱包地址，不返回完整User信息）
+	CurrentPrice        *decimal.Decimal `json:"currentPrice,omitempty"`    // 当前价格（荷兰式拍卖按时间递减）
+	CurrentPriceUSD     *decimal.Decimal `json:"currentPriceUSD,omitempty"` // 当前价格USD
+	NextPriceDropAt     *time.Time       `json:"nextPriceDropAt,omitempty"` // 下一次降价时间（荷兰式拍卖）
+	SealedPhase         string           `json:"sealedPhase,omitempty"`     // 密封拍卖当前阶段(commit,reveal,settlement)
+}
+
+// AuctionPriceTick 荷兰式拍卖价格推送（WebSocket 每个拍卖房间定时推送）
+type AuctionPriceTick struct {
+	AuctionID       string          `json:"auctionId"`                 // 拍卖ID
+	PaymentToken    string          `json:"paymentToken"`              // 支付代币地址
+	CurrentPrice    decimal.Decimal `json:"currentPrice"`              // 当前价格
+	CurrentPriceUSD decimal.Decimal `json:"currentPriceUSD"`           // 当前价格USD
+	FloorPrice      decimal.Decimal `json:"floorPrice"`                // 底价
+	NextPriceDropAt *time.Time      `json:"nextPriceDropAt,omitempty"` // 下一次降价时间
 }
 
 // BidDetailResponse 出价详情响应（只包含钱包地址，不包含完整User信息）
@@ -227,6 +442,8 @@ type BidResponse struct {
 	Timestamp          uint64     `json:"timestamp"`         // 链上时间
 	BidCount           uint64     `json:"bidCount"`         // 出价总数
 	IsHighest          bool       `json:"isHighest"`         // 是否为最高出价
+	Flagged            bool       `json:"flagged"`           // 是否被标记（邀请制拍卖中非白名单钱包的出价、英式拍卖中未达到加价幅度的出价）
+	FlagReason         string     `json:"flagReason,omitempty"` // 标记原因(not_allowlisted,below_bid_increment)
 	MinBidder          string     `json:"minBidder"`        // 上一个最高出价者地址
 	MinBidUnitUSD      uint64     `json:"minBidUnitUSD"`    // 上一个最高出价值（USD最小单位）
 	CreatedAt          *time.Time `json:"createdAt"`         // 创建时间
@@ -253,4 +470,6 @@ type AuctionHistoryResponse struct {
 	HighestBidUSD          *decimal.Decimal `json:"highestBidUSD,omitempty"`          // 当前最高价USD（如果有出价）
 	HighestBidder          string           `json:"highestBidder,omitempty"`          // 出价人地址（如果有出价）
 	HighestBidPaymentToken string           `json:"highestBidPaymentToken,omitempty"` // 最高出价使用的代币地址
+	RelistGeneration       uint64           `json:"relistGeneration"`                 // 重新上架代数（0表示首次上架）
+	RelistedFrom           string           `json:"relistedFrom,omitempty"`           // 前一代拍卖ID（自动重新上架时设置）
 }
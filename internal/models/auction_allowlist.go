@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// 拍卖可见性常量
+const (
+	AuctionVisibilityPublic    = "public"    // 公开：出现在拍卖列表中，任何人可查看和订阅
+	AuctionVisibilityUnlisted  = "unlisted"  // 不公开：不出现在拍卖列表中，知道链接的人可查看
+	AuctionVisibilityAllowlist = "allowlist" // 邀请制：不出现在拍卖列表中，仅卖家和白名单成员可查看和订阅
+)
+
+// AuctionAllowlistEntry 邀请制拍卖的白名单成员（按钱包地址或用户ID匹配，二者填其一）
+type AuctionAllowlistEntry struct {
+	ID            uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned"`
+	AuctionID     string     `json:"auctionId" gorm:"type:varchar(50);not null;uniqueIndex:uk_auction_allowlists_member,priority:1;comment:拍卖ID"`
+	WalletAddress string     `json:"walletAddress,omitempty" gorm:"type:varchar(42);not null;default:'';uniqueIndex:uk_auction_allowlists_member,priority:2;index:idx_auction_allowlists_wallet;comment:白名单钱包地址(小写)"`
+	UserID        uint64     `json:"userId,omitempty" gorm:"type:bigint(20) unsigned;not null;default:0;uniqueIndex:uk_auction_allowlists_member,priority:3;comment:白名单用户ID"`
+	CreatedAt     *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+}
+
+// VisibilityParams 拍卖可见性设置（创建拍卖时使用）
+type VisibilityParams struct {
+	Visibility       string   `json:"visibility" binding:"omitempty,oneof=public unlisted allowlist"` // 可见性(public,unlisted,allowlist)，默认 public
+	AllowlistWallets []string `json:"allowlistWallets" binding:"omitempty,max=1000"`                  // 白名单钱包地址（visibility 为 allowlist 时有效）
+	AllowlistUserIDs []uint64 `json:"allowlistUserIds" binding:"omitempty,max=1000"`                  // 白名单用户ID（visibility 为 allowlist 时有效）
+}
+
+// AuctionAllowlistResponse 拍卖可见性和白名单（卖家查看）
+type AuctionAllowlistResponse struct {
+	AuctionID  string                  `json:"auctionId"`  // 拍卖ID
+	Visibility string                  `json:"visibility"` // 可见性
+	Entries    []AuctionAllowlistEntry `json:"entries"`    // 白名单成员
+}
@@ -0,0 +1,31 @@
+package models
+
+// BatchAuctionPayload 批量创建拍卖的请求体（每项与单个创建拍卖的请求体相同，不支持打包拍卖）
+type BatchAuctionPayload struct {
+	Items []AuctionPayload `json:"items" binding:"required,min=1,max=50,dive"` // 拍卖列表（最多 50 个）
+}
+
+// BatchAuctionResult 批量创建中单个拍卖的结果
+type BatchAuctionResult struct {
+	Index         int      `json:"index"`             // 在请求 items 中的下标
+	NFTID         string   `json:"nftId"`             // NFT唯一标识
+	Success       bool     `json:"success"`           // 是否创建成功
+	Auction       *Auction `json:"auction,omitempty"` // 创建成功的拍卖
+	Error         string   `json:"error,omitempty"`   // 失败原因
+	NeedsApproval bool     `json:"needsApproval"`     // 是否因未授权给平台合约而失败
+}
+
+// BatchApprovalItem 仍需授权给平台合约的 NFT
+type BatchApprovalItem struct {
+	NFTID      string `json:"nftId"`      // NFT唯一标识
+	NFTAddress string `json:"nftAddress"` // NFT合约地址
+	TokenID    uint64 `json:"tokenId"`    // Token ID
+}
+
+// BatchAuctionResponse 批量创建拍卖的结果
+type BatchAuctionResponse struct {
+	Results       []BatchAuctionResult `json:"results"`       // 每个拍卖的结果（与请求 items 顺序一致）
+	Created       int                  `json:"created"`       // 创建成功数量
+	Failed        int                  `json:"failed"`        // 失败数量
+	NeedsApproval []BatchApprovalItem  `json:"needsApproval"` // 仍需授权给平台合约的 NFT（授权后可重新提交）
+}
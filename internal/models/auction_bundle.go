@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// 打包成员转移状态（成交后由平台签名账户把主 NFT 以外的成员从卖家钱包转给获胜者）
+const (
+	BundleDeliveryPending   = "pending"   // 拍卖已成交，等待发送转移交易
+	BundleDeliverySubmitted = "submitted" // 转移交易已发送，等待回执
+	BundleDeliveryDelivered = "delivered" // 已转给获胜者
+	BundleDeliveryFailed    = "failed"    // 转移失败（卖家撤销授权或转走 NFT、交易回滚或等待回执超时）
+)
+
+// AuctionBundleItem 打包拍卖中的单个 NFT
+// 打包拍卖以主 NFT（Position 为 0，即 Auction.NFTID）在链上创建拍卖，其余成员 NFT 由后端统一管理状态。
+// 每个成员与主 NFT 一样在 nft_locks 中加锁（NFTLock），保证同一 NFT 同时只在一个拍卖中；
+// 成交时合约只转移托管的主 NFT，其余成员授权给平台签名账户，由平台转给获胜者（DeliveryStatus），转移完成或失败后释放锁。
+type AuctionBundleItem struct {
+	ID             uint64     `json:"-" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned"`
+	AuctionID      string     `json:"auctionId" gorm:"type:varchar(50);not null;index:idx_auction_bundle_items_auction_id;comment:拍卖ID"`
+	Position       uint64     `json:"position" gorm:"type:bigint(20) unsigned;not null;default:0;comment:在打包中的顺序(0为链上拍卖的主NFT)"`
+	NFTID          string     `json:"nftId" gorm:"type:varchar(64);not null;comment:NFT唯一标识"`
+	NFTAddress     string     `json:"nftAddress" gorm:"type:varchar(42);not null;comment:NFT合约地址"`
+	TokenID        uint64     `json:"tokenId" gorm:"type:bigint(20) unsigned;not null;comment:NFT的Token ID"`
+	ContractName   string     `json:"contractName" gorm:"type:varchar(255);comment:合约名称"`
+	ContractSymbol string     `json:"contractSymbol" gorm:"type:varchar(64);comment:合约符号"`
+	TokenURI       string     `json:"tokenURI" gorm:"type:text;comment:Token URI"`
+	NftName        string     `json:"nftName" gorm:"type:varchar(255);comment:NFT名称"`
+	Image          string     `json:"image" gorm:"type:text;comment:NFT图片URL"`
+	Description    string     `json:"description" gorm:"type:text;comment:NFT描述"`
+	DeliveryStatus string     `json:"deliveryStatus,omitempty" gorm:"type:varchar(20);not null;default:'';index:idx_auction_bundle_items_delivery_status;comment:成员转移状态(pending,submitted,delivered,failed)，主NFT由合约转移为空"`
+	DeliveryTxHash string     `json:"deliveryTxHash,omitempty" gorm:"type:varchar(66);not null;default:'';comment:成员转移交易哈希"`
+	DeliveryError  string     `json:"deliveryError,omitempty" gorm:"type:varchar(255);not null;default:'';comment:成员转移失败原因"`
+	CreatedAt      *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+}
+
+// BundleNFTPayload 打包拍卖的成员 NFT（不包含主 NFT）
+type BundleNFTPayload struct {
+	NFTID      string `json:"nftId" binding:"required"`
+	NFTAddress string `json:"nftAddress" binding:"required"`
+	TokenID    uint64 `json:"tokenId" binding:"required"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// 拍卖取消方式
+const (
+	CancellationModeSeller = "seller" // 卖家签名合约 cancelUserAuction
+	CancellationModeAdmin  = "admin"  // 管理员通过平台私钥调用合约 cancelAuction
+	CancellationModeSystem = "system" // 结算校验不通过时平台私钥调用合约 cancelAuction
+)
+
+// 拍卖取消请求状态
+const (
+	CancellationStatusRequested = "requested" // 卖家取消：已生成待签名交易，等待卖家提交交易哈希；平台取消：交易发送前写入
+	CancellationStatusSubmitted = "submitted" // 交易已发送，等待 AuctionCancelled 事件
+	CancellationStatusConfirmed = "confirmed" // 已收到 AuctionCancelled 事件，拍卖已取消
+	CancellationStatusFailed    = "failed"    // 交易发送失败、执行失败或等待回执超时（可重新发起取消）
+)
+
+// AuctionCancellation 已上链拍卖的取消请求
+// 卖家取消：后端生成 cancelUserAuction 待签名交易，卖家签名发送后提交交易哈希；
+// 管理员取消：后端先写入取消请求再使用平台私钥发送 cancelAuction；两种方式都跟踪交易回执（服务重启后恢复跟踪），收到 AuctionCancelled 事件后确认
+type AuctionCancellation struct {
+	ID                uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:取消请求ID"`
+	AuctionID         string     `json:"auctionId" gorm:"type:varchar(50);not null;index:idx_auction_cancellations_auction_id;comment:拍卖ID"`
+	ContractAuctionID uint64     `json:"contractAuctionId" gorm:"type:bigint(20) unsigned;not null;comment:合约拍卖ID"`
+	Mode              string     `json:"mode" gorm:"type:varchar(20);not null;comment:取消方式(seller,admin,system)"`
+	RequestedBy       uint64     `json:"requestedBy" gorm:"type:bigint(20) unsigned;not null;comment:发起取消的用户ID"`
+	Reason            string     `json:"reason" gorm:"type:varchar(255);not null;default:'';comment:取消原因"`
+	Status            string     `json:"status" gorm:"type:varchar(20);not null;default:'requested';index:idx_auction_cancellations_status;comment:状态(requested,submitted,confirmed,failed)"`
+	TxHash            string     `json:"txHash,omitempty" gorm:"type:varchar(66);not null;default:'';comment:取消交易哈希"`
+	Error             string     `json:"error,omitempty" gorm:"type:varchar(255);not null;default:'';comment:失败原因"`
+	CreatedAt         *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt         *time.Time `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp on update current_timestamp;comment:更新时间"`
+}
+
+// UnsignedTransaction 待用户钱包签名发送的合约交易
+type UnsignedTransaction struct {
+	From    string `json:"from"`    // 发送方钱包地址
+	To      string `json:"to"`      // 合约地址
+	Data    string `json:"data"`    // 调用数据（0x 开头）
+	Value   string `json:"value"`   // 转账金额（wei）
+	ChainID int64  `json:"chainId"` // 链ID
+	Method  string `json:"method"`  // 合约方法名
+}
+
+// CancelAuctionResponse 取消拍卖的结果
+// 未上链的拍卖直接取消；已上链的拍卖返回取消请求（卖家取消时附带待签名交易）
+type CancelAuctionResponse struct {
+	Auction      *Auction             `json:"auction"`
+	Cancellation *AuctionCancellation `json:"cancellation,omitempty"`
+	Transaction  *UnsignedTransaction `json:"transaction,omitempty"`
+}
+
+// SubmitCancelTxPayload 卖家提交已发送的取消交易哈希
+type SubmitCancelTxPayload struct {
+	TxHash string `json:"txHash" binding:"required"` // cancelUserAuction 交易哈希
+}
+
+// AdminCancelAuctionPayload 管理员取消拍卖
+type AdminCancelAuctionPayload struct {
+	Reason string `json:"reason" binding:"required,max=255"` // 取消原因（通知出价者和卖家）
+}
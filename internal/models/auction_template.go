@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AuctionTemplate 卖家保存的拍卖模板（创建拍卖时通过 templateId 引用，请求中显式传入的字段优先）
+// 时间相关参数保存为相对开始时间的时长，创建拍卖时按 startTime 计算
+type AuctionTemplate struct {
+	ID                   uint64           `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:模板ID"`
+	UserID               uint64           `json:"userId" gorm:"type:bigint(20) unsigned;not null;index:idx_auction_templates_user_id;comment:卖家ID"`
+	Name                 string           `json:"name" gorm:"type:varchar(100);not null;comment:模板名称"`
+	AuctionType          string           `json:"auctionType" gorm:"type:varchar(20);not null;default:'english';comment:拍卖类型(english,dutch,sealed)"`
+	PaymentToken         string           `json:"paymentToken" gorm:"type:varchar(42);not null;comment:支付代币地址"`
+	StartPrice           decimal.Decimal  `json:"startPrice" gorm:"type:decimal(65,30);not null;comment:起拍价(单位由PaymentToken指定)"`
+	Duration             uint64           `json:"duration" gorm:"type:bigint(20) unsigned;not null;comment:拍卖时长(秒)"`
+	FloorPrice           *decimal.Decimal `json:"floorPrice,omitempty" gorm:"type:decimal(65,30);comment:荷兰式拍卖底价"`
+	PriceDecayInterval   uint64           `json:"priceDecayInterval" gorm:"type:bigint(20) unsigned;not null;default:0;comment:荷兰式拍卖降价间隔(秒)"`
+	ReservePrice         *decimal.Decimal `json:"reservePrice,omitempty" gorm:"type:decimal(65,30);comment:保留价(英式和密封拍卖)"`
+	BidIncrementPercent  uint64           `json:"bidIncrementPercent" gorm:"type:bigint(20) unsigned;not null;default:0;comment:英式拍卖最小加价幅度(百分比)"`
+	CommitDuration       uint64           `json:"commitDuration" gorm:"type:bigint(20) unsigned;not null;default:0;comment:密封拍卖提交阶段时长(秒)"`
+	RevealDuration       uint64           `json:"revealDuration" gorm:"type:bigint(20) unsigned;not null;default:0;comment:密封拍卖揭示阶段时长(秒)"`
+	AutoRelist           bool             `json:"autoRelist" gorm:"type:tinyint(1);not null;default:0;comment:流拍后是否自动重新上架"`
+	RelistDuration       uint64           `json:"relistDuration" gorm:"type:bigint(20) unsigned;not null;default:0;comment:重新上架的拍卖时长(秒)"`
+	RelistPriceReduction uint64           `json:"relistPriceReduction" gorm:"type:bigint(20) unsigned;not null;default:0;comment:重新上架的起拍价下调百分比"`
+	Visibility           string           `json:"visibility" gorm:"type:varchar(20);not null;default:'public';comment:可见性(public,unlisted,allowlist)"`
+	CreatedAt            *time.Time       `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt            *time.Time       `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp;comment:更新时间"`
+}
+
+// AuctionTemplatePayload 创建/更新拍卖模板的请求体
+type AuctionTemplatePayload struct {
+	Name                 string           `json:"name" binding:"required,max=100"`                                // 模板名称
+	AuctionType          string           `json:"auctionType" binding:"omitempty,oneof=english dutch sealed"`     // 拍卖类型，默认 english
+	PaymentToken         string           `json:"paymentToken" binding:"required"`                                // 支付代币地址
+	StartPrice           decimal.Decimal  `json:"startPrice" binding:"required"`                                  // 起拍价
+	Duration             uint64           `json:"duration" binding:"required,min=60"`                             // 拍卖时长（秒）
+	FloorPrice           *decimal.Decimal `json:"floorPrice"`                                                     // 荷兰式拍卖底价
+	PriceDecayInterval   uint64           `json:"priceDecayInterval"`                                             // 荷兰式拍卖降价间隔（秒）
+	ReservePrice         *decimal.Decimal `json:"reservePrice"`                                                   // 保留价（英式和密封拍卖，不低于起拍价）
+	BidIncrementPercent  uint64           `json:"bidIncrementPercent" binding:"omitempty,max=100"`                // 英式拍卖最小加价幅度（相对上一个最高出价的百分比）
+	CommitDuration       uint64           `json:"commitDuration"`                                                 // 密封拍卖提交阶段时长（秒，从开始时间算起）
+	RevealDuration       uint64           `json:"revealDuration"`                                                 // 密封拍卖揭示阶段时长（秒，从提交截止算起）
+	AutoRelist           bool             `json:"autoRelist"`                                                     // 流拍后是否自动重新上架
+	RelistDuration       uint64           `json:"relistDuration" binding:"omitempty,min=60"`                      // 重新上架的拍卖时长（秒）
+	RelistPriceReduction uint64           `json:"relistPriceReduction" binding:"omitempty,max=90"`                // 重新上架的起拍价下调百分比（0-90）
+	Visibility           string           `json:"visibility" binding:"omitempty,oneof=public unlisted allowlist"` // 可见性，默认 public
+}
+
+// CloneAuctionPayload 克隆拍卖的请求体（均可选）
+type CloneAuctionPayload struct {
+	StartTime *time.Time `json:"startTime"` // 新拍卖开始时间（默认当前时间）
+	EndTime   *time.Time `json:"endTime"`   // 新拍卖结束时间（默认沿用原拍卖时长）
+	Draft     bool       `json:"draft"`     // 是否保存为草稿（不占用 NFT 在线锁）
+}
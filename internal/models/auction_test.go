@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAuctionCurrentPrice(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(100 * time.Second)
+	startPrice := decimal.NewFromInt(100)
+	floorPrice := decimal.NewFromInt(50)
+
+	dutch := func(interval uint64) *Auction {
+		return &Auction{
+			AuctionType:        AuctionTypeDutch,
+			StartPrice:         &startPrice,
+			FloorPrice:         &floorPrice,
+			PriceDecayInterval: interval,
+			StartTime:          &start,
+			EndTime:            &end,
+		}
+	}
+	english := &Auction{AuctionType: AuctionTypeEnglish, StartPrice: &startPrice, StartTime: &start, EndTime: &end}
+
+	tests := []struct {
+		name    string
+		auction *Auction
+		now     time.Time
+		want    string
+	}{
+		{"english ignores time", english, start.Add(50 * time.Second), "100"},
+		{"no start price", &Auction{AuctionType: AuctionTypeDutch}, start, "0"},
+		{"before start", dutch(0), start.Add(-time.Second), "100"},
+		{"at start", dutch(0), start, "100"},
+		{"continuous quarter", dutch(0), start.Add(25 * time.Second), "87.5"},
+		{"continuous half", dutch(0), start.Add(50 * time.Second), "75"},
+		{"stepped rounds down to interval", dutch(30), start.Add(59 * time.Second), "85"},
+		{"stepped on boundary", dutch(30), start.Add(60 * time.Second), "70"},
+		{"at end", dutch(0), end, "50"},
+		{"after end", dutch(30), end.Add(time.Hour), "50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.auction.CurrentPrice(tt.now)
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("CurrentPrice() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuctionCurrentPriceUSD(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(100 * time.Second)
+	startPrice := decimal.NewFromInt(2)
+	startPriceUSD := decimal.NewFromInt(6000)
+	floorPrice := decimal.NewFromInt(1)
+	auction := &Auction{
+		AuctionType:   AuctionTypeDutch,
+		StartPrice:    &startPrice,
+		StartPriceUSD: &startPriceUSD,
+		FloorPrice:    &floorPrice,
+		StartTime:     &start,
+		EndTime:       &end,
+	}
+
+	if got := auction.CurrentPriceUSD(start.Add(50 * time.Second)); !got.Equal(decimal.NewFromInt(4500)) {
+		t.Errorf("CurrentPriceUSD() = %s, want 4500", got)
+	}
+	if got := auction.CurrentPriceUSD(end); !got.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("CurrentPriceUSD() at end = %s, want 3000", got)
+	}
+}
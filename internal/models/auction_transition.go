@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AuctionTransition 拍卖状态变更记录（只追加，不修改）
+type AuctionTransition struct {
+	ID         uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned"`
+	AuctionID  string     `json:"auctionId" gorm:"type:varchar(50);not null;index:idx_auction_transitions_auction_id;comment:拍卖ID"`
+	FromStatus string     `json:"fromStatus" gorm:"type:varchar(20);not null;default:'';comment:变更前状态(空表示新建)"`
+	ToStatus   string     `json:"toStatus" gorm:"type:varchar(20);not null;comment:变更后状态"`
+	Actor      string     `json:"actor" gorm:"type:varchar(64);not null;default:'';comment:操作者(user:用户ID/链上地址/system)"`
+	Cause      string     `json:"cause" gorm:"type:varchar(20);not null;comment:触发来源(api,event,task)"`
+	Reason     string     `json:"reason" gorm:"type:varchar(64);not null;default:'';comment:变更原因"`
+	TxHash     string     `json:"txHash,omitempty" gorm:"type:varchar(66);not null;default:'';comment:关联的链上交易哈希"`
+	CreatedAt  *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:变更时间"`
+}
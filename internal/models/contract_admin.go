@@ -0,0 +1,120 @@
+package models
+
+import "time"
+
+// 合约管理操作类型（对应拍卖合约的管理员方法）
+const (
+	ContractActionForceEnd       = "force_end"        // forceEndAuctionAndClaimNFT
+	ContractActionPause          = "pause"            // pause
+	ContractActionUnpause        = "unpause"          // unpause
+	ContractActionSetPlatformFee = "set_platform_fee" // setPlatformFee
+	ContractActionSetFeeTiers    = "set_fee_tiers"    // setFeeTiers
+	ContractActionSetDynamicFee  = "set_dynamic_fee"  // setDynamicFeeEnabled
+	ContractActionSetPriceFeeds  = "set_price_feeds"  // setPriceFeed / setPriceFeeds
+)
+
+// 合约管理操作状态
+const (
+	ContractActionStatusRequested = "requested" // 已记录操作，交易发送中
+	ContractActionStatusSubmitted = "submitted" // 交易已发送，等待回执
+	ContractActionStatusConfirmed = "confirmed" // 交易执行成功
+	ContractActionStatusFailed    = "failed"    // 交易发送失败、执行失败或等待回执超时
+)
+
+// 合约设置键（由合约事件或交易回执写入 contract_settings）
+const (
+	ContractSettingPaused            = "paused"              // 合约是否暂停（Paused/Unpaused 事件）
+	ContractSettingPlatformFee       = "platform_fee"        // 平台手续费（PlatformFeeUpdated 事件）
+	ContractSettingDynamicFeeEnabled = "dynamic_fee_enabled" // 是否启用动态手续费（DynamicFeeEnabled 事件）
+	ContractSettingFeeTierPrefix     = "fee_tier:"           // 手续费档次（FeeTierUpdated 事件），键为 fee_tier:档次索引
+	ContractSettingPriceFeedPrefix   = "price_feed:"         // 代币价格预言机（合约无事件，setPriceFeed 交易成功后写入），键为 price_feed:代币地址
+)
+
+// ContractAction 管理员通过平台私钥发送的合约管理交易
+type ContractAction struct {
+	ID          uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:操作ID"`
+	Action      string     `json:"action" gorm:"type:varchar(32);not null;index:idx_contract_actions_action;comment:操作类型"`
+	Target      string     `json:"target,omitempty" gorm:"type:varchar(50);not null;default:'';comment:操作对象（如拍卖ID）"`
+	Params      string     `json:"params,omitempty" gorm:"type:text;comment:操作参数(JSON)"`
+	Reason      string     `json:"reason,omitempty" gorm:"type:varchar(255);not null;default:'';comment:操作原因"`
+	RequestedBy uint64     `json:"requestedBy" gorm:"type:bigint(20) unsigned;not null;comment:发起操作的管理员用户ID"`
+	TxHash      string     `json:"txHash" gorm:"type:varchar(66);not null;default:'';comment:交易哈希"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'requested';index:idx_contract_actions_status;comment:状态(requested,submitted,confirmed,failed)"`
+	BlockNumber uint64     `json:"blockNumber,omitempty" gorm:"type:bigint(20) unsigned;not null;default:0;comment:交易所在区块"`
+	Error       string     `json:"error,omitempty" gorm:"type:varchar(255);not null;default:'';comment:失败原因"`
+	CreatedAt   *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt   *time.Time `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp on update current_timestamp;comment:更新时间"`
+}
+
+// ContractSetting 合约当前设置（键值对），按区块号和日志索引只保留最新值
+type ContractSetting struct {
+	Key         string     `json:"key" gorm:"column:setting_key;primaryKey;type:varchar(100);comment:设置键"`
+	Value       string     `json:"value" gorm:"type:varchar(255);not null;default:'';comment:设置值"`
+	BlockNumber uint64     `json:"blockNumber" gorm:"type:bigint(20) unsigned;not null;default:0;comment:来源区块"`
+	LogIndex    uint       `json:"logIndex" gorm:"type:int(10) unsigned;not null;default:0;comment:来源日志索引"`
+	TxHash      string     `json:"txHash" gorm:"type:varchar(66);not null;default:'';comment:来源交易哈希"`
+	UpdatedAt   *time.Time `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp on update current_timestamp;comment:更新时间"`
+}
+
+// ContractFeeTier 手续费档次
+type ContractFeeTier struct {
+	Index     uint64 `json:"index"`
+	Threshold string `json:"threshold"` // 美元价值阈值（8 位小数）
+	FeeRate   string `json:"feeRate"`   // 手续费率（基点）
+}
+
+// ContractSettingsResponse 合约当前设置
+type ContractSettingsResponse struct {
+	Paused            bool              `json:"paused"`
+	PlatformFee       string            `json:"platformFee"`
+	DynamicFeeEnabled bool              `json:"dynamicFeeEnabled"`
+	FeeTiers          []ContractFeeTier `json:"feeTiers"`
+	PriceFeeds        map[string]string `json:"priceFeeds"`  // 代币地址 -> 价格预言机地址
+	BlockNumber       uint64            `json:"blockNumber"` // 设置来源的最新区块
+}
+
+// ForceEndAuctionPayload 管理员强制结束拍卖
+type ForceEndAuctionPayload struct {
+	Reason string `json:"reason" binding:"required,max=255"` // 强制结束原因
+}
+
+// ContractPausePayload 暂停或恢复合约
+type ContractPausePayload struct {
+	Reason string `json:"reason" binding:"required,max=255"` // 操作原因
+}
+
+// SetPlatformFeePayload 设置平台手续费
+type SetPlatformFeePayload struct {
+	Fee    uint64 `json:"fee" binding:"max=10000"` // 平台手续费（基点，10000 = 100%）
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// FeeTierPayload 手续费档次参数
+type FeeTierPayload struct {
+	Threshold string `json:"threshold" binding:"required"` // 美元价值阈值（8 位小数的整数字符串）
+	FeeRate   uint64 `json:"feeRate" binding:"max=10000"`  // 手续费率（基点）
+}
+
+// SetFeeTiersPayload 设置手续费档次（整体替换）
+type SetFeeTiersPayload struct {
+	Tiers  []FeeTierPayload `json:"tiers" binding:"required,min=1,dive"`
+	Reason string           `json:"reason" binding:"max=255"`
+}
+
+// SetDynamicFeePayload 启用或禁用动态手续费
+type SetDynamicFeePayload struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Reason  string `json:"reason" binding:"max=255"`
+}
+
+// PriceFeedPayload 代币价格预言机参数
+type PriceFeedPayload struct {
+	Token     string `json:"token" binding:"required"`     // 代币地址（原生币为零地址）
+	PriceFeed string `json:"priceFeed" binding:"required"` // Chainlink 价格预言机地址
+}
+
+// SetPriceFeedsPayload 设置代币价格预言机
+type SetPriceFeedsPayload struct {
+	Feeds  []PriceFeedPayload `json:"feeds" binding:"required,min=1,dive"`
+	Reason string             `json:"reason" binding:"max=255"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 出售方式（列表中区分拍卖和固定价格出售）
+const (
+	SaleTypeAuction = "auction" // 拍卖（english、dutch、sealed）
+	SaleTypeListing = "listing" // 固定价格出售（fixed）
+)
+
+// 出售结果（只对已结束的拍卖和固定价格出售返回）
+const (
+	SaleOutcomeSold      = "sold"      // 拍卖成交（NFT 已转给最高出价者）
+	SaleOutcomePurchased = "purchased" // 固定价格出售已被购买
+	SaleOutcomeUnsold    = "unsold"    // 到期无人出价或购买（流拍）
+)
+
+// ListingPayload 创建固定价格出售的请求体
+// 固定价格出售复用拍卖记录（auctionType 为 fixed），链上以标价作为起拍价创建拍卖
+type ListingPayload struct {
+	NFTID        string          `json:"nftId" binding:"required"`        // NFT唯一标识
+	NFTAddress   string          `json:"nftAddress" binding:"required"`   // NFT合约地址
+	TokenID      uint64          `json:"tokenId" binding:"required"`      // Token ID
+	PaymentToken string          `json:"paymentToken" binding:"required"` // 支付代币地址(0x0表示ETH,其他表示ERC20代币)
+	Price        decimal.Decimal `json:"price" binding:"required"`        // 标价(单位由PaymentToken指定)
+	StartTime    *time.Time      `json:"startTime"`                       // 开始出售时间（可选，默认当前时间）
+	EndTime      *time.Time      `json:"endTime" binding:"required"`      // 出售截止时间，到期无人购买自动下架
+	VisibilityParams
+}
+
+// UpdateListingPricePayload 修改固定价格出售标价的请求体
+type UpdateListingPricePayload struct {
+	Price decimal.Decimal `json:"price" binding:"required"` // 新标价(单位由PaymentToken指定)
+}
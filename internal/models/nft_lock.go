@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// NFTLock NFT 在线锁：拍卖（含打包拍卖的每个成员）进入 pending 时写入，结束/取消/过期后删除
+// NFTID 为主键，无论 NFT 作为单个拍卖、打包主 NFT 还是打包成员，同一 NFT 同时只能被一个拍卖锁定
+type NFTLock struct {
+	NFTID     string     `json:"nftId" gorm:"primaryKey;type:varchar(64);comment:NFT唯一标识"`
+	AuctionID string     `json:"auctionId" gorm:"type:varchar(50);not null;index:idx_nft_locks_auction_id;comment:锁定该NFT的拍卖ID"`
+	CreatedAt *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:加锁时间"`
+}
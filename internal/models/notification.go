@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 通知类型常量
+const (
+	NotificationTypeAuctionEndingSoon      = "auction_ending_soon"      // 拍卖即将结束
+	NotificationTypeAuctionRelisted        = "auction_relisted"         // 流拍后已自动重新上架，等待卖家签名上链
+	NotificationTypeAuctionExpired         = "auction_expired"          // 待上架拍卖超时未签名上链，已过期并释放 NFT
+	NotificationTypeAuctionCancelled       = "auction_cancelled"        // 拍卖已被管理员取消
+	NotificationTypeAuctionCancelledRefund = "auction_cancelled_refund" // 拍卖已取消，最高出价已退款
+	NotificationTypeOfferReceived          = "offer_received"           // 持有的 NFT 收到新报价
+	NotificationTypeOfferAccepted          = "offer_accepted"           // 报价已被接受，可在链上按报价金额购买
+	NotificationTypeOfferRejected          = "offer_rejected"           // 报价已被拒绝
+	NotificationTypeOfferExpired           = "offer_expired"            // 报价已过期
+)
+
+// AuctionWatch 用户关注的拍卖
+type AuctionWatch struct {
+	ID        uint64     `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned"`
+	AuctionID string     `json:"auctionId" gorm:"type:varchar(50);not null;uniqueIndex:uk_auction_watches_auction_user,priority:1;comment:拍卖ID"`
+	UserID    uint64     `json:"userId" gorm:"type:bigint(20) unsigned;not null;uniqueIndex:uk_auction_watches_auction_user,priority:2;index:idx_auction_watches_user_id;comment:关注者ID"`
+	CreatedAt *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+}
+
+// Notification 发送给用户的通知（通过通知渠道投递，不落库）
+type Notification struct {
+	Type      string                 `json:"type"`                // 通知类型
+	Title     string                 `json:"title"`               // 标题
+	Content   string                 `json:"content"`             // 内容
+	AuctionID string                 `json:"auctionId,omitempty"` // 关联拍卖ID
+	Data      map[string]interface{} `json:"data,omitempty"`      // 附加数据
+	CreatedAt time.Time              `json:"createdAt"`           // 生成时间
+}
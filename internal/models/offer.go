@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 报价状态常量
+const (
+	OfferStatusPending   = "pending"   // 等待NFT持有者处理
+	OfferStatusAccepted  = "accepted"  // 已接受（已为持有者创建标价为报价金额的固定价格出售）
+	OfferStatusRejected  = "rejected"  // 已拒绝
+	OfferStatusExpired   = "expired"   // 已过期
+	OfferStatusCancelled = "cancelled" // 买家已撤回
+)
+
+// Offer 买家对未上架 NFT 的链下签名报价
+// 买家使用 EIP-712（eth_signTypedData_v4）对报价签名，后端校验签名、代币余额和对拍卖合约的授权额度后保存；
+// 持有者接受后以报价金额创建固定价格出售，由买家在链上完成购买。
+type Offer struct {
+	ID               uint64           `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:报价ID"`
+	NFTID            string           `json:"nftId" gorm:"type:varchar(64);not null;index:idx_offers_nft_id;comment:NFT唯一标识"`
+	NFTAddress       string           `json:"nftAddress" gorm:"type:varchar(42);not null;comment:NFT合约地址"`
+	TokenID          uint64           `json:"tokenId" gorm:"type:bigint(20) unsigned;not null;comment:Token ID"`
+	OwnerUserID      uint64           `json:"ownerUserId" gorm:"type:bigint(20) unsigned;not null;index:idx_offers_owner_user_id;comment:报价时NFT持有者ID"`
+	BuyerUserID      uint64           `json:"buyerUserId" gorm:"type:bigint(20) unsigned;not null;index:idx_offers_buyer_user_id;comment:买家ID"`
+	BuyerAddress     string           `json:"buyerAddress" gorm:"type:varchar(42);not null;comment:买家钱包地址"`
+	PaymentToken     string           `json:"paymentToken" gorm:"type:varchar(42);not null;comment:报价代币地址(仅支持ERC20)"`
+	Amount           decimal.Decimal  `json:"amount" gorm:"type:decimal(65,30);not null;comment:报价金额(单位由PaymentToken指定)"`
+	AmountUSD        *decimal.Decimal `json:"amountUSD" gorm:"type:decimal(65,30);comment:报价时的USD价值"`
+	RawAmount        string           `json:"rawAmount" gorm:"type:varchar(80);not null;comment:报价金额(代币最小单位，参与签名)"`
+	Expiry           int64            `json:"expiry" gorm:"type:bigint(20);not null;comment:过期时间戳(秒，参与签名)"`
+	Nonce            string           `json:"nonce" gorm:"type:varchar(80);not null;comment:买家随机数(参与签名)"`
+	OfferHash        string           `json:"offerHash" gorm:"type:varchar(66);not null;uniqueIndex:uk_offers_offer_hash;comment:EIP-712摘要"`
+	Signature        string           `json:"-" gorm:"type:varchar(132);not null;comment:买家EIP-712签名"`
+	Status           string           `json:"status" gorm:"type:varchar(20);not null;default:'pending';comment:状态(pending,accepted,rejected,expired,cancelled)"`
+	ListingAuctionID string           `json:"listingAuctionId,omitempty" gorm:"type:varchar(50);comment:接受后创建的固定价格出售拍卖ID"`
+	CreatedAt        *time.Time       `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt        *time.Time       `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp;comment:更新时间"`
+}
+
+// OfferPayload 提交报价的请求体
+// 签名的 EIP-712 结构见 services.OfferTypedData
+type OfferPayload struct {
+	PaymentToken string `json:"paymentToken" binding:"required"` // 报价代币地址(仅支持ERC20，如USDC)
+	Amount       string `json:"amount" binding:"required"`       // 报价金额(代币最小单位的十进制字符串)
+	Expiry       int64  `json:"expiry" binding:"required"`       // 过期时间戳(秒)
+	Nonce        string `json:"nonce" binding:"required"`        // 随机数(十进制字符串)，防止重复提交相同报价
+	Signature    string `json:"signature" binding:"required"`    // eth_signTypedData_v4 签名
+}
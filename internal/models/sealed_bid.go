@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 密封出价状态常量
+const (
+	SealedBidStatusCommitted = "committed" // 已提交承诺，等待揭示
+	SealedBidStatusRevealed  = "revealed"  // 已揭示且校验通过
+	SealedBidStatusInvalid   = "invalid"   // 揭示金额无效（低于起拍价等），不参与排名
+)
+
+// SealedBid 密封拍卖出价承诺
+// 提交阶段只保存承诺哈希和签名；揭示阶段校验后才写入金额。
+// ChainHash 按提交顺序串联同一拍卖的所有承诺（prevChainHash + 本条承诺内容），任何一条被篡改都会导致后续哈希校验失败。
+type SealedBid struct {
+	ID              uint64           `json:"id" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:密封出价ID"`
+	AuctionID       string           `json:"auctionId" gorm:"type:varchar(50);not null;uniqueIndex:uk_sealed_bids_auction_user,priority:1;comment:拍卖ID"`
+	UserID          uint64           `json:"userId" gorm:"type:bigint(20) unsigned;not null;uniqueIndex:uk_sealed_bids_auction_user,priority:2;comment:出价者ID"`
+	WalletAddress   string           `json:"walletAddress" gorm:"type:varchar(42);not null;comment:出价者钱包地址"`
+	CommitHash      string           `json:"commitHash" gorm:"type:varchar(66);not null;comment:出价承诺哈希 keccak256(auctionId|bidder|paymentToken|amount|salt)"`
+	CommitSignature string           `json:"commitSignature" gorm:"type:varchar(132);not null;comment:出价者对承诺消息的签名"`
+	PrevChainHash   string           `json:"prevChainHash" gorm:"type:varchar(66);not null;comment:上一条承诺的链式哈希"`
+	ChainHash       string           `json:"chainHash" gorm:"type:varchar(66);not null;comment:链式哈希(防篡改)"`
+	CommittedAt     *time.Time       `json:"committedAt" gorm:"type:datetime(6);not null;comment:承诺提交时间"`
+	Status          string           `json:"status" gorm:"type:varchar(20);not null;default:'committed';comment:状态(committed,revealed,invalid)"`
+	PaymentToken    string           `json:"paymentToken,omitempty" gorm:"type:varchar(42);comment:揭示的出价代币地址"`
+	Amount          *decimal.Decimal `json:"amount,omitempty" gorm:"type:decimal(65,30);comment:揭示的出价金额(单位由PaymentToken指定)"`
+	AmountUSD       *decimal.Decimal `json:"amountUSD,omitempty" gorm:"type:decimal(65,30);comment:揭示时的出价USD价值"`
+	RawAmount       string           `json:"-" gorm:"type:varchar(80);comment:揭示的原始金额字符串(参与承诺哈希计算)"`
+	Salt            string           `json:"-" gorm:"type:varchar(128);comment:揭示的随机盐"`
+	RevealSignature string           `json:"-" gorm:"type:varchar(132);comment:出价者对揭示消息的签名"`
+	RevealedAt      *time.Time       `json:"revealedAt,omitempty" gorm:"type:datetime;comment:揭示时间"`
+	Rank            uint64           `json:"rank,omitempty" gorm:"type:bigint(20) unsigned;not null;default:0;comment:揭示结束后的排名(1为最高)"`
+	Winner          bool             `json:"winner" gorm:"type:tinyint(1);not null;default:0;comment:是否为获胜者"`
+	CreatedAt       *time.Time       `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt       *time.Time       `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp;comment:更新时间"`
+}
+
+// SealedBidCommitPayload 提交出价承诺的请求体
+type SealedBidCommitPayload struct {
+	CommitHash string `json:"commitHash" binding:"required"` // keccak256(auctionId|bidder|paymentToken|amount|salt)，0x开头
+	Signature  string `json:"signature" binding:"required"`  // 对承诺消息的 personal_sign 签名
+}
+
+// SealedBidRevealPayload 揭示出价的请求体
+type SealedBidRevealPayload struct {
+	PaymentToken string `json:"paymentToken" binding:"required"` // 出价代币地址
+	Amount       string `json:"amount" binding:"required"`       // 出价金额(与承诺时的字符串完全一致)
+	Salt         string `json:"salt" binding:"required"`         // 承诺时使用的随机盐
+	Signature    string `json:"signature" binding:"required"`    // 对揭示消息的 personal_sign 签名
+}
+
+// SealedAuctionResult 密封拍卖揭示结束后的结果（WebSocket 推送和接口返回）
+type SealedAuctionResult struct {
+	AuctionID         string           `json:"auctionId"`                   // 拍卖ID
+	Winner            string           `json:"winner,omitempty"`            // 获胜者钱包地址（无有效出价时为空）
+	PaymentToken      string           `json:"paymentToken,omitempty"`      // 获胜者出价代币
+	ClearingPrice     *decimal.Decimal `json:"clearingPrice,omitempty"`     // 成交价（第二高价，单位由 PaymentToken 指定）
+	ClearingPriceUSD  *decimal.Decimal `json:"clearingPriceUSD,omitempty"`  // 成交价USD
+	RevealedCount     int              `json:"revealedCount"`               // 有效揭示数量
+	CommittedCount    int              `json:"committedCount"`              // 承诺总数
+	SettlementEndTime *time.Time       `json:"settlementEndTime,omitempty"` // 获胜者链上出价截止时间（拍卖结束时间）
+}
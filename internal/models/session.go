@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserSession 用户登录会话（设备），存储在 Redis 中
+type UserSession struct {
+	ID            string    `json:"id"`
+	WalletAddress string    `json:"walletAddress"` // 登录使用的钱包地址
+	UserAgent     string    `json:"userAgent"`     // 最近一次请求的 User-Agent
+	IP            string    `json:"ip"`            // 最近一次请求的 IP
+	CreatedAt     time.Time `json:"createdAt"`     // 登录时间
+	LastSeenAt    time.Time `json:"lastSeenAt"`    // 最近活跃时间（按分钟更新）
+	Current       bool      `json:"current"`       // 是否为当前请求使用的会话
+}
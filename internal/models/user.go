@@ -4,20 +4,81 @@ import (
 	"time"
 )
 
+// 用户角色
+const (
+	RoleUser      = "user"      // 普通用户
+	RoleModerator = "moderator" // 运营：可处理任意拍卖（取消拍卖、管理拍卖任务）
+	RoleAdmin     = "admin"     // 管理员：拥有全部权限，包括合约管理和角色分配
+)
+
+// 账户状态
+const (
+	UserStatusActive    = "active"    // 正常
+	UserStatusSuspended = "suspended" // 暂停：到期（suspendedUntil）后自动恢复，为空表示直到管理员恢复
+	UserStatusBanned    = "banned"    // 封禁
+	UserStatusDeleted   = "deleted"   // 已注销：个人信息已清除，钱包可重新注册新账户
+)
+
 type User struct {
-	ID            uint64    `json:"-" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:用户ID"`
-	Username      string    `json:"username" gorm:"type:varchar(64);not null;uniqueIndex:idx_users_username;comment:用户名"`
-	Email         string    `json:"email" gorm:"type:varchar(128);not null;uniqueIndex:idx_users_email;comment:邮箱"`
-	Password      string    `json:"-" gorm:"type:varchar(255);not null;comment:密码哈希"`
-	WalletAddress string    `json:"walletAddress" gorm:"type:varchar(42);index:idx_users_wallet_address;comment:钱包地址"`
-	Nonce         string    `json:"-" gorm:"type:varchar(64);comment:登录Nonce"`
-	CreatedAt     time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
-	UpdatedAt     time.Time `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp on update current_timestamp;comment:更新时间"`
+	ID             uint64     `json:"-" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:用户ID"`
+	Username       string     `json:"username" gorm:"type:varchar(64);not null;uniqueIndex:idx_users_username;comment:用户名"`
+	Email          *string    `json:"email,omitempty" gorm:"type:varchar(128);uniqueIndex:idx_users_email;comment:邮箱(可选)"`
+	EmailVerified  bool       `json:"emailVerified" gorm:"type:tinyint(1);not null;default:0;comment:邮箱是否已验证"`
+	PendingEmail   *string    `json:"pendingEmail,omitempty" gorm:"type:varchar(128);index:idx_users_pending_email;comment:待验证的新邮箱(验证后写入email)"`
+	Password       string     `json:"-" gorm:"type:varchar(255);not null;comment:密码哈希"`
+	WalletAddress  string     `json:"walletAddress" gorm:"type:varchar(42);index:idx_users_wallet_address;comment:钱包地址"`
+	Nonce          string     `json:"-" gorm:"type:varchar(64);comment:登录Nonce"`
+	Role           string     `json:"role" gorm:"type:varchar(20);not null;default:'user';index:idx_users_role;comment:角色(user,moderator,admin)"`
+	DisplayName    string     `json:"displayName" gorm:"type:varchar(64);not null;default:'';comment:昵称"`
+	AvatarURL      string     `json:"avatarUrl" gorm:"type:varchar(255);not null;default:'';comment:头像URL"`
+	Bio            string     `json:"bio" gorm:"type:varchar(500);not null;default:'';comment:个人简介"`
+	OnboardedAt    *time.Time `json:"onboardedAt,omitempty" gorm:"type:datetime;comment:完成引导时间"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;default:'active';index:idx_users_status;comment:状态(active,suspended,banned,deleted)"`
+	StatusReason   string     `json:"statusReason,omitempty" gorm:"type:varchar(255);not null;default:'';comment:暂停/封禁原因"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty" gorm:"type:datetime;comment:暂停截止时间"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty" gorm:"type:datetime;comment:注销时间"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:创建时间"`
+	UpdatedAt      time.Time  `json:"updatedAt" gorm:"type:datetime;not null;default:current_timestamp on update current_timestamp;comment:更新时间"`
 
 	Auctions []Auction `json:"auctions,omitempty" gorm:"foreignKey:UserID"`
 	Bids     []Bid     `json:"bids,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// EmailAddress 邮箱地址，未设置时为空字符串
+func (u *User) EmailAddress() string {
+	if u.Email == nil {
+		return ""
+	}
+	return *u.Email
+}
+
+// PendingEmailAddress 待验证的新邮箱，没有时为空字符串
+func (u *User) PendingEmailAddress() string {
+	if u.PendingEmail == nil {
+		return ""
+	}
+	return *u.PendingEmail
+}
+
+// EmailToVerify 需要验证的邮箱：待验证的新邮箱，否则为未验证的当前邮箱，都没有时为空字符串
+func (u *User) EmailToVerify() string {
+	if pending := u.PendingEmailAddress(); pending != "" {
+		return pending
+	}
+	if u.EmailVerified {
+		return ""
+	}
+	return u.EmailAddress()
+}
+
+// EffectiveStatus 当前生效的账户状态（暂停已到期的视为正常）
+func (u *User) EffectiveStatus(now time.Time) string {
+	if u.Status == UserStatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return UserStatusActive
+	}
+	return u.Status
+}
+
 type RegisterPayload struct {
 	Username      string `json:"username" binding:"required,min=1,max=64"`
 	Email         string `json:"email" binding:"required,email"`
@@ -38,15 +99,74 @@ type LoginPayload struct {
 }
 
 type LoginResult struct {
-	Token string      `json:"token"`
-	User  UserProfile `json:"user"`
+	TokenPair
+	User UserProfile `json:"user"`
+}
+
+// TokenPair 访问令牌和刷新令牌
+type TokenPair struct {
+	Token        string `json:"token"`        // 访问令牌（JWT）
+	RefreshToken string `json:"refreshToken"` // 刷新令牌（一次性使用，刷新后轮换）
+	ExpiresIn    int64  `json:"expiresIn"`    // 访问令牌有效期（秒）
+}
+
+// RefreshTokenPayload 刷新访问令牌
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// LogoutPayload 登出
+type LogoutPayload struct {
+	AllSessions bool `json:"allSessions"` // 是否登出所有会话（所有设备）
 }
 
 type UserProfile struct {
-	ID            uint64 `json:"id"`
-	Username      string `json:"username"`
-	Email         string `json:"email"`
-	WalletAddress string `json:"walletAddress"`
+	ID            uint64     `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`                  // 未设置时为空字符串
+	EmailVerified bool       `json:"emailVerified"`          // 邮箱是否已验证（只有已验证的邮箱会收到邮件通知）
+	PendingEmail  string     `json:"pendingEmail,omitempty"` // 待验证的新邮箱，验证后替换 email
+	WalletAddress string     `json:"walletAddress"`
+	Role          string     `json:"role"`
+	DisplayName   string     `json:"displayName"`
+	AvatarURL     string     `json:"avatarUrl"`
+	Bio           string     `json:"bio"`
+	Onboarded     bool       `json:"onboarded"` // 是否已完成引导（新用户首次登录后需要设置用户名等资料）
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	OnboardedAt   *time.Time `json:"onboardedAt,omitempty"`
+}
+
+// NewUserProfile 转换为用户资料
+func NewUserProfile(user *User) UserProfile {
+	return UserProfile{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.EmailAddress(),
+		EmailVerified: user.EmailVerified,
+		PendingEmail:  user.PendingEmailAddress(),
+		WalletAddress: user.WalletAddress,
+		Role:          user.Role,
+		DisplayName:   user.DisplayName,
+		AvatarURL:     user.AvatarURL,
+		Bio:           user.Bio,
+		Onboarded:     user.OnboardedAt != nil,
+		Status:        user.EffectiveStatus(time.Now()),
+		CreatedAt:     user.CreatedAt,
+		OnboardedAt:   user.OnboardedAt,
+	}
+}
+
+// UpdateUserRolePayload 管理员修改用户角色
+type UpdateUserRolePayload struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// UpdateUserStatusPayload 管理员修改账户状态
+type UpdateUserStatusPayload struct {
+	Status         string     `json:"status" binding:"required,oneof=active suspended banned"`
+	Reason         string     `json:"reason" binding:"max=255"` // 暂停/封禁原因（展示给用户）
+	SuspendedUntil *time.Time `json:"suspendedUntil"`           // 暂停截止时间（仅 suspended，为空表示直到管理员恢复）
 }
 
 // WalletLoginRequestNoncePayload 钱包登录请求 nonce
@@ -56,14 +176,16 @@ type WalletLoginRequestNoncePayload struct {
 
 // WalletLoginRequestNonceResult 返回 nonce 和消息
 type WalletLoginRequestNonceResult struct {
-	Nonce   string `json:"nonce"`
-	Message string `json:"message"`
+	Nonce          string `json:"nonce"`
+	Message        string `json:"message"`        // SIWE（EIP-4361）消息，原样交给钱包 personal_sign
+	IssuedAt       string `json:"issuedAt"`       // 签发时间（RFC 3339）
+	ExpirationTime string `json:"expirationTime"` // 过期时间（RFC 3339）
 }
 
 // WalletLoginVerifyPayload 钱包登录验证签名
 type WalletLoginVerifyPayload struct {
 	WalletAddress string `json:"walletAddress" binding:"required"`
-	Message       string `json:"message" binding:"required"`
+	Message       string `json:"message" binding:"required"` // 签名的 SIWE 消息（与 request-nonce 返回的一致）
 	Signature     string `json:"signature" binding:"required"`
 }
 
@@ -74,8 +196,25 @@ type PlatformStatsResponse struct {
 	TotalBids     uint64 `json:"totalBids"`     // 出价总数
 }
 
-// UpdateProfilePayload 更新用户信息请求
+// UpdateProfilePayload 更新用户信息请求（未提供的字段不修改）
 type UpdateProfilePayload struct {
-	Username string `json:"username" binding:"omitempty,min=1,max=64"` // 用户名（可选）
-	Email    string `json:"email" binding:"omitempty,email"`           // 邮箱（可选）
+	Username    string  `json:"username" binding:"omitempty,min=1,max=64"` // 用户名（可选）
+	Email       string  `json:"email" binding:"omitempty,email,max=128"`   // 邮箱（可选）
+	DisplayName *string `json:"displayName" binding:"omitempty,max=64"`    // 昵称（可选，空字符串表示清除）
+	AvatarURL   *string `json:"avatarUrl" binding:"omitempty,max=255"`     // 头像URL（可选，空字符串表示清除）
+	Bio         *string `json:"bio" binding:"omitempty,max=500"`           // 个人简介（可选，空字符串表示清除）
+}
+
+// VerifyEmailPayload 验证邮箱（验证邮件链接中的令牌）
+type VerifyEmailPayload struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// OnboardingPayload 新用户完成引导（首次登录后设置资料）
+type OnboardingPayload struct {
+	Username    string `json:"username" binding:"required,min=3,max=64"` // 用户名
+	DisplayName string `json:"displayName" binding:"max=64"`             // 昵称（可选）
+	AvatarURL   string `json:"avatarUrl" binding:"omitempty,max=255"`    // 头像URL（可选）
+	Bio         string `json:"bio" binding:"max=500"`                    // 个人简介（可选）
+	Email       string `json:"email" binding:"omitempty,email,max=128"`  // 邮箱（可选）
 }
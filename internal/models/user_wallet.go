@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// UserWallet 用户关联的其他钱包（主钱包保存在 users.wallet_address），通过钱包签名证明所有权后关联
+type UserWallet struct {
+	ID            uint64     `json:"-" gorm:"primaryKey;autoIncrement;type:bigint(20) unsigned;comment:ID"`
+	UserID        uint64     `json:"userId" gorm:"type:bigint(20) unsigned;not null;index:idx_user_wallets_user_id;comment:用户ID"`
+	WalletAddress string     `json:"walletAddress" gorm:"type:varchar(42);not null;uniqueIndex:uk_user_wallets_wallet_address;comment:钱包地址（小写）"`
+	CreatedAt     *time.Time `json:"createdAt" gorm:"type:datetime;not null;default:current_timestamp;comment:关联时间"`
+}
+
+// UserWalletResponse 用户的钱包
+type UserWalletResponse struct {
+	WalletAddress string     `json:"walletAddress"`
+	Primary       bool       `json:"primary"`                // 是否为主钱包（注册钱包，不可解除关联）
+	LinkedAt      *time.Time `json:"linkedAt,omitempty"`     // 关联时间（主钱包为空）
+	MergedUserID  uint64     `json:"mergedUserId,omitempty"` // 关联其他账户的主钱包时，被合并并注销的账户ID
+}
+
+// LinkWalletNoncePayload 请求关联钱包的签名消息
+type LinkWalletNoncePayload struct {
+	WalletAddress string `json:"walletAddress" binding:"required"`
+}
+
+// LinkWalletPayload 关联钱包（使用待关联钱包签名 link-nonce 返回的 SIWE 消息）
+type LinkWalletPayload struct {
+	WalletAddress string `json:"walletAddress" binding:"required"`
+	Message       string `json:"message" binding:"required"`
+	Signature     string `json:"signature" binding:"required"`
+}
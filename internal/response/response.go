@@ -2,6 +2,8 @@ package response
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -68,6 +70,28 @@ func Forbidden(c *gin.Context, message string) {
 	ErrorWithMessage(c, http.StatusForbidden, message)
 }
 
+// SetRateLimitHeaders 设置限流响应头：窗口内的请求上限、剩余次数、距离窗口内请求数下降的秒数
+func SetRateLimitHeaders(c *gin.Context, limit int, remaining int, reset time.Duration) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+}
+
+// TooManyRequests 返回 429，Retry-After 为客户端需要等待的秒数
+func TooManyRequests(c *gin.Context, retryAfter time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+	ErrorWithMessage(c, http.StatusTooManyRequests, message)
+}
+
+// ceilSeconds 向上取整的秒数（至少为 1）
+func ceilSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
 func Created(c *gin.Context, data interface{}) {
 	SuccessWithStatus(c, http.StatusCreated, data)
 }
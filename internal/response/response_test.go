@@ -0,0 +1,29 @@
+package response
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCeilSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want int
+	}{
+		{0, 1},
+		{-time.Second, 1},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{time.Second + time.Nanosecond, 2},
+		{1500 * time.Millisecond, 2},
+		{59 * time.Second, 59},
+		{time.Minute, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			if got := ceilSeconds(tt.d); got != tt.want {
+				t.Errorf("ceilSeconds(%v) = %d, want %d", tt.d, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"my-auction-market-api/internal/config"
 	"my-auction-market-api/internal/handlers"
 	"my-auction-market-api/internal/middleware"
+	"my-auction-market-api/internal/models"
 	"my-auction-market-api/internal/services"
 	"my-auction-market-api/internal/websocket"
 )
@@ -17,21 +18,46 @@ func Register(rg *gin.RouterGroup, cfg config.Config, smr *services.ServiceManag
 	rg.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	rg.GET("/health", handlers.HealthCheck)
+	rg.GET("/config", handlers.GetConfig(cfg, smr.ContractAdminService))
 	rg.GET("/config/ethereum", handlers.GetEthereumConfig(cfg))
 
+	// 认证请求更新登录会话的最近活跃时间
+	middleware.SetSessionTracker(smr.TokenService)
+	// 认证请求检查账户状态（暂停、封禁、已注销的账户被拒绝）
+	middleware.SetAccountStatusChecker(smr.UserService)
+	// 认证中间件接受 API Key（仅限声明了权限范围的接口）
+	middleware.SetAPIKeyAuthenticator(smr.APIKeyService)
+	// 接口限流（Redis 滑动窗口，规则按路由组配置）；user 规则放在认证中间件之后以按用户计数
+	middleware.SetRateLimiter(middleware.NewRateLimiter(smr.Redis))
+	limit := func(name string) gin.HandlerFunc {
+		return middleware.RateLimit(name, cfg.RateLimit.Rules[name])
+	}
+	auctionScopes := middleware.APIKeyScopes{Read: models.APIKeyScopeReadAuctions, Write: models.APIKeyScopeWriteAuctions}
+	nftScopes := middleware.APIKeyScopes{Read: models.APIKeyScopeReadNFTs, Write: models.APIKeyScopeWriteNFTs}
+
 	// 使用服务管理器中的服务创建handlers
-	userHandler := handlers.NewUserHandler(smr.UserService, smr.ListenerService)
+	userHandler := handlers.NewUserHandler(smr.UserService, smr.TokenService, smr.EmailVerification, smr.ListenerService)
 	auctionHandler := handlers.NewAuctionHandler(smr.AuctionService)
 	bidHandler := handlers.NewBidHandler(smr.BidService, smr.AuctionService)
+	sealedBidHandler := handlers.NewSealedBidHandler(smr.SealedBidService, smr.AuctionService)
+	listingHandler := handlers.NewListingHandler(smr.AuctionService)
+	offerHandler := handlers.NewOfferHandler(smr.OfferService)
+	auctionTemplateHandler := handlers.NewAuctionTemplateHandler(smr.AuctionService)
 	nftHandler := handlers.NewNFTHandler(smr.NFTService)
-	auctionTaskHandler := handlers.NewAuctionTaskHandler(smr.AuctionTaskScheduler)
+	auctionTaskHandler := handlers.NewAuctionTaskHandler(smr.AuctionTaskScheduler, smr.AuctionService)
+	adminHandler := handlers.NewAdminHandler(smr.UserService, smr.TokenService, smr.AuctionService, smr.ContractAdminService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(smr.APIKeyService)
 
-	// Auth routes (no authentication required) - Wallet login only
+	// Auth routes (no authentication required except logout) - Wallet login only
 	auth := rg.Group("/auth")
+	auth.Use(limit("auth"))
 	{
 		// Wallet login routes
 		auth.POST("/wallet/request-nonce", userHandler.RequestNonce)
 		auth.POST("/wallet/verify", userHandler.VerifyWalletLogin)
+		auth.POST("/refresh", userHandler.RefreshToken)
+		auth.POST("/email/verify", userHandler.VerifyEmail)
+		auth.POST("/logout", middleware.AuthMiddleware(), limit("user"), userHandler.Logout)
 	}
 
 	// Users routes
@@ -42,10 +68,22 @@ func Register(rg *gin.RouterGroup, cfg config.Config, smr *services.ServiceManag
 		
 		// 需要认证的接口
 		usersAuth := users.Group("")
-		usersAuth.Use(middleware.AuthMiddleware())
+		usersAuth.Use(middleware.AuthMiddleware(), limit("user"))
 		{
 			usersAuth.GET("/profile", userHandler.GetProfile)
 			usersAuth.PUT("/profile", userHandler.UpdateProfile)
+			usersAuth.POST("/onboarding", userHandler.CompleteOnboarding)
+			usersAuth.POST("/email/verification", userHandler.ResendEmailVerification)
+			usersAuth.DELETE("/account", userHandler.DeleteAccount)
+			usersAuth.GET("/sessions", userHandler.ListSessions)
+			usersAuth.DELETE("/sessions/:id", userHandler.RevokeSession)
+			usersAuth.GET("/wallets", userHandler.ListWallets)
+			usersAuth.POST("/wallets/link-nonce", userHandler.RequestLinkWalletNonce)
+			usersAuth.POST("/wallets", userHandler.LinkWallet)
+			usersAuth.DELETE("/wallets/:address", userHandler.UnlinkWallet)
+			usersAuth.GET("/api-keys", apiKeyHandler.List)
+			usersAuth.POST("/api-keys", apiKeyHandler.Create)
+			usersAuth.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
 		}
 	}
 
@@ -58,26 +96,73 @@ func Register(rg *gin.RouterGroup, cfg config.Config, smr *services.ServiceManag
 		auctions.GET("/stats", auctionHandler.GetAuctionSimpleStats) // 拍卖简单统计
 		auctions.GET("/nfts", auctionHandler.ListNFTs)
 		auctions.GET("/supported-tokens", auctionHandler.GetSupportedTokens)
-		auctions.GET("/token-price/:token", auctionHandler.GetTokenPrice)
-		auctions.POST("/convert-to-usd", auctionHandler.ConvertToUSD)
-		auctions.POST("/check-nft-approval", auctionHandler.CheckNFTApproval)
+		// 以下接口每次请求都会调用链上 RPC
+		auctions.GET("/token-price/:token", limit("rpc"), auctionHandler.GetTokenPrice)
+		auctions.POST("/convert-to-usd", limit("rpc"), auctionHandler.ConvertToUSD)
+		auctions.POST("/check-nft-approval", limit("rpc"), auctionHandler.CheckNFTApproval)
 		// More specific routes must come before wildcard routes
 		auctions.GET("/:id/detail", auctionHandler.GetDetailByID)
+		auctions.GET("/:id/history", auctionHandler.GetHistory)
 		auctions.GET("/:id/bids", bidHandler.GetBidsByAuctionID)
+		auctions.GET("/:id/sealed-bids", sealedBidHandler.List)
 		auctions.GET("/:id", auctionHandler.GetByID)
 
 		auctionsAuth := auctions.Group("")
-		auctionsAuth.Use(middleware.AuthMiddleware())
+		auctionsAuth.Use(middleware.AuthMiddleware(auctionScopes), limit("user"))
 		{
 			auctionsAuth.POST("", auctionHandler.Create)
+			auctionsAuth.POST("/batch", auctionHandler.BatchCreate)
 			auctionsAuth.PUT("/:id", auctionHandler.Update)
 			auctionsAuth.POST("/:id/cancel", auctionHandler.Cancel)
+			auctionsAuth.POST("/:id/cancel/tx", auctionHandler.SubmitCancelTx)
+			auctionsAuth.GET("/:id/cancellation", auctionHandler.GetCancellation)
+			auctionsAuth.POST("/:id/abandon", auctionHandler.Abandon)
+			auctionsAuth.POST("/:id/commit", auctionHandler.Commit)
+			auctionsAuth.POST("/:id/clone", auctionHandler.Clone)
+			auctionsAuth.POST("/:id/watch", auctionHandler.Watch)
+			auctionsAuth.DELETE("/:id/watch", auctionHandler.Unwatch)
+			auctionsAuth.GET("/:id/allowlist", auctionHandler.GetAllowlist)
+			auctionsAuth.PUT("/:id/allowlist", auctionHandler.UpdateAllowlist)
+			auctionsAuth.POST("/:id/sealed-bids/commit", sealedBidHandler.Commit)
+			auctionsAuth.POST("/:id/sealed-bids/reveal", sealedBidHandler.Reveal)
 			// 更具体的路由必须在通用路由之前
 			auctionsAuth.GET("/my/history", auctionHandler.GetUserAuctionHistory)
 			auctionsAuth.GET("/my", auctionHandler.GetUserAuctions)
 		}
 	}
 
+	// Auction templates routes (require authentication)
+	auctionTemplates := rg.Group("/auction-templates")
+	auctionTemplates.Use(middleware.AuthMiddleware(auctionScopes), limit("user"))
+	{
+		auctionTemplates.GET("", auctionTemplateHandler.List)
+		auctionTemplates.POST("", auctionTemplateHandler.Create)
+		auctionTemplates.PUT("/:id", auctionTemplateHandler.Update)
+		auctionTemplates.DELETE("/:id", auctionTemplateHandler.Delete)
+	}
+
+	// Fixed-price listings routes (require authentication)
+	// 固定价格出售与拍卖共用拍卖列表和详情接口（auctionType 为 fixed）
+	listings := rg.Group("/listings")
+	listings.Use(middleware.AuthMiddleware(auctionScopes), limit("user"))
+	{
+		listings.POST("", listingHandler.Create)
+		listings.PUT("/:id/price", listingHandler.UpdatePrice)
+		listings.POST("/:id/delist", listingHandler.Delist)
+	}
+
+	// Offers routes (require authentication)
+	// 对未上架 NFT 的链下签名报价，提交接口为 POST /nfts/:id/offers
+	offers := rg.Group("/offers")
+	offers.Use(middleware.AuthMiddleware(nftScopes), limit("user"))
+	{
+		offers.GET("/received", offerHandler.ListReceived)
+		offers.GET("/made", offerHandler.ListMade)
+		offers.POST("/:id/accept", offerHandler.Accept)
+		offers.POST("/:id/reject", offerHandler.Reject)
+		offers.POST("/:id/cancel", offerHandler.Cancel)
+	}
+
 	// Bids routes
 	bids := rg.Group("/bids")
 	{
@@ -86,15 +171,15 @@ func Register(rg *gin.RouterGroup, cfg config.Config, smr *services.ServiceManag
 
 	// Auction Task routes (require authentication)
 	auctionTasks := rg.Group("/auction-tasks")
-	auctionTasks.Use(middleware.AuthMiddleware())
+	auctionTasks.Use(middleware.AuthMiddleware(auctionScopes), limit("user"))
 	{
 		auctionTasks.GET("/:auctionId", auctionTaskHandler.GetTaskStatus)
-		auctionTasks.DELETE("/:auctionId", auctionTaskHandler.CancelAuctionTask)
+		auctionTasks.DELETE("/:auctionId", middleware.RequireRole(models.RoleModerator, models.RoleAdmin), auctionTaskHandler.CancelAuctionTask)
 	}
 
 	// NFT routes (require authentication)
 	nfts := rg.Group("/nfts")
-	nfts.Use(middleware.AuthMiddleware())
+	nfts.Use(middleware.AuthMiddleware(nftScopes), limit("user"))
 	{
 		nfts.GET("/owned", nftHandler.GetOwnedNFTs)
 		nfts.POST("/sync", nftHandler.SyncNFTs)
@@ -103,9 +188,34 @@ func Register(rg *gin.RouterGroup, cfg config.Config, smr *services.ServiceManag
 		nfts.GET("/my/list", nftHandler.GetMyNFTsList)
 		nfts.GET("/my/ownership/:nftId", nftHandler.GetMyNFTOwnershipByNFTID)
 		nfts.GET("/:id", nftHandler.GetNFTByID)
+		nfts.POST("/:id/offers", offerHandler.Create)
 		nfts.POST("/verify", nftHandler.VerifyOwnership)
 	}
 
+	// Admin routes (require authentication and moderator or admin role)
+	admin := rg.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), limit("user"), middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+	{
+		admin.POST("/auctions/:id/cancel", adminHandler.CancelAuction)
+		admin.PUT("/users/:id/status", adminHandler.UpdateUserStatus)
+	}
+
+	// Admin-only routes: contract management and role assignment
+	adminOnly := admin.Group("")
+	adminOnly.Use(middleware.RequireRole(models.RoleAdmin))
+	{
+		adminOnly.PUT("/users/:id/role", adminHandler.UpdateUserRole)
+		adminOnly.POST("/auctions/:id/force-end", adminHandler.ForceEndAuction)
+		adminOnly.POST("/contract/pause", adminHandler.PauseContract)
+		adminOnly.POST("/contract/unpause", adminHandler.UnpauseContract)
+		adminOnly.POST("/contract/platform-fee", adminHandler.SetPlatformFee)
+		adminOnly.POST("/contract/fee-tiers", adminHandler.SetFeeTiers)
+		adminOnly.POST("/contract/dynamic-fee", adminHandler.SetDynamicFee)
+		adminOnly.POST("/contract/price-feeds", adminHandler.SetPriceFeeds)
+		adminOnly.GET("/contract/actions", adminHandler.ListContractActions)
+		adminOnly.GET("/contract/actions/:id", adminHandler.GetContractAction)
+	}
+
 	// WebSocket 路由
 	rg.GET("/ws", func(c *gin.Context) {
 		websocket.ServeWS(smr.WSHub, c)
@@ -36,6 +36,10 @@ func New(cfg config.Config) (*Server, error) {
 	// }
 
 	engine := gin.New()
+	// 默认不信任任何代理，避免客户端伪造 X-Forwarded-For 绕过按 IP 限流
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 	engine.Use(gin.Recovery())
 	engine.Use(middleware.RequestLogger())
 	engine.Use(middleware.ValidationErrorHandler())
@@ -68,12 +72,30 @@ func (s *Server) Run() error {
 	// 启动拍卖任务调度器
 	s.serviceManager.StartAuctionTaskScheduler(ctx)
 
+	// 启动荷兰式拍卖价格推送
+	s.serviceManager.StartDutchPriceTicker(ctx)
+
+	// 启动待上架拍卖过期扫描
+	s.serviceManager.StartPendingSweeper(ctx)
+
 	// 启动区块链事件监听服务（如果已初始化）
 	if err := s.serviceManager.StartListenerService(); err != nil {
 		logger.Warn("failed to start listener service: %v", err)
 		logger.Warn("continuing without blockchain event listener")
 	}
 
+	// 恢复跟踪重启前已发送的拍卖取消交易
+	s.serviceManager.ResumeCancellationTracking()
+
+	// 恢复跟踪重启前已发送的合约管理交易
+	s.serviceManager.ResumeContractActionTracking()
+
+	// 恢复重启前未完成的打包成员转移
+	s.serviceManager.ResumeBundleDeliveries()
+
+	// 同步合约当前设置（暂停状态、手续费），补齐监听服务未运行期间的变更
+	go s.serviceManager.SyncContractSettings(ctx)
+
 	// 确保在服务器关闭时清理所有服务
 	defer func() {
 		if err := s.serviceManager.Close(); err != nil {
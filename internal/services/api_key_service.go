@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// apiKeyTouchInterval 更新最近使用时间的最小间隔
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyService 用户 API Key 管理与认证（每个 Key 的请求频率由认证中间件按 RateLimit 限制）
+type APIKeyService struct {
+	config config.APIKeyConfig
+}
+
+func NewAPIKeyService(cfg config.APIKeyConfig) *APIKeyService {
+	return &APIKeyService{
+		config: cfg,
+	}
+}
+
+// List 获取用户的 API Key（含已撤销和已过期的），按创建时间倒序
+func (s *APIKeyService) List(userID uint64) ([]models.APIKeyResponse, error) {
+	var keys []models.APIKey
+	if err := database.DB.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	result := make([]models.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		result = append(result, models.NewAPIKeyResponse(&keys[i]))
+	}
+	return result, nil
+}
+
+// Create 创建 API Key，Key 明文只在返回结果中出现一次
+func (s *APIKeyService) Create(userID uint64, payload models.CreateAPIKeyPayload) (*models.CreateAPIKeyResult, error) {
+	scopes := make([]string, 0, len(payload.Scopes))
+	for _, scope := range payload.Scopes {
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, errors.BadRequest(fmt.Sprintf("invalid scope: %s", scope))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	rateLimit := payload.RateLimit
+	if rateLimit == 0 {
+		rateLimit = s.config.DefaultRateLimit
+	}
+	if rateLimit > s.config.MaxRateLimit {
+		return nil, errors.BadRequest(fmt.Sprintf("rate limit cannot exceed %d requests per minute", s.config.MaxRateLimit))
+	}
+
+	now := time.Now()
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(now) {
+		return nil, errors.BadRequest("expiration time must be in the future")
+	}
+
+	var active int64
+	if err := database.DB.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&active).Error; err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+	if active >= int64(s.config.MaxKeysPerUser) {
+		return nil, errors.BadRequest(fmt.Sprintf("at most %d active API keys are allowed", s.config.MaxKeysPerUser))
+	}
+
+	id, err := randomToken(6)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	keyPrefix := models.APIKeyPrefix + id
+	plaintext := keyPrefix + "_" + secret
+
+	key := models.APIKey{
+		UserID:    userID,
+		Name:      payload.Name,
+		KeyPrefix: keyPrefix,
+		KeyHash:   hashToken(plaintext),
+		Scopes:    strings.Join(scopes, ","),
+		RateLimit: rateLimit,
+		ExpiresAt: payload.ExpiresAt,
+	}
+	if err := database.DB.Create(&key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	if err := database.DB.First(&key, key.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	logger.Info("API key created: userID=%d, keyID=%d, prefix=%s, scopes=%s", userID, key.ID, keyPrefix, key.Scopes)
+	return &models.CreateAPIKeyResult{
+		APIKeyResponse: models.NewAPIKeyResponse(&key),
+		Key:            plaintext,
+	}, nil
+}
+
+// Revoke 撤销用户的 API Key，撤销后立即失效
+func (s *APIKeyService) Revoke(userID uint64, keyID uint64) error {
+	var key models.APIKey
+	if err := database.DB.Where("id = ? AND user_id = ?", keyID, userID).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrNotFound.WithMessage("API key not found")
+		}
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	if err := database.DB.Model(&key).Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	logger.Info("API key revoked: userID=%d, keyID=%d, prefix=%s", userID, key.ID, key.KeyPrefix)
+	return nil
+}
+
+// AuthenticateAPIKey 验证 API Key：按前缀查找后比较哈希，检查撤销和过期状态，并记录最近使用时间和 IP
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, plaintext string, ip string) (*models.APIKeyPrincipal, error) {
+	invalid := errors.ErrUnauthorized.WithMessage("invalid or revoked API key")
+
+	rest, ok := strings.CutPrefix(plaintext, models.APIKeyPrefix)
+	if !ok {
+		return nil, invalid
+	}
+	id, _, ok := strings.Cut(rest, "_")
+	if !ok {
+		return nil, invalid
+	}
+	keyPrefix := models.APIKeyPrefix + id
+
+	var key models.APIKey
+	if err := database.DB.WithContext(ctx).Where("key_prefix = ?", keyPrefix).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, invalid
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(plaintext)), []byte(key.KeyHash)) != 1 {
+		return nil, invalid
+	}
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, invalid
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, key.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, invalid
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 最近使用时间按分钟更新，失败不影响请求
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := database.DB.WithContext(ctx).Model(&key).
+			Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error; err != nil {
+			logger.Warn("failed to update API key last used time: keyID=%d, error=%v", key.ID, err)
+		}
+	}
+
+	return &models.APIKeyPrincipal{
+		KeyID:     key.ID,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.EmailAddress(),
+		Role:      user.Role,
+		Scopes:    key.ScopeList(),
+		RateLimit: key.RateLimit,
+	}, nil
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/websocket"
+)
+
+// 邀请制拍卖（visibility = allowlist）：
+// 列表接口（List、ListPublic、ListNFTs）只返回 public 拍卖；详情接口和 WebSocket 拍卖房间只对卖家和白名单成员开放；
+// 合约无法限制出价者，非白名单钱包的链上出价在 BidPlaced 事件到达时标记为 flagged；
+// 拍卖的全局 WebSocket 消息（auction_created、auction_started 等）只推送给卖家和白名单成员。
+
+// normalizeVisibility 规范化可见性（默认 public）
+func normalizeVisibility(visibility string) string {
+	if visibility == "" {
+		return models.AuctionVisibilityPublic
+	}
+	return visibility
+}
+
+// buildAllowlistEntries 根据可见性设置构建白名单成员（非 allowlist 可见性不保存白名单）
+func buildAllowlistEntries(params models.VisibilityParams) ([]models.AuctionAllowlistEntry, error) {
+	if normalizeVisibility(params.Visibility) != models.AuctionVisibilityAllowlist {
+		return nil, nil
+	}
+
+	var entries []models.AuctionAllowlistEntry
+	seenWallets := make(map[string]bool)
+	for _, wallet := range params.AllowlistWallets {
+		if !common.IsHexAddress(wallet) {
+			return nil, errors.BadRequest(fmt.Sprintf("invalid allowlist wallet address: %s", wallet))
+		}
+		wallet = strings.ToLower(wallet)
+		if seenWallets[wallet] {
+			continue
+		}
+		seenWallets[wallet] = true
+		entries = append(entries, models.AuctionAllowlistEntry{WalletAddress: wallet})
+	}
+
+	seenUsers := make(map[uint64]bool)
+	for _, userID := range params.AllowlistUserIDs {
+		if userID == 0 || seenUsers[userID] {
+			continue
+		}
+		seenUsers[userID] = true
+		entries = append(entries, models.AuctionAllowlistEntry{UserID: userID})
+	}
+	return entries, nil
+}
+
+// saveAllowlistEntries 保存拍卖的白名单成员（需在事务中调用）
+func saveAllowlistEntries(tx *gorm.DB, auctionID string, entries []models.AuctionAllowlistEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	for i := range entries {
+		entries[i].ID = 0
+		entries[i].CreatedAt = nil
+		entries[i].AuctionID = auctionID
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to create allowlist entries: %w", err)
+	}
+	return nil
+}
+
+// loadAllowlistEntries 查询拍卖的白名单成员
+func loadAllowlistEntries(db *gorm.DB, auctionID string) ([]models.AuctionAllowlistEntry, error) {
+	var entries []models.AuctionAllowlistEntry
+	if err := db.Where("auction_id = ?", auctionID).Order("id ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load allowlist entries: %w", err)
+	}
+	return entries, nil
+}
+
+// isAllowlisted 判断用户是否在拍卖白名单中：按用户ID，或按用户的任一钱包（主钱包和已关联钱包）
+func isAllowlisted(db *gorm.DB, auctionID string, userID uint64) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	wallets, err := userWalletAddresses(db, userID)
+	if err != nil {
+		return false, err
+	}
+	query := db.Model(&models.AuctionAllowlistEntry{}).Where("auction_id = ?", auctionID)
+	if len(wallets) > 0 {
+		query = query.Where("(user_id = ? OR wallet_address IN ?)", userID, wallets)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check allowlist: %w", err)
+	}
+	return count > 0, nil
+}
+
+// isBidAllowlisted 判断出价是否来自白名单成员：出价钱包在白名单中，或出价用户按用户ID在白名单中
+// 按钱包添加的成员只能用该钱包出价（接受报价创建的出售只允许报价钱包购买）
+func isBidAllowlisted(db *gorm.DB, auctionID string, bid *models.Bid) (bool, error) {
+	query := db.Model(&models.AuctionAllowlistEntry{}).Where("auction_id = ?", auctionID)
+	if bid.UserID > 0 {
+		query = query.Where("(wallet_address = ? OR user_id = ?)", strings.ToLower(bid.WalletAddress), bid.UserID)
+	} else {
+		query = query.Where("wallet_address = ?", strings.ToLower(bid.WalletAddress))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check allowlist: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CanAccess 判断用户是否可以访问拍卖（userID 为 0 表示未登录）
+// 非邀请制拍卖所有人可访问；邀请制拍卖只有卖家和白名单成员可访问
+func (s *AuctionService) CanAccess(auction *models.Auction, userID uint64) (bool, error) {
+	if !auction.RequiresMembership() {
+		return true, nil
+	}
+	if userID == 0 {
+		return false, nil
+	}
+	if auction.UserID == userID {
+		return true, nil
+	}
+	return isAllowlisted(database.DB, auction.AuctionID, userID)
+}
+
+// CheckAccess 校验用户是否可以查看拍卖详情，不可访问时返回 Forbidden
+func (s *AuctionService) CheckAccess(auction *models.Auction, userID uint64) error {
+	ok, err := s.CanAccess(auction, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Forbidden("this auction is invite-only")
+	}
+	return nil
+}
+
+// CanSubscribeRoom 判断 WebSocket 客户端是否可以订阅房间（作为 Hub 的订阅校验函数）
+// 只限制邀请制拍卖的 auction:{auctionId} 房间，其他房间不限制
+func (s *AuctionService) CanSubscribeRoom(userID uint, roomID string) bool {
+	auctionID, ok := strings.CutPrefix(roomID, "auction:")
+	if !ok {
+		return true
+	}
+
+	var auction models.Auction
+	if err := database.DB.Select("auction_id", "user_id", "visibility").
+		Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			logger.Error("failed to check room subscription: roomID=%s, error=%v", roomID, err)
+			return false
+		}
+		return true
+	}
+
+	allowed, err := s.CanAccess(&auction, uint64(userID))
+	if err != nil {
+		logger.Error("failed to check room subscription: roomID=%s, error=%v", roomID, err)
+		return false
+	}
+	return allowed
+}
+
+// GetAllowlist 卖家查看拍卖的可见性和白名单
+func (s *AuctionService) GetAllowlist(userID uint64, auctionID string) (*models.AuctionAllowlistResponse, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadAllowlistEntries(database.DB, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuctionAllowlistResponse{
+		AuctionID:  auction.AuctionID,
+		Visibility: normalizeVisibility(auction.Visibility),
+		Entries:    entries,
+	}, nil
+}
+
+// UpdateAllowlist 卖家修改拍卖的可见性并整体替换白名单
+// 已结束、已取消或已过期的拍卖不能修改
+func (s *AuctionService) UpdateAllowlist(userID uint64, auctionID string, params models.VisibilityParams) (*models.AuctionAllowlistResponse, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status == AuctionStatusEnded || auction.Status == AuctionStatusCancelled || auction.Status == AuctionStatusExpired {
+		return nil, errors.BadRequest(fmt.Sprintf("cannot change visibility of auction in status %s", auction.Status))
+	}
+
+	visibility := normalizeVisibility(params.Visibility)
+	entries, err := buildAllowlistEntries(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(auction).Update("visibility", visibility).Error; err != nil {
+			return fmt.Errorf("failed to update visibility: %w", err)
+		}
+		if err := tx.Where("auction_id = ?", auctionID).Delete(&models.AuctionAllowlistEntry{}).Error; err != nil {
+			return fmt.Errorf("failed to clear allowlist: %w", err)
+		}
+		return saveAllowlistEntries(tx, auctionID, entries)
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("auction allowlist updated: auctionID=%s, visibility=%s, entries=%d", auctionID, visibility, len(entries))
+	return s.GetAllowlist(userID, auctionID)
+}
+
+// getSellerAuction 查询属于当前用户的拍卖
+func (s *AuctionService) getSellerAuction(userID uint64, auctionID string) (*models.Auction, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Forbidden("auction not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	return &auction, nil
+}
+
+// FlagBidIfNotAllowlisted 邀请制拍卖中，标记非白名单钱包的链上出价（BidPlaced 事件到达时调用）
+// 返回出价是否被标记
+func (s *AuctionService) FlagBidIfNotAllowlisted(bid *models.Bid) (bool, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", bid.AuctionID).First(&auction).Error; err != nil {
+		return false, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.RequiresMembership() {
+		return false, nil
+	}
+
+	allowed, err := isBidAllowlisted(database.DB, auction.AuctionID, bid)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		return false, nil
+	}
+
+	if err := flagBid(database.DB, bid, models.BidFlagNotAllowlisted); err != nil {
+		return false, err
+	}
+	logger.Warn("bid from non-allowlisted wallet flagged: auctionID=%s, bidID=%d, wallet=%s",
+		bid.AuctionID, bid.ID, bid.WalletAddress)
+	return true, nil
+}
+
+// allowlistMemberUserIDs 拍卖白名单成员的用户ID：按用户ID添加的成员，以及白名单钱包所属的用户（主钱包或已关联钱包）
+func allowlistMemberUserIDs(db *gorm.DB, auctionID string) ([]uint64, error) {
+	entries, err := loadAllowlistEntries(db, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []uint64
+	var wallets []string
+	for _, entry := range entries {
+		if entry.UserID > 0 {
+			userIDs = append(userIDs, entry.UserID)
+		}
+		if entry.WalletAddress != "" {
+			wallets = append(wallets, entry.WalletAddress)
+		}
+	}
+	if len(wallets) == 0 {
+		return userIDs, nil
+	}
+
+	var owners []uint64
+	if err := db.Model(&models.User{}).Where("wallet_address IN ?", wallets).Pluck("id", &owners).Error; err != nil {
+		return nil, fmt.Errorf("failed to get allowlist wallet owners: %w", err)
+	}
+	userIDs = append(userIDs, owners...)
+	var linkedOwners []uint64
+	if err := db.Model(&models.UserWallet{}).Where("wallet_address IN ?", wallets).Pluck("user_id", &linkedOwners).Error; err != nil {
+		return nil, fmt.Errorf("failed to get allowlist wallet owners: %w", err)
+	}
+	return append(userIDs, linkedOwners...), nil
+}
+
+// broadcastAuctionMessage 推送拍卖的全局消息：非邀请制拍卖广播给所有客户端，邀请制拍卖只推送给卖家和白名单成员
+func broadcastAuctionMessage(hub *websocket.Hub, auction *models.Auction, message interface{}) error {
+	if auction == nil || !auction.RequiresMembership() {
+		return hub.BroadcastMessage(message)
+	}
+
+	userIDs, err := allowlistMemberUserIDs(database.DB, auction.AuctionID)
+	if err != nil {
+		return err
+	}
+	sent := map[uint64]bool{}
+	for _, userID := range append([]uint64{auction.UserID}, userIDs...) {
+		if userID == 0 || sent[userID] {
+			continue
+		}
+		sent[userID] = true
+		if err := hub.SendToUser(uint(userID), message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
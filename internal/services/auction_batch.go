@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/contracts/erc721_nft"
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/utils"
+)
+
+// 批量创建拍卖：所有 NFT 的 ownerOf/getApproved 通过一次 JSON-RPC 批量请求读取，
+// 起拍价的 USD 价值按代币缓存预言机报价后在本地换算，同一批次每种支付代币只读取一次链上价格；
+// 校验通过的拍卖在同一个数据库事务中创建（每个拍卖使用独立的保存点，单个失败不影响其他拍卖）
+
+// nftChainCheck 批量链上校验的单个 NFT
+type nftChainCheck struct {
+	nftID         string
+	nftAddress    string
+	tokenID       uint64
+	walletAddress string // 应持有该 NFT 的卖家钱包（主钱包或已关联钱包）
+	err           error  // 校验失败原因
+	unapproved    bool   // 是否因未授权给平台合约而失败
+}
+
+// BatchCreate 批量创建拍卖，返回每个拍卖的结果和仍需授权的 NFT 列表
+func (s *AuctionService) BatchCreate(userID uint64, payload models.BatchAuctionPayload) (*models.BatchAuctionResponse, error) {
+	items := payload.Items
+	if len(items) == 0 {
+		return nil, errors.BadRequest("items are required")
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	resp := &models.BatchAuctionResponse{
+		Results:       make([]models.BatchAuctionResult, len(items)),
+		NeedsApproval: []models.BatchApprovalItem{},
+	}
+	for i, item := range items {
+		resp.Results[i] = models.BatchAuctionResult{Index: i, NFTID: item.NFTID}
+	}
+
+	// 1. 请求内校验：不支持打包拍卖，同一 NFT 只能出现一次
+	var valid []int
+	seen := make(map[string]int)
+	for i, item := range items {
+		if len(item.BundleNFTs) > 0 {
+			setBatchError(&resp.Results[i], errors.BadRequest("bundle auctions are not supported in batch creation"))
+			continue
+		}
+		if first, ok := seen[item.NFTID]; ok {
+			setBatchError(&resp.Results[i], errors.BadRequest(fmt.Sprintf("duplicate NFT in batch, same as item %d", first)))
+			continue
+		}
+		seen[item.NFTID] = i
+		valid = append(valid, i)
+	}
+
+	// 2. 批量链上校验（草稿不校验链上状态）
+	var checks []*nftChainCheck
+	checkIndexes := make(map[int]*nftChainCheck)
+	for _, i := range valid {
+		if items[i].Draft {
+			continue
+		}
+		walletAddress, err := sellerWalletAddress(database.DB, &user, items[i].NFTID)
+		if err != nil {
+			return nil, err
+		}
+		check := &nftChainCheck{nftID: items[i].NFTID, nftAddress: items[i].NFTAddress, tokenID: items[i].TokenID, walletAddress: walletAddress}
+		checks = append(checks, check)
+		checkIndexes[i] = check
+	}
+	if err := s.batchVerifyNFTsOnChain(checks); err != nil {
+		return nil, err
+	}
+
+	// 3. 逐个执行数据库校验并构建拍卖记录
+	pricer := newTokenUSDPricer(&s.config, s.ethClient.GetClient())
+	prepared := make(map[int]*preparedAuction)
+	var order []int
+	for _, i := range valid {
+		if check, ok := checkIndexes[i]; ok && check.err != nil {
+			setBatchError(&resp.Results[i], check.err)
+			if check.unapproved {
+				resp.Results[i].NeedsApproval = true
+				resp.NeedsApproval = append(resp.NeedsApproval, models.BatchApprovalItem{
+					NFTID:      check.nftID,
+					NFTAddress: check.nftAddress,
+					TokenID:    check.tokenID,
+				})
+			}
+			continue
+		}
+		p, err := s.prepareAuction(userID, items[i], false, pricer)
+		if err != nil {
+			setBatchError(&resp.Results[i], err)
+			continue
+		}
+		prepared[i] = p
+		order = append(order, i)
+	}
+
+	// 4. 同一事务中创建，单个拍卖写入失败（如在线锁冲突）时回滚到保存点
+	if len(order) > 0 {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			for _, i := range order {
+				if err := tx.Transaction(func(itemTx *gorm.DB) error {
+					return savePreparedAuction(itemTx, prepared[i])
+				}); err != nil {
+					// nft_locks 主键冲突：该 NFT（主 NFT 或打包成员）已被并发创建的拍卖锁定
+					if strings.Contains(err.Error(), "Duplicate entry") {
+						err = errors.BadRequest("该 NFT 已经在拍卖中")
+					}
+					setBatchError(&resp.Results[i], err)
+					continue
+				}
+				resp.Results[i].Success = true
+				resp.Results[i].Auction = &prepared[i].auction
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create auctions: %w", err)
+		}
+	}
+
+	for _, result := range resp.Results {
+		if result.Success {
+			resp.Created++
+		} else {
+			resp.Failed++
+		}
+	}
+	logger.Info("batch auctions created: userID=%d, created=%d, failed=%d, needsApproval=%d",
+		userID, resp.Created, resp.Failed, len(resp.NeedsApproval))
+	return resp, nil
+}
+
+// setBatchError 记录单个拍卖的失败原因（非业务错误只返回通用提示，详细信息写日志）
+func setBatchError(result *models.BatchAuctionResult, err error) {
+	result.Success = false
+	if appErr, ok := errors.IsAppError(err); ok {
+		result.Error = appErr.Message
+		return
+	}
+	logger.Error("batch auction item failed: index=%d, nftID=%s, error=%v", result.Index, result.NFTID, err)
+	result.Error = "failed to create auction"
+}
+
+// batchVerifyNFTsOnChain 批量读取 NFT 的 ownerOf 和 getApproved，校验 NFT 归属 check.walletAddress 且已单独授权给平台合约
+// 单个 NFT 的校验结果写入 check.err；只有 RPC 请求本身失败时返回 error
+func (s *AuctionService) batchVerifyNFTsOnChain(checks []*nftChainCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	nftABI, err := erc721_nft.MyNFTMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to load NFT ABI: %w", err)
+	}
+
+	msgs := make([]ethereum.CallMsg, 0, len(checks)*2)
+	for _, check := range checks {
+		to := common.HexToAddress(check.nftAddress)
+		tokenID := new(big.Int).SetUint64(check.tokenID)
+		ownerOfData, err := nftABI.Pack("ownerOf", tokenID)
+		if err != nil {
+			return fmt.Errorf("failed to pack ownerOf call: %w", err)
+		}
+		getApprovedData, err := nftABI.Pack("getApproved", tokenID)
+		if err != nil {
+			return fmt.Errorf("failed to pack getApproved call: %w", err)
+		}
+		msgs = append(msgs,
+			ethereum.CallMsg{To: &to, Data: ownerOfData},
+			ethereum.CallMsg{To: &to, Data: getApprovedData})
+	}
+
+	outputs, callErrs, err := s.ethClient.BatchCallContract(context.Background(), msgs)
+	if err != nil {
+		return err
+	}
+
+	platformContractAddress := common.HexToAddress(s.config.AuctionContractAddress)
+	for i, check := range checks {
+		ownerOut, approvedOut := outputs[2*i], outputs[2*i+1]
+		if callErrs[2*i] != nil || len(ownerOut) == 0 {
+			check.err = errors.BadRequest(fmt.Sprintf("failed to get NFT owner for token %d. NFT ID: %s", check.tokenID, check.nftID))
+			continue
+		}
+		chainOwner := common.BytesToAddress(ownerOut)
+		if strings.ToLower(chainOwner.Hex()) != check.walletAddress {
+			check.err = errors.BadRequest(
+				fmt.Sprintf("NFT token %d is not owned by your wallet address. Chain owner: %s, Your wallet: %s. NFT ID: %s",
+					check.tokenID, chainOwner.Hex(), check.walletAddress, check.nftID))
+			continue
+		}
+		if callErrs[2*i+1] != nil || len(approvedOut) == 0 {
+			check.err = errors.BadRequest(fmt.Sprintf("failed to check GetApproved for token %d. NFT ID: %s", check.tokenID, check.nftID))
+			continue
+		}
+		if common.BytesToAddress(approvedOut) != platformContractAddress {
+			check.unapproved = true
+			check.err = errors.BadRequest(
+				fmt.Sprintf("NFT token %d has not been approved for the platform contract. Please approve the NFT first. NFT ID: %s, Contract: %s",
+					check.tokenID, check.nftID, check.nftAddress))
+		}
+	}
+	return nil
+}
+
+// tokenUSDQuote 代币的预言机报价（读取失败时记录错误，同一批次不再重试）
+type tokenUSDQuote struct {
+	tokenDecimals uint8
+	price         *big.Int
+	priceDecimals uint8
+	err           error
+}
+
+// tokenUSDPricer 按代币缓存预言机报价，换算公式与合约 convertToUSDValue 一致：
+// usdValue = amount * price / 10^tokenDecimals（结果为价格精度的整数）
+type tokenUSDPricer struct {
+	config *config.EthereumConfig
+	client *ethclient.Client
+	quotes map[string]*tokenUSDQuote
+}
+
+func newTokenUSDPricer(ethConfig *config.EthereumConfig, client *ethclient.Client) *tokenUSDPricer {
+	return &tokenUSDPricer{
+		config: ethConfig,
+		client: client,
+		quotes: make(map[string]*tokenUSDQuote),
+	}
+}
+
+// quote 获取代币报价，每种代币只读取一次链上数据
+func (p *tokenUSDPricer) quote(tokenAddress string) (*tokenUSDQuote, error) {
+	key := strings.ToLower(tokenAddress)
+	if q, ok := p.quotes[key]; ok {
+		return q, q.err
+	}
+
+	q := &tokenUSDQuote{}
+	p.quotes[key] = q
+	_, _, tokenDecimals, _, err := utils.ERC20Token(p.client, tokenAddress, p.config.ChainID)
+	if err != nil {
+		q.err = fmt.Errorf("failed to get ERC20 token: %w", err)
+		return q, q.err
+	}
+	auctionContract, err := my_auction.NewMyXAuctionV2(common.HexToAddress(p.config.AuctionContractAddress), p.client)
+	if err != nil {
+		q.err = fmt.Errorf("failed to create auction contract: %w", err)
+		return q, q.err
+	}
+	price, err := auctionContract.GetTokenPrice(&bind.CallOpts{}, common.HexToAddress(tokenAddress))
+	if err != nil {
+		q.err = fmt.Errorf("failed to get token price: %w", err)
+		return q, q.err
+	}
+	q.tokenDecimals = tokenDecimals
+	q.price = price.Price
+	q.priceDecimals = price.Decimals
+	return q, nil
+}
+
+// convert 将代币金额换算为USD（返回值与 ConvertTokenAmountToUSD 相同）
+func (p *tokenUSDPricer) convert(tokenAddress string, amount float64) (*models.ConvertToUSDResponse, error) {
+	amountDecimal := decimal.NewFromFloat(amount)
+	if amountDecimal.LessThanOrEqual(decimal.Zero) {
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	q, err := p.quote(tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(q.tokenDecimals)), nil)
+	amountUnit := amountDecimal.Mul(decimal.NewFromBigInt(scale, 0)).BigInt()
+	usdValue := new(big.Int).Div(new(big.Int).Mul(amountUnit, q.price), scale)
+	usdValueDecimal := decimal.NewFromBigInt(usdValue, int32(q.priceDecimals)*-1)
+
+	return &models.ConvertToUSDResponse{
+		Token:         tokenAddress,
+		Amount:        amountDecimal.InexactFloat64(),
+		AmountUSD:     usdValueDecimal.InexactFloat64(),
+		AmountUnitUSD: usdValue.Uint64(),
+		AmountUSDStr:  usdValueDecimal.StringFixed(int32(q.priceDecimals)),
+	}, nil
+}
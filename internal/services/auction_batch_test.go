@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestTokenUSDPricerConvert(t *testing.T) {
+	pricer := newTokenUSDPricer(nil, nil)
+	pricer.quotes["0xtoken"] = &tokenUSDQuote{tokenDecimals: 18, price: big.NewInt(300000000000), priceDecimals: 8}
+	pricer.quotes["0xusdc"] = &tokenUSDQuote{tokenDecimals: 6, price: big.NewInt(100000000), priceDecimals: 8}
+	pricer.quotes["0xbroken"] = &tokenUSDQuote{err: errors.New("no price feed")}
+
+	tests := []struct {
+		name     string
+		token    string
+		amount   float64
+		wantUnit uint64
+		wantStr  string
+		wantErr  bool
+	}{
+		{"eth", "0xtoken", 1.5, 450000000000, "4500.00000000", false},
+		{"token key is case-insensitive", "0xTOKEN", 0.001, 300000000, "3.00000000", false},
+		{"usdc", "0xusdc", 12.34, 1234000000, "12.34000000", false},
+		{"zero amount", "0xtoken", 0, 0, "", true},
+		{"cached quote error", "0xbroken", 1, 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pricer.convert(tt.token, tt.amount)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("convert() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convert() error = %v", err)
+			}
+			if got.AmountUnitUSD != tt.wantUnit || got.AmountUSDStr != tt.wantStr {
+				t.Errorf("convert() = %d (%s), want %d (%s)", got.AmountUnitUSD, got.AmountUSDStr, tt.wantUnit, tt.wantStr)
+			}
+		})
+	}
+}
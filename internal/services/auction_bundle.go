@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/contracts/erc721_nft"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/models"
+)
+
+// maxBundleSize 打包拍卖最多包含的 NFT 数量（含主 NFT）
+const maxBundleSize = 10
+
+// newBundleItem 根据 NFT 元数据构建打包成员
+func newBundleItem(nft models.NFT, position int) models.AuctionBundleItem {
+	return models.AuctionBundleItem{
+		Position:       uint64(position),
+		NFTID:          nft.NFTID,
+		NFTAddress:     strings.ToLower(nft.ContractAddress),
+		TokenID:        nft.TokenID,
+		ContractName:   nft.ContractName,
+		ContractSymbol: nft.ContractSymbol,
+		TokenURI:       nft.TokenURI,
+		NftName:        nft.NftName,
+		Image:          nft.Image,
+		Description:    nft.Description,
+	}
+}
+
+// prepareBundleItems 验证打包拍卖的成员 NFT 并构建打包成员列表（主 NFT 位于第一位）
+// 每个成员都必须：属于卖家且状态为 holding、不在其他拍卖中、链上归属卖家钱包并已授权给平台签名账户
+// 草稿只校验所有权，在线锁和链上状态在提交草稿时由 verifyBundleItems 校验
+func (s *AuctionService) prepareBundleItems(userID uint64, walletAddress string, lead models.NFT,
+	members []models.BundleNFTPayload, draft bool) ([]models.AuctionBundleItem, error) {
+	if len(members)+1 > maxBundleSize {
+		return nil, errors.BadRequest(fmt.Sprintf("a bundle can contain at most %d NFTs", maxBundleSize))
+	}
+
+	items := []models.AuctionBundleItem{newBundleItem(lead, 0)}
+	seen := map[string]bool{lead.NFTID: true}
+	for i, member := range members {
+		if seen[member.NFTID] {
+			return nil, errors.BadRequest(fmt.Sprintf("duplicate NFT in bundle: %s", member.NFTID))
+		}
+		seen[member.NFTID] = true
+
+		var ownership models.NFTOwnership
+		if err := database.DB.Where("nft_id = ? AND user_id = ? AND status = ?",
+			member.NFTID, userID, models.NFTOwnershipStatusHolding).
+			Preload("NFT").
+			First(&ownership).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.NotFound(
+					fmt.Sprintf("NFT not found or you don't own it: NFT ID %s. Please sync your NFTs first", member.NFTID))
+			}
+			return nil, fmt.Errorf("failed to verify NFT ownership: %w", err)
+		}
+		nft := ownership.NFT
+		if nft.NFTID == "" {
+			return nil, fmt.Errorf("NFT data not found for NFT ID: %s", member.NFTID)
+		}
+		if !strings.EqualFold(nft.ContractAddress, member.NFTAddress) || nft.TokenID != member.TokenID {
+			return nil, errors.BadRequest(
+				fmt.Sprintf("NFT address or token id does not match NFT ID %s", member.NFTID))
+		}
+
+		item := newBundleItem(nft, i+1)
+		if !draft {
+			if err := s.verifyBundleItem(walletAddress, item); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// verifyBundleItem 校验打包成员未被其他拍卖锁定，且链上归属卖家钱包并已授权给平台签名账户
+func (s *AuctionService) verifyBundleItem(walletAddress string, item models.AuctionBundleItem) error {
+	lockedBy, err := findOnlineLock(database.DB, item.NFTID)
+	if err != nil {
+		return err
+	}
+	if lockedBy != "" {
+		return errors.BadRequest(
+			fmt.Sprintf("该 NFT 已经在拍卖中，NFT ID: %s，拍卖ID: %s", item.NFTID, lockedBy))
+	}
+	return s.verifyBundleMemberOnChain(walletAddress, item)
+}
+
+// verifyBundleMemberOnChain 校验成员 NFT 链上归属卖家钱包，并已授权（approve 或 setApprovalForAll）给平台签名账户
+// 合约每场拍卖只托管主 NFT，成员在成交后由平台签名账户从卖家钱包转给获胜者
+func (s *AuctionService) verifyBundleMemberOnChain(walletAddress string, item models.AuctionBundleItem) error {
+	signer, err := s.platformSignerAddress()
+	if err != nil {
+		return err
+	}
+	nftContract, err := erc721_nft.NewMyNFT(common.HexToAddress(item.NFTAddress), s.ethClient.GetClient())
+	if err != nil {
+		return fmt.Errorf("failed to create NFT contract instance: %w", err)
+	}
+
+	opts := &bind.CallOpts{Context: context.Background()}
+	tokenID := new(big.Int).SetUint64(item.TokenID)
+	chainOwner, err := nftContract.OwnerOf(opts, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to get NFT owner for token %d: %w", item.TokenID, err)
+	}
+	if strings.ToLower(chainOwner.Hex()) != walletAddress {
+		return errors.BadRequest(
+			fmt.Sprintf("NFT token %d is not owned by your wallet address. Chain owner: %s, Your wallet: %s. NFT ID: %s",
+				item.TokenID, chainOwner.Hex(), walletAddress, item.NFTID))
+	}
+
+	approved, err := nftContract.GetApproved(opts, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to check GetApproved for token %d: %w", item.TokenID, err)
+	}
+	if approved == signer {
+		return nil
+	}
+	approvedForAll, err := nftContract.IsApprovedForAll(opts, chainOwner, signer)
+	if err != nil {
+		return fmt.Errorf("failed to check IsApprovedForAll for token %d: %w", item.TokenID, err)
+	}
+	if !approvedForAll {
+		return errors.BadRequest(
+			fmt.Sprintf("bundle NFT token %d has not been approved for the platform signer %s. Please approve it first. NFT ID: %s, Contract: %s",
+				item.TokenID, strings.ToLower(signer.Hex()), item.NFTID, item.NFTAddress))
+	}
+	return nil
+}
+
+// platformSignerAddress 平台私钥对应的账户地址
+func (s *AuctionService) platformSignerAddress() (common.Address, error) {
+	auth, err := s.ethClient.GetAuth(context.Background(), s.config.PlatformPrivateKey)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get platform signer: %w", err)
+	}
+	return auth.From, nil
+}
+
+// auctionNFTIDs 返回拍卖涉及的全部 NFT ID（打包拍卖返回所有成员，否则只有主 NFT）
+func auctionNFTIDs(db *gorm.DB, auction *models.Auction) ([]string, error) {
+	if auction.BundleSize == 0 {
+		return []string{auction.NFTID}, nil
+	}
+	var nftIDs []string
+	if err := db.Model(&models.AuctionBundleItem{}).
+		Where("auction_id = ?", auction.AuctionID).
+		Order("position ASC").
+		Pluck("nft_id", &nftIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load bundle items: %w", err)
+	}
+	return nftIDs, nil
+}
+
+// loadBundleItems 加载拍卖的打包成员（按 Position 排序）
+func loadBundleItems(db *gorm.DB, auctionID string) ([]models.AuctionBundleItem, error) {
+	var items []models.AuctionBundleItem
+	if err := db.Where("auction_id = ?", auctionID).Order("position ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to load bundle items: %w", err)
+	}
+	return items, nil
+}
+
+// preloadBundleItems 列表查询时预加载打包成员
+func preloadBundleItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
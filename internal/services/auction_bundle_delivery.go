@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/contracts/erc721_nft"
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+const (
+	// bundleDeliveryPollInterval 打包成员转移交易回执轮询间隔
+	bundleDeliveryPollInterval = 5 * time.Second
+	// bundleDeliveryTimeout 打包成员转移交易回执最长等待时间
+	bundleDeliveryTimeout = 10 * time.Minute
+)
+
+// 打包拍卖成交交付：合约每场拍卖只托管主 NFT，AuctionEnded 事件（结束任务结算或管理员强制结束）确认成交后，
+// 平台签名账户对每个成员调用 transferFrom(卖家, 获胜者)。成员状态 pending -> submitted -> delivered / failed，
+// 转移完成或失败后释放成员的在线锁；服务重启时恢复未完成的转移（ResumeBundleDeliveries）
+
+// OnEventAuctionEnded 处理合约 AuctionEnded 事件：打包拍卖成交时开始把成员 NFT 转给获胜者
+func (s *AuctionService) OnEventAuctionEnded(auctionContractId uint64, winner common.Address) error {
+	if winner == (common.Address{}) {
+		return nil
+	}
+	var auction models.Auction
+	if err := database.DB.Where("contract_auction_id = ?", auctionContractId).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get auction: %w", err)
+	}
+	if auction.BundleSize == 0 {
+		return nil
+	}
+
+	// 只有首次处理该事件时才开始转移（重复事件不会重复发送交易）
+	result := database.DB.Model(&models.AuctionBundleItem{}).
+		Where("auction_id = ? AND position > 0 AND delivery_status = ''", auction.AuctionID).
+		Update("delivery_status", models.BundleDeliveryPending)
+	if result.Error != nil {
+		return fmt.Errorf("failed to schedule bundle delivery: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("bundle auction settled, delivering members: auctionID=%s, winner=%s, members=%d",
+			auction.AuctionID, winner.Hex(), result.RowsAffected)
+		go s.deliverBundleMembers(auction.AuctionID)
+	}
+	return nil
+}
+
+// ResumeBundleDeliveries 服务启动时恢复打包成员转移：继续跟踪 submitted 状态成员的交易回执，重新发送 pending 状态成员的交易
+func (s *AuctionService) ResumeBundleDeliveries() error {
+	var items []models.AuctionBundleItem
+	if err := database.DB.Where("delivery_status IN ?",
+		[]string{models.BundleDeliveryPending, models.BundleDeliverySubmitted}).
+		Find(&items).Error; err != nil {
+		return fmt.Errorf("failed to load undelivered bundle items: %w", err)
+	}
+
+	pending := map[string]bool{}
+	for _, item := range items {
+		if item.DeliveryStatus == models.BundleDeliverySubmitted && item.DeliveryTxHash != "" {
+			go s.trackBundleDelivery(item, common.HexToHash(item.DeliveryTxHash))
+			continue
+		}
+		pending[item.AuctionID] = true
+	}
+	for auctionID := range pending {
+		go s.deliverBundleMembers(auctionID)
+	}
+	if len(items) > 0 {
+		logger.Info("resumed delivery of %d bundle item(s)", len(items))
+	}
+	return nil
+}
+
+// deliverBundleMembers 为拍卖中 pending 状态的成员发送转移交易，卖家和获胜者以链上拍卖记录为准
+func (s *AuctionService) deliverBundleMembers(auctionID string) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		logger.Error("failed to get bundle auction for delivery: auctionID=%s, error=%v", auctionID, err)
+		return
+	}
+	myAuction, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), s.ethClient.GetClient())
+	if err != nil {
+		logger.Error("failed to create auction contract instance: %v", err)
+		return
+	}
+	// 读取失败时成员保持 pending，服务重启时重试
+	auctionOnChain, err := myAuction.GetAuction(&bind.CallOpts{}, new(big.Int).SetUint64(auction.ContractAuctionID))
+	if err != nil {
+		logger.Error("failed to get auction on chain for bundle delivery: auctionID=%s, error=%v", auctionID, err)
+		return
+	}
+	if auctionOnChain.HighestBidder == (common.Address{}) {
+		logger.Error("bundle auction has no winner on chain, skipping delivery: auctionID=%s", auctionID)
+		return
+	}
+
+	var items []models.AuctionBundleItem
+	if err := database.DB.Where("auction_id = ? AND delivery_status = ?", auctionID, models.BundleDeliveryPending).
+		Order("position ASC").Find(&items).Error; err != nil {
+		logger.Error("failed to load pending bundle items: auctionID=%s, error=%v", auctionID, err)
+		return
+	}
+	for _, item := range items {
+		s.deliverBundleMember(item, auctionOnChain.Seller, auctionOnChain.HighestBidder)
+	}
+}
+
+// deliverBundleMember 发送单个成员的转移交易；成员已在获胜者钱包中（如重启前交易已发送但未记录）时直接标记为 delivered
+func (s *AuctionService) deliverBundleMember(item models.AuctionBundleItem, seller common.Address, winner common.Address) {
+	nftContract, err := erc721_nft.NewMyNFT(common.HexToAddress(item.NFTAddress), s.ethClient.GetClient())
+	if err != nil {
+		finishBundleDelivery(item, models.BundleDeliveryPending, models.BundleDeliveryFailed, cancellationErrorMessage(err))
+		return
+	}
+	tokenID := new(big.Int).SetUint64(item.TokenID)
+	if owner, err := nftContract.OwnerOf(&bind.CallOpts{}, tokenID); err == nil && owner == winner {
+		finishBundleDelivery(item, models.BundleDeliveryPending, models.BundleDeliveryDelivered, "")
+		return
+	}
+
+	auth, err := s.ethClient.GetAuth(context.Background(), s.config.PlatformPrivateKey)
+	if err != nil {
+		logger.Error("failed to get transaction auth for bundle delivery: itemID=%d, error=%v", item.ID, err)
+		return
+	}
+	// 卖家撤销授权或已转走 NFT 时预估 gas 失败，在此标记为 failed
+	tx, err := nftContract.TransferFrom(auth, seller, winner, tokenID)
+	if err != nil {
+		finishBundleDelivery(item, models.BundleDeliveryPending, models.BundleDeliveryFailed, cancellationErrorMessage(err))
+		logger.Warn("failed to send bundle delivery transaction: auctionID=%s, nftID=%s, error=%v", item.AuctionID, item.NFTID, err)
+		return
+	}
+
+	if err := database.DB.Model(&models.AuctionBundleItem{}).
+		Where("id = ? AND delivery_status = ?", item.ID, models.BundleDeliveryPending).
+		Updates(map[string]interface{}{
+			"delivery_status":  models.BundleDeliverySubmitted,
+			"delivery_tx_hash": tx.Hash().Hex(),
+		}).Error; err != nil {
+		// 成员保持 pending，服务重启时发现 NFT 已在获胜者钱包中会直接标记为 delivered
+		logger.Error("failed to record bundle delivery transaction: itemID=%d, txHash=%s, error=%v", item.ID, tx.Hash().Hex(), err)
+		return
+	}
+	logger.Info("bundle delivery transaction sent: auctionID=%s, nftID=%s, to=%s, txHash=%s",
+		item.AuctionID, item.NFTID, winner.Hex(), tx.Hash().Hex())
+	go s.trackBundleDelivery(item, tx.Hash())
+}
+
+// trackBundleDelivery 跟踪成员转移交易回执：成功标记为 delivered，回滚或等待回执超时标记为 failed
+func (s *AuctionService) trackBundleDelivery(item models.AuctionBundleItem, txHash common.Hash) {
+	ctx, cancel := context.WithTimeout(context.Background(), bundleDeliveryTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(bundleDeliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			finishBundleDelivery(item, models.BundleDeliverySubmitted, models.BundleDeliveryFailed,
+				fmt.Sprintf("transaction receipt not found within %s", bundleDeliveryTimeout))
+			logger.Warn("bundle delivery receipt not found before timeout: itemID=%d, txHash=%s", item.ID, txHash.Hex())
+			return
+		case <-ticker.C:
+			receipt, err := s.ethClient.GetTransactionReceipt(ctx, txHash)
+			if err != nil {
+				if err != goethereum.NotFound {
+					logger.Warn("failed to get bundle delivery receipt: txHash=%s, error=%v", txHash.Hex(), err)
+				}
+				continue
+			}
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				finishBundleDelivery(item, models.BundleDeliverySubmitted, models.BundleDeliveryDelivered, "")
+				logger.Info("bundle member delivered: auctionID=%s, nftID=%s, txHash=%s", item.AuctionID, item.NFTID, txHash.Hex())
+				return
+			}
+			finishBundleDelivery(item, models.BundleDeliverySubmitted, models.BundleDeliveryFailed, "transaction reverted")
+			logger.Warn("bundle delivery transaction reverted: itemID=%d, txHash=%s", item.ID, txHash.Hex())
+			return
+		}
+	}
+}
+
+// finishBundleDelivery 将成员转移状态从 from 更新为 delivered 或 failed，并释放成员的在线锁
+func finishBundleDelivery(item models.AuctionBundleItem, from string, to string, message string) {
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AuctionBundleItem{}).
+			Where("id = ? AND delivery_status = ?", item.ID, from).
+			Updates(map[string]interface{}{
+				"delivery_status": to,
+				"delivery_error":  message,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update bundle delivery: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return unlockBundleMember(tx, &item)
+	}); err != nil {
+		logger.Error("failed to finish bundle delivery: itemID=%d, status=%s, error=%v", item.ID, to, err)
+		return
+	}
+	if to == models.BundleDeliveryFailed {
+		logger.Error("bundle member delivery failed, winner has not received the NFT: auctionID=%s, nftID=%s, error=%s",
+			item.AuctionID, item.NFTID, message)
+	}
+}
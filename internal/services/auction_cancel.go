@@ -0,0 +1,557 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// 拍卖取消：
+// draft/pending 拍卖未上链，直接取消并释放 NFT 在线锁；
+// 已上链（upcoming/active）的拍卖由卖家签名合约 cancelUserAuction（已有出价时不允许），
+// 或由管理员通过平台私钥调用合约 cancelAuction（已有出价时合约退款给最高出价者）；
+// 结算校验不通过的拍卖同样由平台私钥调用 cancelAuction（SystemCancel）。
+// 取消交易发送后跟踪回执，拍卖状态以 AuctionCancelled 事件为准（OnEventAuctionCancelled 中确认取消请求并通知出价者退款）
+
+const (
+	// cancelReceiptPollInterval 取消交易回执轮询间隔
+	cancelReceiptPollInterval = 5 * time.Second
+	// cancelReceiptTimeout 取消交易回执最长等待时间
+	cancelReceiptTimeout = 10 * time.Minute
+)
+
+// Cancel 卖家取消拍卖
+// 未上链的拍卖直接取消；已上链的拍卖返回 cancelUserAuction 待签名交易，卖家发送后调用 SubmitCancelTx 提交交易哈希
+func (s *AuctionService) Cancel(userID uint64, auctionID string) (*models.CancelAuctionResponse, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch auction.Status {
+	case AuctionStatusDraft, AuctionStatusPending:
+		if err := s.cancelOffChain(auction, userActor(userID), "cancel"); err != nil {
+			return nil, err
+		}
+		return &models.CancelAuctionResponse{Auction: auction}, nil
+	case AuctionStatusUpcoming, AuctionStatusActive:
+		return s.requestSellerCancel(userID, auction)
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("cannot cancel auction in status %s", auction.Status))
+	}
+}
+
+// AdminCancel 管理员取消拍卖（不受出价限制）
+// 未上链的拍卖直接取消；已上链的拍卖使用平台私钥调用合约 cancelAuction
+func (s *AuctionService) AdminCancel(adminUserID uint64, auctionID string, reason string) (*models.CancelAuctionResponse, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("auction not found")
+		}
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+
+	switch auction.Status {
+	case AuctionStatusDraft, AuctionStatusPending:
+		if err := s.cancelOffChain(&auction, userActor(adminUserID), "admin_cancel"); err != nil {
+			return nil, err
+		}
+		logger.Info("auction cancelled by admin: auctionID=%s, adminUserID=%d, reason=%s", auction.AuctionID, adminUserID, reason)
+		return &models.CancelAuctionResponse{Auction: &auction}, nil
+	case AuctionStatusUpcoming, AuctionStatusActive:
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("cannot cancel auction in status %s", auction.Status))
+	}
+
+	if auction.ContractAuctionID == 0 {
+		return nil, errors.BadRequest("auction is not on chain yet")
+	}
+	open, err := findOpenCancellation(auction.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil && open.Status == models.CancellationStatusSubmitted {
+		return nil, errors.BadRequest(fmt.Sprintf("cancellation transaction already submitted: %s", open.TxHash))
+	}
+
+	cancellation, err := s.platformCancel(&auction, models.CancellationModeAdmin, adminUserID, reason)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CancelAuctionResponse{Auction: &auction, Cancellation: cancellation}, nil
+}
+
+// SystemCancel 结算校验不通过时由平台取消已上链的拍卖（合约退款给最高出价者，NFT 退回卖家）
+// 已有提交中的取消交易时不重复发送
+func (s *AuctionService) SystemCancel(auction *models.Auction, reason string) (*models.AuctionCancellation, error) {
+	open, err := findOpenCancellation(auction.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil && open.Status == models.CancellationStatusSubmitted {
+		logger.Info("cancellation already submitted, skipping system cancel: auctionID=%s, txHash=%s", auction.AuctionID, open.TxHash)
+		return open, nil
+	}
+	return s.platformCancel(auction, models.CancellationModeSystem, 0, reason)
+}
+
+// platformCancel 使用平台私钥调用合约 cancelAuction，记录取消请求并跟踪交易回执
+// 先写入 requested 状态的取消请求再发送交易，发送成功后更新为 submitted 并记录交易哈希；
+// 发送失败时标记为 failed，保证每笔平台取消交易都有对应的取消请求
+func (s *AuctionService) platformCancel(auction *models.Auction, mode string, requestedBy uint64, reason string) (*models.AuctionCancellation, error) {
+	myAuction, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), s.ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auction contract instance: %w", err)
+	}
+	auth, err := s.ethClient.GetAuth(context.Background(), s.config.PlatformPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction auth: %w", err)
+	}
+
+	cancellation := models.AuctionCancellation{
+		AuctionID:         auction.AuctionID,
+		ContractAuctionID: auction.ContractAuctionID,
+		Mode:              mode,
+		RequestedBy:       requestedBy,
+		Reason:            reason,
+		Status:            models.CancellationStatusRequested,
+	}
+	if err := database.DB.Create(&cancellation).Error; err != nil {
+		return nil, fmt.Errorf("failed to save cancellation: %w", err)
+	}
+
+	tx, err := myAuction.CancelAuction(auth, new(big.Int).SetUint64(auction.ContractAuctionID))
+	if err != nil {
+		if updateErr := database.DB.Model(&cancellation).Updates(map[string]interface{}{
+			"status": models.CancellationStatusFailed,
+			"error":  cancellationErrorMessage(err),
+		}).Error; updateErr != nil {
+			logger.Error("failed to mark cancellation failed: cancellationID=%d, error=%v", cancellation.ID, updateErr)
+		}
+		return nil, fmt.Errorf("failed to cancel auction on chain: %w", err)
+	}
+
+	// 交易已发送：即使记录交易哈希失败，AuctionCancelled 事件到达时仍会确认这条 requested 状态的取消请求
+	if err := database.DB.Model(&cancellation).Updates(map[string]interface{}{
+		"status":  models.CancellationStatusSubmitted,
+		"tx_hash": tx.Hash().Hex(),
+	}).Error; err != nil {
+		logger.Error("failed to record cancel transaction: cancellationID=%d, txHash=%s, error=%v", cancellation.ID, tx.Hash().Hex(), err)
+	}
+	cancellation.Status = models.CancellationStatusSubmitted
+	cancellation.TxHash = tx.Hash().Hex()
+	logger.Info("platform cancel transaction sent: auctionID=%s, mode=%s, requestedBy=%d, reason=%s, txHash=%s",
+		auction.AuctionID, mode, requestedBy, reason, tx.Hash().Hex())
+
+	go s.trackCancellation(cancellation.ID, tx.Hash())
+	return &cancellation, nil
+}
+
+// cancellationErrorMessage 取消请求的失败原因（error 字段最长 255 个字符）
+func cancellationErrorMessage(err error) string {
+	msg := err.Error()
+	if len(msg) > 255 {
+		msg = strings.ToValidUTF8(msg[:255], "")
+	}
+	return msg
+}
+
+// ResumeCancellationTracking 服务启动时恢复跟踪已发送但尚未确认的取消交易（submitted 状态）
+func (s *AuctionService) ResumeCancellationTracking() error {
+	var submitted []models.AuctionCancellation
+	if err := database.DB.Where("status = ? AND tx_hash <> ''", models.CancellationStatusSubmitted).
+		Find(&submitted).Error; err != nil {
+		return fmt.Errorf("failed to load submitted cancellations: %w", err)
+	}
+	for _, cancellation := range submitted {
+		go s.trackCancellation(cancellation.ID, common.HexToHash(cancellation.TxHash))
+	}
+	if len(submitted) > 0 {
+		logger.Info("resumed tracking of %d submitted cancellation(s)", len(submitted))
+	}
+	return nil
+}
+
+// SubmitCancelTx 卖家提交已发送的 cancelUserAuction 交易哈希
+// 校验交易的接收方、调用数据和发送方后开始跟踪回执
+func (s *AuctionService) SubmitCancelTx(userID uint64, auctionID string, txHash string) (*models.AuctionCancellation, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	open, err := findOpenCancellation(auction.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if open == nil || open.Mode != models.CancellationModeSeller || open.Status != models.CancellationStatusRequested {
+		return nil, errors.BadRequest("no cancellation waiting for a transaction, please request cancellation first")
+	}
+
+	hashBytes, err := hexutil.Decode(txHash)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return nil, errors.BadRequest("invalid transaction hash")
+	}
+	hash := common.BytesToHash(hashBytes)
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tx, _, err := s.ethClient.GetTransactionByHash(context.Background(), hash)
+	if err != nil {
+		if err == goethereum.NotFound {
+			return nil, errors.BadRequest("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	expected, err := cancelUserAuctionData(auction.ContractAuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() == nil || *tx.To() != common.HexToAddress(s.config.AuctionContractAddress) || !bytes.Equal(tx.Data(), expected) {
+		return nil, errors.BadRequest("transaction is not a cancelUserAuction call for this auction")
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, errors.BadRequest("failed to recover transaction sender")
+	}
+	if !strings.EqualFold(sender.Hex(), auctionSellerWallet(auction, &user)) {
+		return nil, errors.BadRequest("transaction was not sent by your wallet")
+	}
+
+	result := database.DB.Model(open).
+		Where("status = ?", models.CancellationStatusRequested).
+		Updates(map[string]interface{}{
+			"status":  models.CancellationStatusSubmitted,
+			"tx_hash": hash.Hex(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update cancellation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.BadRequest("cancellation is no longer waiting for a transaction")
+	}
+	logger.Info("seller cancel transaction submitted: auctionID=%s, userID=%d, txHash=%s", auction.AuctionID, userID, hash.Hex())
+
+	go s.trackCancellation(open.ID, hash)
+	return getCancellation(open.ID)
+}
+
+// GetCancellation 查询卖家拍卖最近一次的取消请求
+func (s *AuctionService) GetCancellation(userID uint64, auctionID string) (*models.AuctionCancellation, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	var cancellation models.AuctionCancellation
+	if err := database.DB.Where("auction_id = ?", auction.AuctionID).Order("id DESC").First(&cancellation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("cancellation not found")
+		}
+		return nil, fmt.Errorf("failed to get cancellation: %w", err)
+	}
+	return &cancellation, nil
+}
+
+// cancelOffChain 取消尚未上链的拍卖（draft/pending），状态机同时释放 NFT 在线锁
+func (s *AuctionService) cancelOffChain(auction *models.Auction, actor string, reason string) error {
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		changed, err := transitionAuction(tx, auction, StatusTransition{
+			To:     AuctionStatusCancelled,
+			Actor:  actor,
+			Cause:  TransitionCauseAPI,
+			Reason: reason,
+		})
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return errors.BadRequest("auction status has changed, please refresh and try again")
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to cancel auction: %w", err)
+	}
+	return nil
+}
+
+// requestSellerCancel 卖家取消已上链的拍卖：校验取消策略并生成 cancelUserAuction 待签名交易
+func (s *AuctionService) requestSellerCancel(userID uint64, auction *models.Auction) (*models.CancelAuctionResponse, error) {
+	if auction.ContractAuctionID == 0 {
+		return nil, errors.BadRequest("auction is not on chain yet")
+	}
+	hasBids, err := auctionHasBids(auction)
+	if err != nil {
+		return nil, err
+	}
+	if hasBids {
+		return nil, errors.Forbidden("auction already has bids and can only be cancelled by an administrator")
+	}
+
+	open, err := findOpenCancellation(auction.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil && open.Status == models.CancellationStatusSubmitted {
+		return nil, errors.BadRequest(fmt.Sprintf("cancellation transaction already submitted: %s", open.TxHash))
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	data, err := cancelUserAuctionData(auction.ContractAuctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重复请求复用未提交的取消请求
+	if open == nil {
+		open = &models.AuctionCancellation{
+			AuctionID:         auction.AuctionID,
+			ContractAuctionID: auction.ContractAuctionID,
+			Mode:              models.CancellationModeSeller,
+			RequestedBy:       userID,
+			Status:            models.CancellationStatusRequested,
+		}
+		if err := database.DB.Create(open).Error; err != nil {
+			return nil, fmt.Errorf("failed to save cancellation: %w", err)
+		}
+	}
+
+	return &models.CancelAuctionResponse{
+		Auction:      auction,
+		Cancellation: open,
+		Transaction: &models.UnsignedTransaction{
+			From:    auctionSellerWallet(auction, &user),
+			To:      strings.ToLower(s.config.AuctionContractAddress),
+			Data:    hexutil.Encode(data),
+			Value:   "0",
+			ChainID: s.config.ChainID,
+			Method:  "cancelUserAuction",
+		},
+	}, nil
+}
+
+// auctionSellerWallet 拍卖的卖家钱包：上架时持有 NFT 的钱包（可能是已关联钱包），旧数据回退到主钱包
+func auctionSellerWallet(auction *models.Auction, user *models.User) string {
+	if auction.OwnerAddress != "" {
+		return strings.ToLower(auction.OwnerAddress)
+	}
+	return strings.ToLower(user.WalletAddress)
+}
+
+// auctionHasBids 拍卖是否已有出价（密封拍卖包括已提交的出价承诺）
+func auctionHasBids(auction *models.Auction) (bool, error) {
+	if auction.BidCount > 0 || auction.HighestBidder != "" {
+		return true, nil
+	}
+	if auction.IsSealed() {
+		var count int64
+		if err := database.DB.Model(&models.SealedBid{}).Where("auction_id = ?", auction.AuctionID).Count(&count).Error; err != nil {
+			return false, fmt.Errorf("failed to count sealed bids: %w", err)
+		}
+		return count > 0, nil
+	}
+	return false, nil
+}
+
+// cancelUserAuctionData 打包合约 cancelUserAuction 调用数据
+func cancelUserAuctionData(contractAuctionID uint64) ([]byte, error) {
+	auctionABI, err := my_auction.MyXAuctionV2MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auction ABI: %w", err)
+	}
+	data, err := auctionABI.Pack("cancelUserAuction", new(big.Int).SetUint64(contractAuctionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack cancelUserAuction call: %w", err)
+	}
+	return data, nil
+}
+
+// findOpenCancellation 查询拍卖未完成的取消请求（requested 或 submitted）
+func findOpenCancellation(auctionID string) (*models.AuctionCancellation, error) {
+	var cancellation models.AuctionCancellation
+	if err := database.DB.Where("auction_id = ? AND status IN ?", auctionID,
+		[]string{models.CancellationStatusRequested, models.CancellationStatusSubmitted}).
+		Order("id DESC").First(&cancellation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cancellation: %w", err)
+	}
+	return &cancellation, nil
+}
+
+func getCancellation(id uint64) (*models.AuctionCancellation, error) {
+	var cancellation models.AuctionCancellation
+	if err := database.DB.First(&cancellation, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cancellation: %w", err)
+	}
+	return &cancellation, nil
+}
+
+// trackCancellation 跟踪取消交易回执：交易失败时标记取消请求为 failed；成功时等待 AuctionCancelled 事件确认
+func (s *AuctionService) trackCancellation(cancellationID uint64, txHash common.Hash) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelReceiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cancelReceiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 交易可能已被丢弃或因手续费过低长期未打包，标记为 failed 以便重新发起取消；之后交易仍被打包时由 AuctionCancelled 事件确认
+			failCancellation(cancellationID, fmt.Sprintf("transaction receipt not found within %s", cancelReceiptTimeout))
+			logger.Warn("cancel transaction receipt not found before timeout: cancellationID=%d, txHash=%s", cancellationID, txHash.Hex())
+			return
+		case <-ticker.C:
+			receipt, err := s.ethClient.GetTransactionReceipt(ctx, txHash)
+			if err != nil {
+				if err != goethereum.NotFound {
+					logger.Warn("failed to get cancel transaction receipt: txHash=%s, error=%v", txHash.Hex(), err)
+				}
+				continue
+			}
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				logger.Info("cancel transaction mined: cancellationID=%d, txHash=%s, block=%d", cancellationID, txHash.Hex(), receipt.BlockNumber.Uint64())
+				return
+			}
+			failCancellation(cancellationID, "transaction reverted")
+			logger.Warn("cancel transaction reverted: cancellationID=%d, txHash=%s", cancellationID, txHash.Hex())
+			return
+		}
+	}
+}
+
+// failCancellation 将提交中的取消请求标记为 failed（交易回滚或等待回执超时）
+func failCancellation(cancellationID uint64, message string) {
+	if err := database.DB.Model(&models.AuctionCancellation{}).
+		Where("id = ? AND status = ?", cancellationID, models.CancellationStatusSubmitted).
+		Updates(map[string]interface{}{
+			"status": models.CancellationStatusFailed,
+			"error":  message,
+		}).Error; err != nil {
+		logger.Error("failed to mark cancellation failed: cancellationID=%d, error=%v", cancellationID, err)
+	}
+}
+
+// confirmCancellation AuctionCancelled 事件到达时确认取消请求
+// 优先确认交易哈希一致的请求（包括等待回执超时后标记为 failed 的请求），否则确认最近一次未完成的请求（卖家签名后未提交交易哈希）；
+// 其余未完成的请求标记为 failed
+func confirmCancellation(tx *gorm.DB, auctionID string, txHash string) (*models.AuctionCancellation, error) {
+	var open []models.AuctionCancellation
+	if err := tx.Where("auction_id = ? AND (status IN ? OR (status = ? AND tx_hash = ? AND tx_hash <> ''))", auctionID,
+		[]string{models.CancellationStatusRequested, models.CancellationStatusSubmitted},
+		models.CancellationStatusFailed, txHash).
+		Order("id DESC").Find(&open).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cancellations: %w", err)
+	}
+	if len(open) == 0 {
+		return nil, nil
+	}
+
+	confirmed := &open[0]
+	for i := range open {
+		if strings.EqualFold(open[i].TxHash, txHash) {
+			confirmed = &open[i]
+			break
+		}
+	}
+	for i := range open {
+		c := &open[i]
+		updates := map[string]interface{}{
+			"status": models.CancellationStatusFailed,
+			"error":  "auction cancelled by another transaction",
+		}
+		if c == confirmed {
+			updates = map[string]interface{}{
+				"status":  models.CancellationStatusConfirmed,
+				"tx_hash": txHash,
+			}
+		}
+		if err := tx.Model(c).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("failed to update cancellation: %w", err)
+		}
+	}
+	confirmed.Status = models.CancellationStatusConfirmed
+	confirmed.TxHash = txHash
+	return confirmed, nil
+}
+
+// notifyAuctionCancelled 通知最高出价者退款；管理员取消时同时通知卖家
+func (s *AuctionService) notifyAuctionCancelled(auction *models.Auction, cancellation *models.AuctionCancellation,
+	bidder string, paymentToken string, refundAmount uint64) {
+	if s.notifier == nil {
+		return
+	}
+	reason := ""
+	if cancellation != nil {
+		reason = cancellation.Reason
+	}
+
+	if bidder != "" && bidder != strings.ToLower(common.Address{}.Hex()) {
+		if user, err := findUserByWallet(database.DB, bidder); err != nil {
+			logger.Warn("refund notification skipped, bidder user not found: auctionID=%s, bidder=%s", auction.AuctionID, bidder)
+		} else {
+			s.notifier.Notify(context.Background(), []uint64{user.ID}, &models.Notification{
+				Type:      models.NotificationTypeAuctionCancelledRefund,
+				Title:     "拍卖已取消，出价已退款",
+				Content:   "您参与出价的拍卖已被取消，合约已将您的最高出价退回到您的钱包",
+				AuctionID: auction.AuctionID,
+				Data: map[string]interface{}{
+					"paymentToken": paymentToken,
+					"refundAmount": refundAmount,
+					"reason":       reason,
+				},
+				CreatedAt: time.Now(),
+			})
+		}
+	}
+
+	if cancellation != nil && cancellation.Mode == models.CancellationModeSystem {
+		s.notifier.Notify(context.Background(), []uint64{auction.UserID}, &models.Notification{
+			Type:      models.NotificationTypeAuctionCancelled,
+			Title:     "拍卖未能成交，已取消",
+			Content:   "您的拍卖最高出价不满足成交条件，平台已取消拍卖，NFT 已退回到您的钱包",
+			AuctionID: auction.AuctionID,
+			Data: map[string]interface{}{
+				"reason": reason,
+				"txHash": cancellation.TxHash,
+			},
+			CreatedAt: time.Now(),
+		})
+	}
+
+	if cancellation != nil && cancellation.Mode == models.CancellationModeAdmin {
+		s.notifier.Notify(context.Background(), []uint64{auction.UserID}, &models.Notification{
+			Type:      models.NotificationTypeAuctionCancelled,
+			Title:     "拍卖已被管理员取消",
+			Content:   "您的拍卖已被平台管理员取消，NFT 已退回到您的钱包",
+			AuctionID: auction.AuctionID,
+			Data: map[string]interface{}{
+				"reason": reason,
+				"txHash": cancellation.TxHash,
+			},
+			CreatedAt: time.Now(),
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// 拍卖草稿：draft 状态的拍卖不占用 NFT 在线锁（online_lock 为 nft_id:auction_id，不写入 nft_locks），也不校验链上授权，
+// 卖家可以反复修改；提交（Commit）时校验在线锁和链上状态，加锁后变为 pending，等待卖家签名链上 createAuction
+
+// Commit 提交草稿：校验所有权、在线锁和链上授权后加锁，状态改为 pending
+func (s *AuctionService) Commit(userID uint64, auctionID string) (*models.Auction, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status != AuctionStatusDraft {
+		return nil, errors.BadRequest(fmt.Sprintf("cannot commit auction in status %s: only drafts can be committed", auction.Status))
+	}
+	if auction.StartTime == nil || auction.EndTime == nil {
+		return nil, errors.BadRequest("start time and end time are required")
+	}
+	if !auction.EndTime.After(time.Now()) {
+		return nil, errors.BadRequest(
+			fmt.Sprintf("end time must be in the future, current end time: %s",
+				auction.EndTime.Format("2006-01-02 15:04:05")))
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 主 NFT 和打包成员都需要仍由卖家持有、未被其他拍卖锁定、链上归属卖家钱包并已授权
+	var members []models.AuctionBundleItem
+	if auction.BundleSize > 0 {
+		items, err := loadBundleItems(database.DB, auction.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 1 {
+			members = items[1:]
+		}
+	}
+	nftIDs := []string{auction.NFTID}
+	for _, item := range members {
+		nftIDs = append(nftIDs, item.NFTID)
+	}
+	var holding int64
+	if err := database.DB.Model(&models.NFTOwnership{}).
+		Where("nft_id IN ? AND user_id = ? AND status = ?", nftIDs, userID, models.NFTOwnershipStatusHolding).
+		Count(&holding).Error; err != nil {
+		return nil, fmt.Errorf("failed to verify NFT ownership: %w", err)
+	}
+	if holding != int64(len(nftIDs)) {
+		return nil, errors.BadRequest("you no longer hold all NFTs of this draft. Please sync your NFTs first")
+	}
+
+	lockedBy, err := findOnlineLock(database.DB, auction.NFTID)
+	if err != nil {
+		return nil, err
+	}
+	if lockedBy != "" {
+		return nil, errors.BadRequest(fmt.Sprintf("该 NFT 已经在拍卖中，拍卖ID: %s", lockedBy))
+	}
+	sellerWallet, err := sellerWalletAddress(database.DB, &user, auction.NFTID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyNFTOnChain(sellerWallet, auction.NFTID, auction.NFTAddress, auction.TokenID); err != nil {
+		return nil, err
+	}
+	for _, item := range members {
+		if err := s.verifyBundleItem(sellerWallet, item); err != nil {
+			return nil, err
+		}
+	}
+
+	// 状态机进入 pending 时加锁（主 NFT 和打包成员），nft_locks 主键保证并发提交或创建时同一 NFT 只有一个拍卖加锁成功
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		changed, err := transitionAuction(tx, auction, StatusTransition{
+			To:      AuctionStatusPending,
+			Actor:   userActor(userID),
+			Cause:   TransitionCauseAPI,
+			Reason:  "commit",
+			Updates: map[string]interface{}{"online": uint64(time.Now().Unix())},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to commit draft: %w", err)
+		}
+		if !changed {
+			return errors.BadRequest("draft has already been committed or cancelled")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("auction draft committed: auctionID=%s, userID=%d", auction.AuctionID, userID)
+	if err := database.DB.Preload("BundleItems", preloadBundleItems).
+		Where("auction_id = ?", auction.AuctionID).First(auction).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload auction: %w", err)
+	}
+	return auction, nil
+}
+
+// Clone 以卖家的历史拍卖（任意状态）为蓝本创建新拍卖：沿用 NFT（含打包成员）、拍卖类型、价格、
+// 流拍重新上架、可见性和白名单设置；开始时间默认为当前时间，时长默认沿用原拍卖，
+// 荷兰式/密封拍卖的时间参数按新时长等比例换算。默认创建 pending 拍卖，draft 为 true 时保存为草稿
+func (s *AuctionService) Clone(userID uint64, auctionID string, payload models.CloneAuctionPayload) (*models.Auction, error) {
+	source, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if source.StartTime == nil || source.EndTime == nil || source.StartPrice == nil {
+		return nil, errors.BadRequest("source auction is missing time or start price")
+	}
+	if source.AuctionType == models.AuctionTypeFixed {
+		return nil, errors.BadRequest("fixed-price listings cannot be cloned")
+	}
+
+	startTime := time.Now()
+	if payload.StartTime != nil {
+		startTime = *payload.StartTime
+	}
+	endTime := startTime.Add(source.EndTime.Sub(*source.StartTime))
+	if payload.EndTime != nil {
+		endTime = *payload.EndTime
+	}
+	if !endTime.After(startTime) {
+		return nil, errors.BadRequest("end time must be after start time")
+	}
+
+	clone := models.AuctionPayload{
+		NFTID:             source.NFTID,
+		NFTAddress:        source.NFTAddress,
+		TokenID:           source.TokenID,
+		AuctionType:       source.AuctionType,
+		PaymentToken:      source.PaymentToken,
+		StartPrice:        *source.StartPrice,
+		StartTime:         &startTime,
+		EndTime:           &endTime,
+		Draft:             payload.Draft,
+		AuctionTypeParams: scaledTypeParams(source, startTime, endTime.Sub(startTime)),
+		RelistParams: models.RelistParams{
+			AutoRelist:           source.AutoRelist,
+			RelistDuration:       source.RelistDuration,
+			RelistPriceReduction: source.RelistPriceReduction,
+		},
+		VisibilityParams: models.VisibilityParams{
+			Visibility: normalizeVisibility(source.Visibility),
+		},
+	}
+
+	if source.BundleSize > 0 {
+		items, err := loadBundleItems(database.DB, source.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Position == 0 {
+				continue
+			}
+			clone.BundleNFTs = append(clone.BundleNFTs, models.BundleNFTPayload{
+				NFTID:      item.NFTID,
+				NFTAddress: item.NFTAddress,
+				TokenID:    item.TokenID,
+			})
+		}
+	}
+
+	entries, err := loadAllowlistEntries(database.DB, source.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.WalletAddress != "" {
+			clone.AllowlistWallets = append(clone.AllowlistWallets, entry.WalletAddress)
+		} else {
+			clone.AllowlistUserIDs = append(clone.AllowlistUserIDs, entry.UserID)
+		}
+	}
+
+	auction, err := s.Create(userID, clone)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("auction cloned: sourceAuctionID=%s, auctionID=%s, status=%s", source.AuctionID, auction.AuctionID, auction.Status)
+	return auction, nil
+}
+
+// scaledTypeParams 以新的开始时间和时长换算原拍卖的类型参数
+// 荷兰式拍卖沿用底价和降价间隔；保留价和加价幅度沿用；密封拍卖的提交/揭示截止时间按新时长等比例换算
+func scaledTypeParams(source *models.Auction, startTime time.Time, duration time.Duration) models.AuctionTypeParams {
+	params := models.AuctionTypeParams{
+		PriceDecayInterval:  source.PriceDecayInterval,
+		BidIncrementPercent: source.BidIncrementPercent,
+	}
+	if source.ReservePrice != nil {
+		reservePrice := *source.ReservePrice
+		params.ReservePrice = &reservePrice
+	}
+	if source.IsDutch() && source.FloorPrice != nil {
+		floorPrice := *source.FloorPrice
+		params.FloorPrice = &floorPrice
+	}
+	if source.IsSealed() && source.CommitEndTime != nil && source.RevealEndTime != nil {
+		originalDuration := source.EndTime.Sub(*source.StartTime)
+		scale := func(t *time.Time) *time.Time {
+			offset := time.Duration(float64(t.Sub(*source.StartTime)) * float64(duration) / float64(originalDuration))
+			scaled := startTime.Add(offset)
+			return &scaled
+		}
+		params.CommitEndTime = scale(source.CommitEndTime)
+		params.RevealEndTime = scale(source.RevealEndTime)
+	}
+	return params
+}
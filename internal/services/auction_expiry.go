@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// 待上架拍卖过期：pending 拍卖创建时即占用 NFT 在线锁（nft_id:1），卖家迟迟不签名链上 createAuction 时 NFT 无法再次上架。
+// 超过 auction.pending_ttl（以 online 字段记录的进入 pending 的时间戳计算）或卖家主动放弃时，
+// 拍卖标记为 expired 并释放在线锁（改为 nft_id:auction_id）。
+// 过期与 AuctionCreated 事件处理都通过 status = pending 的条件更新/行锁互斥，只有一方生效；
+// 事件晚于过期到达时恢复该 NFT 最近过期的拍卖（见 reviveExpiredAuction）
+
+// Abandon 卖家放弃尚未上链的待上架拍卖：标记为 expired 并释放 NFT 在线锁
+func (s *AuctionService) Abandon(userID uint64, auctionID string) (*models.Auction, error) {
+	auction, err := s.getSellerAuction(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status != AuctionStatusPending {
+		return nil, errors.BadRequest(fmt.Sprintf("cannot abandon auction in status %s: only pending auctions can be abandoned", auction.Status))
+	}
+
+	expired, err := s.ExpirePending(auction, userActor(userID), TransitionCauseAPI, "abandon")
+	if err != nil {
+		return nil, err
+	}
+	if !expired {
+		return nil, errors.BadRequest("auction has already been created on chain or is no longer pending")
+	}
+	logger.Info("pending auction abandoned: auctionID=%s, userID=%d", auction.AuctionID, userID)
+	return auction, nil
+}
+
+// ExpirePending 将 pending 拍卖标记为 expired 并释放主 NFT 和打包成员的在线锁
+// 返回 false 表示拍卖已不是 pending（已上链或已被取消/过期），未做任何修改
+func (s *AuctionService) ExpirePending(auction *models.Auction, actor string, cause string, reason string) (bool, error) {
+	if auction.Status != AuctionStatusPending {
+		return false, nil
+	}
+	expired := false
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 状态机以 status = pending 为条件更新：AuctionCreated 事件处理持有行锁时在此等待，提交后更新不生效
+		changed, err := transitionAuction(tx, auction, StatusTransition{
+			To:      AuctionStatusExpired,
+			Actor:   actor,
+			Cause:   cause,
+			Reason:  reason,
+			Updates: map[string]interface{}{"online": 0},
+		})
+		expired = changed
+		return err
+	}); err != nil {
+		return false, fmt.Errorf("failed to expire auction: %w", err)
+	}
+
+	if expired {
+		auction.Online = 0
+	}
+	return expired, nil
+}
+
+// reviveExpiredAuction 迟到的 AuctionCreated 事件：恢复该 NFT 最近过期的拍卖为 pending 并重新加锁
+// NFT 已被新的拍卖锁定时返回错误（链上拍卖需要卖家或管理员取消）
+func reviveExpiredAuction(tx *gorm.DB, nftID string, actor string, txHash string) (*models.Auction, error) {
+	var auction models.Auction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("nft_id = ? AND status = ?", nftID, AuctionStatusExpired).
+		Order("updated_at DESC, id DESC").
+		First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("no pending or expired auction found for NFT %s: %w", nftID, err)
+		}
+		return nil, fmt.Errorf("failed to get expired auction: %w", err)
+	}
+
+	// 状态机进入 pending 时重新加锁（主 NFT 和打包成员）
+	if _, err := transitionAuction(tx, &auction, StatusTransition{
+		To:     AuctionStatusPending,
+		Actor:  actor,
+		Cause:  TransitionCauseEvent,
+		Reason: "revive_expired",
+		TxHash: txHash,
+	}); err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return nil, fmt.Errorf("cannot revive expired auction %s: NFT %s has been locked by another auction", auction.AuctionID, nftID)
+		}
+		return nil, fmt.Errorf("failed to revive expired auction: %w", err)
+	}
+	logger.Warn("expired auction revived by late AuctionCreated event: auctionID=%s, nftID=%s", auction.AuctionID, nftID)
+	return &auction, nil
+}
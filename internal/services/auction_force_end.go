@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// OnEventAuctionForceEnded 处理合约 AuctionForceEnded 事件（管理员强制结束或即时成交结算）
+// 结束任务已结算的拍卖状态已是 ended，视为重复事件；否则按链上最高出价者更新 NFT 持有状态并结束拍卖
+func (s *AuctionService) OnEventAuctionForceEnded(auctionContractId uint64, endedBy string, txHash string) (string, error) {
+	myAuction, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), s.ethClient.GetClient())
+	if err != nil {
+		return "", fmt.Errorf("failed to create auction contract instance: %w", err)
+	}
+	auctionOnChain, err := myAuction.GetAuction(&bind.CallOpts{}, new(big.Int).SetUint64(auctionContractId))
+	if err != nil {
+		return "", fmt.Errorf("failed to get auction on chain: %w", err)
+	}
+
+	var auctionID string
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var auction models.Auction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("contract_auction_id = ?", auctionContractId).First(&auction).Error; err != nil {
+			return fmt.Errorf("failed to get auction: %w", err)
+		}
+		auctionID = auction.AuctionID
+		if auction.Status != AuctionStatusUpcoming && auction.Status != AuctionStatusActive {
+			logger.Info("auction already settled, skipping force end event: auctionID=%s, status=%s", auction.AuctionID, auction.Status)
+			return nil
+		}
+
+		nftIDs, err := auctionNFTIDs(tx, &auction)
+		if err != nil {
+			return err
+		}
+		seller := strings.ToLower(auctionOnChain.Seller.Hex())
+		sold := auctionOnChain.HighestBidder != (common.Address{})
+		if sold {
+			// 打包拍卖的全部成员一起标记为已出售：合约只转移主 NFT，其余成员保持锁定直到平台转给获胜者（AuctionEnded 事件触发）
+			winner := strings.ToLower(auctionOnChain.HighestBidder.Hex())
+			if err := tx.Model(&models.NFTOwnership{}).
+				Where("nft_id IN ? AND user_id = ?", nftIDs, auction.UserID).
+				Updates(map[string]interface{}{
+					"status":        models.NFTOwnershipStatusSold,
+					"owner_address": winner,
+					"approved":      0,
+					"updated_at":    time.Now(),
+				}).Error; err != nil {
+				return fmt.Errorf("failed to update NFT ownership status: %w", err)
+			}
+			winningBid, err := findWinningBid(tx, auction.AuctionID, winner)
+			if err != nil {
+				return err
+			}
+			if err := markWinningBid(tx, winningBid); err != nil {
+				return err
+			}
+		} else if err := tx.Model(&models.NFTOwnership{}).
+			Where("nft_id IN ? AND user_id = ?", nftIDs, auction.UserID).
+			Updates(map[string]interface{}{
+				"status":        models.NFTOwnershipStatusHolding,
+				"owner_address": seller,
+				"approved":      0,
+				"updated_at":    time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update NFT ownership status: %w", err)
+		}
+
+		if _, err := transitionAuction(tx, &auction, StatusTransition{
+			To:     AuctionStatusEnded,
+			Actor:  endedBy,
+			Cause:  TransitionCauseEvent,
+			Reason: "force_end",
+			TxHash: txHash,
+
+			KeepBundleMemberLocks: sold,
+		}); err != nil {
+			return err
+		}
+		logger.Info("auction force ended: auctionID=%s, endedBy=%s, txHash=%s", auction.AuctionID, endedBy, txHash)
+		return nil
+	})
+	return auctionID, err
+}
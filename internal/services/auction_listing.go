@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// 固定价格出售复用拍卖记录（auction_type = fixed）：
+// 创建时与拍卖共用 NFT 所有权/授权校验和 NFT 在线锁（nft_locks）防重复上架；
+// 链上以标价作为起拍价创建拍卖，首个达到标价的出价由 OnInstantSaleBidPlaced 提前结算，
+// 结算和 NFT 所有权变更与拍卖结束完全一致（processAuctionEnd）。
+
+// CreateListing 创建固定价格出售（pending 状态，等待卖家签名链上 createAuction）
+func (s *AuctionService) CreateListing(userID uint64, payload models.ListingPayload) (*models.Auction, error) {
+	startTime := payload.StartTime
+	if startTime == nil {
+		now := time.Now()
+		startTime = &now
+	}
+	return s.Create(userID, models.AuctionPayload{
+		NFTID:            payload.NFTID,
+		NFTAddress:       payload.NFTAddress,
+		TokenID:          payload.TokenID,
+		AuctionType:      models.AuctionTypeFixed,
+		PaymentToken:     payload.PaymentToken,
+		StartPrice:       payload.Price,
+		StartTime:        startTime,
+		EndTime:          payload.EndTime,
+		VisibilityParams: payload.VisibilityParams,
+	})
+}
+
+// getUserListing 查询属于当前用户的固定价格出售
+func (s *AuctionService) getUserListing(userID uint64, auctionID string) (*models.Auction, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Forbidden("listing not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+	if !auction.IsFixedPrice() {
+		return nil, errors.BadRequest("auction is not a fixed-price listing")
+	}
+	return &auction, nil
+}
+
+// UpdateListingPrice 修改标价
+// 只有 pending 状态可以修改：上链后合约的起拍价无法修改，需要下架后重新出售
+func (s *AuctionService) UpdateListingPrice(userID uint64, auctionID string, price decimal.Decimal) (*models.Auction, error) {
+	auction, err := s.getUserListing(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status != AuctionStatusPending {
+		return nil, errors.BadRequest("only pending listings can change price, delist and list again instead")
+	}
+	if !price.IsPositive() {
+		return nil, errors.BadRequest("price must be greater than 0")
+	}
+
+	priceFloat, _ := price.Float64()
+	usdResponse, err := ConvertTokenAmountToUSD(&s.config, auction.PaymentToken, priceFloat, s.ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert price to USD: %w", err)
+	}
+
+	if err := database.DB.Model(auction).Updates(map[string]interface{}{
+		"start_price":          price,
+		"start_price_usd":      decimal.NewFromFloat(usdResponse.AmountUSD),
+		"start_price_unit_usd": usdResponse.AmountUnitUSD,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update listing price: %w", err)
+	}
+
+	if err := database.DB.Where("auction_id = ?", auctionID).First(auction).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload listing: %w", err)
+	}
+	return auction, nil
+}
+
+// Delist 下架固定价格出售
+// pending：直接取消并释放 online_lock；已上链：由平台调用合约 cancelAuction，状态由 AuctionCancelled 事件更新
+func (s *AuctionService) Delist(userID uint64, auctionID string) (*models.Auction, error) {
+	auction, err := s.getUserListing(userID, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch auction.Status {
+	case AuctionStatusPending:
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if _, err := transitionAuction(tx, auction, StatusTransition{
+				To:     AuctionStatusCancelled,
+				Actor:  userActor(userID),
+				Cause:  TransitionCauseAPI,
+				Reason: "delist",
+			}); err != nil {
+				return fmt.Errorf("failed to delist: %w", err)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return auction, nil
+	case AuctionStatusActive, AuctionStatusUpcoming:
+		if auction.HighestBidder != "" {
+			return nil, errors.BadRequest("listing has already been purchased")
+		}
+		if auction.ContractAuctionID == 0 {
+			return nil, errors.BadRequest("listing is not on chain yet")
+		}
+		myAuction, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), s.ethClient.GetClient())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create auction contract instance: %w", err)
+		}
+		auth, err := s.ethClient.GetAuth(context.Background(), s.config.PlatformPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction auth: %w", err)
+		}
+		tx, err := myAuction.CancelAuction(auth, big.NewInt(int64(auction.ContractAuctionID)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel listing on chain: %w", err)
+		}
+		logger.Info("delist transaction sent: auctionID=%s, txHash=%s", auction.AuctionID, tx.Hash().Hex())
+		return auction, nil
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf("cannot delist listing in status %s", auction.Status))
+	}
+}
+
+// saleOutcome 已结束拍卖的出售结果：有最高出价者时拍卖为 sold、固定价格出售为 purchased，否则为 unsold
+func saleOutcome(auction *models.Auction) string {
+	if auction.Status != AuctionStatusEnded {
+		return ""
+	}
+	if auction.HighestBidder == "" {
+		return models.SaleOutcomeUnsold
+	}
+	if auction.IsFixedPrice() {
+		return models.SaleOutcomePurchased
+	}
+	return models.SaleOutcomeSold
+}
+
+// fillSaleOutcomes 为查询结果计算出售结果
+func fillSaleOutcomes(auctions []models.Auction) {
+	for i := range auctions {
+		auctions[i].Outcome = saleOutcome(&auctions[i])
+	}
+}
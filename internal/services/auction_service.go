@@ -14,6 +14,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/sony/sonyflake"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"my-auction-market-api/internal/config"
 	erc721_nft "my-auction-market-api/internal/contracts/erc721_nft"
@@ -34,10 +35,13 @@ var (
 
 // 拍卖状态常量
 const (
+	AuctionStatusDraft     = "draft"     // 草稿（不占用 NFT 在线锁，提交后变为 pending）
 	AuctionStatusPending   = "pending"   // 待上架
-	AuctionStatusActive    = "active"    // 已上架/进行中
+	AuctionStatusUpcoming  = "upcoming"  // 已上链，等待开始时间（由 auction-start 任务切换为 active）
+	AuctionStatusActive    = "active"    // 已上架/进行中（对外筛选条件为 live）
 	AuctionStatusEnded     = "ended"     // 已结束
 	AuctionStatusCancelled = "cancelled" // 已取消
+	AuctionStatusExpired   = "expired"   // 待上架超时或卖家放弃，已释放 NFT 在线锁（迟到的 AuctionCreated 事件仍可恢复）
 )
 
 func init() {
@@ -54,44 +58,66 @@ type AuctionService struct {
 	ethClient     *ethereum.Client
 	config        config.EthereumConfig
 	taskScheduler *AuctionTaskScheduler
+	notifier      *NotificationService
 }
 
 func (s *AuctionService) OnEventAuctionCancelled(auctionContractId uint64,
-	cancelledBy string, bidder string, paymentToken string, refundAmount uint64, refundAmountValue uint64) (string, error) {
+	cancelledBy string, bidder string, paymentToken string, refundAmount uint64, refundAmountValue uint64, txHash string) (string, error) {
 	//修改nft_ownerships
 	var auctionId string
+	var auction models.Auction
+	var cancellation *models.AuctionCancellation
+	duplicate := false
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
-		var auction models.Auction
 		if err := tx.Where("contract_auction_id = ?", auctionContractId).First(&auction).Error; err != nil {
 			logger.Error("failed to get auction: %v", err)
 			return err
 		}
+		if auction.Status == AuctionStatusCancelled { // 重复事件
+			auctionId = auction.AuctionID
+			duplicate = true
+			return nil
+		}
 		var nftOwnership models.NFTOwnership
 		if err := tx.Where("nft_id = ? AND user_id = ?", auction.NFTID, auction.UserID).First(&nftOwnership).Error; err != nil {
 			logger.Error("failed to get nft_ownership: %v", err)
 			return err
 		}
 
-		if err := tx.Model(&models.NFTOwnership{}).Where("nft_id = ? AND user_id = ?", auction.NFTID, auction.UserID).
+		nftIDs, err := auctionNFTIDs(tx, &auction)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.NFTOwnership{}).Where("nft_id IN ? AND user_id = ?", nftIDs, auction.UserID).
 			Updates(map[string]interface{}{"status": models.NFTOwnershipStatusHolding,
 				"owner_address": auction.OwnerAddress,
 				"approved":      0}).Error; err != nil {
 			logger.Error("failed to update nft_ownership: %v", err)
 			return err
 		}
-		nftOnlineLock := fmt.Sprintf("%s:%s", auction.NFTID, auction.AuctionID)
-		if err := tx.Model(&models.Auction{}).Where("contract_auction_id = ?", auctionContractId).
-			Updates(map[string]interface{}{"status": AuctionStatusCancelled,
-				"updated_at":  time.Now(),
-				"online":      0,
-				"online_lock": nftOnlineLock}).Error; err != nil {
+		if _, err := transitionAuction(tx, &auction, StatusTransition{
+			To:      AuctionStatusCancelled,
+			Actor:   cancelledBy,
+			Cause:   TransitionCauseEvent,
+			Reason:  "auction_cancelled",
+			TxHash:  txHash,
+			Updates: map[string]interface{}{"online": 0},
+		}); err != nil {
 			logger.Error("failed to update auction: %v", err)
 			return err
 		}
-		//
+		// 确认卖家或管理员发起的取消请求
+		confirmed, err := confirmCancellation(tx, auction.AuctionID, txHash)
+		if err != nil {
+			return err
+		}
+		cancellation = confirmed
 		auctionId = auction.AuctionID
 		return nil
 	})
+	if err == nil && !duplicate {
+		s.notifyAuctionCancelled(&auction, cancellation, bidder, paymentToken, refundAmount)
+	}
 	return auctionId, err
 }
 
@@ -123,6 +149,11 @@ func (s *AuctionService) SetTaskScheduler(scheduler *AuctionTaskScheduler) {
 	s.taskScheduler = scheduler
 }
 
+// SetNotificationService 设置通知服务（拍卖取消时通知出价者退款和卖家）
+func (s *AuctionService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
 // ConvertTokenAmountToUSD 将代币金额转换为美元价值（服务方法，封装配置和客户端）
 func (s *AuctionService) ConvertTokenAmountToUSD(tokenAddress string, amount float64) (*models.ConvertToUSDResponse, error) {
 	return ConvertTokenAmountToUSD(&s.config, tokenAddress, amount, s.ethClient.GetClient())
@@ -210,8 +241,9 @@ func (s *AuctionService) List(query page.PageQuery) ([]models.Auction, int64, er
 	var auctions []models.Auction
 	var total int64
 
-	// 查询条件：online == 1
-	baseQuery := database.DB.Model(&models.Auction{}).Where("online = ?", 1)
+	// 查询条件：online == 1，且只包含公开拍卖
+	baseQuery := database.DB.Model(&models.Auction{}).
+		Where("online = ? AND visibility = ?", 1, models.AuctionVisibilityPublic)
 
 	// 统计总数
 	if err := baseQuery.Count(&total).Error; err != nil {
@@ -221,6 +253,7 @@ func (s *AuctionService) List(query page.PageQuery) ([]models.Auction, int64, er
 	// 查询列表
 	if err := baseQuery.
 		Preload("User").
+		Preload("BundleItems", preloadBundleItems).
 		Order("created_at DESC").
 		Offset(query.Offset()).
 		Limit(query.Limit()).
@@ -228,25 +261,35 @@ func (s *AuctionService) List(query page.PageQuery) ([]models.Auction, int64, er
 		return nil, 0, err
 	}
 
+	fillSaleOutcomes(auctions)
 	return auctions, total, nil
 }
 
 // ListPublic 获取公开拍卖列表（用于首页展示）
-// 排序规则：active 状态的排在前面，ended 状态的排在后面，每个状态内部按时间倒序
-// statusFilter: 可选的状态筛选，如果为空或 "all"，则返回所有 active 和 ended 状态的数据
-func (s *AuctionService) ListPublic(query page.PageQuery, statusFilter string) ([]models.Auction, int64, error) {
+// 排序规则：active(live) 状态的排在前面，其次 upcoming，ended 状态的排在后面，每个状态内部按时间倒序
+// statusFilter: 可选的状态筛选（live/active、upcoming、ended），如果为空或 "all"，则返回所有 live、upcoming 和 ended 状态的数据
+// saleTypeFilter: 可选的出售方式筛选（auction、listing），如果为空或 "all"，则同时返回拍卖和固定价格出售
+func (s *AuctionService) ListPublic(query page.PageQuery, statusFilter string, saleTypeFilter string) ([]models.Auction, int64, error) {
 	var auctions []models.Auction
 	var total int64
 
-	// 查询条件：online == 1，且只包含 active 和 ended 状态
+	// 查询条件：online == 1，只包含公开拍卖，且只包含 active、upcoming 和 ended 状态
 	baseQuery := database.DB.Model(&models.Auction{}).
-		Where("online = ? AND status IN ?", 1, []string{AuctionStatusActive, AuctionStatusEnded})
+		Where("online = ? AND visibility = ? AND status IN ?", 1, models.AuctionVisibilityPublic,
+			[]string{AuctionStatusActive, AuctionStatusUpcoming, AuctionStatusEnded})
 
-	// 如果指定了状态筛选，进一步过滤
-	if statusFilter != "" && statusFilter != "all" {
-		if statusFilter == AuctionStatusActive || statusFilter == AuctionStatusEnded {
-			baseQuery = baseQuery.Where("status = ?", statusFilter)
-		}
+	// 如果指定了状态筛选，进一步过滤（live 是 active 的对外名称）
+	switch statusFilter {
+	case "live", AuctionStatusActive:
+		baseQuery = baseQuery.Where("status = ?", AuctionStatusActive)
+	case AuctionStatusUpcoming, AuctionStatusEnded:
+		baseQuery = baseQuery.Where("status = ?", statusFilter)
+	}
+	switch saleTypeFilter {
+	case models.SaleTypeListing:
+		baseQuery = baseQuery.Where("auction_type = ?", models.AuctionTypeFixed)
+	case models.SaleTypeAuction:
+		baseQuery = baseQuery.Where("auction_type <> ?", models.AuctionTypeFixed)
 	}
 
 	// 统计总数
@@ -255,16 +298,18 @@ func (s *AuctionService) ListPublic(query page.PageQuery, statusFilter string) (
 	}
 
 	// 查询列表
-	// 排序：使用 CASE WHEN 确保 active 排在前面，ended 排在后面，然后按时间倒序
+	// 排序：使用 CASE WHEN 确保 active 排在前面，upcoming 其次，ended 排在后面，然后按时间倒序
 	if err := baseQuery.
 		Preload("User").
-		Order("CASE WHEN status = 'active' THEN 0 WHEN status = 'ended' THEN 1 ELSE 2 END, created_at DESC").
+		Preload("BundleItems", preloadBundleItems).
+		Order("CASE WHEN status = 'active' THEN 0 WHEN status = 'upcoming' THEN 1 WHEN status = 'ended' THEN 2 ELSE 3 END, created_at DESC").
 		Offset(query.Offset()).
 		Limit(query.Limit()).
 		Find(&auctions).Error; err != nil {
 		return nil, 0, err
 	}
 
+	fillSaleOutcomes(auctions)
 	return auctions, total, nil
 }
 
@@ -278,8 +323,9 @@ func (s *AuctionService) ListNFTs(query page.PageQuery) ([]models.AuctionNFTItem
 	var countResult struct {
 		Count int64
 	}
-	countSQL := `SELECT COUNT(DISTINCT CONCAT(nft_address, '-', token_id)) as count FROM auctions`
-	if err := database.DB.Raw(countSQL).Scan(&countResult).Error; err != nil {
+	// 非公开拍卖（unlisted、allowlist）不计入
+	countSQL := `SELECT COUNT(DISTINCT CONCAT(nft_address, '-', token_id)) as count FROM auctions WHERE visibility = ?`
+	if err := database.DB.Raw(countSQL, models.AuctionVisibilityPublic).Scan(&countResult).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count NFTs: %w", err)
 	}
 	total = countResult.Count
@@ -291,23 +337,28 @@ func (s *AuctionService) ListNFTs(query page.PageQuery) ([]models.AuctionNFTItem
 			nft_address as nft_address,
 			token_id as token_id,
 			MAX(nft_id) as nft_id,
-			MAX(name) as name,
+			MAX(nft_name) as name,
 			MAX(image) as image,
 			MAX(contract_name) as contract_name,
 			MAX(contract_symbol) as contract_symbol,
 			MAX(token_uri) as token_uri,
 			MAX(description) as description,
-			COUNT(*) as auction_count
+			COUNT(*) as auction_count,
+			SUBSTRING_INDEX(GROUP_CONCAT(auction_type ORDER BY created_at DESC), ',', 1) as auction_type
 		FROM auctions
+		WHERE visibility = ?
 		GROUP BY nft_address, token_id
 		ORDER BY MAX(created_at) DESC
 		LIMIT ? OFFSET ?
 	`
 
-	if err := database.DB.Raw(querySQL, query.Limit(), query.Offset()).
+	if err := database.DB.Raw(querySQL, models.AuctionVisibilityPublic, query.Limit(), query.Offset()).
 		Scan(&nfts).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to list NFTs: %w", err)
 	}
+	for i := range nfts {
+		nfts[i].SaleType = (&models.Auction{AuctionType: nfts[i].AuctionType}).SaleType()
+	}
 
 	return nfts, total, nil
 }
@@ -602,12 +653,15 @@ func ConvertUnitUSDToToken(ethConfig *config.EthereumConfig, tokenAddress string
 // GetByID 根据AuctionID（字符串）获取单个拍卖记录（基本信息）
 func (s *AuctionService) GetByID(auctionID string) (*models.Auction, error) {
 	var auction models.Auction
-	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+	if err := database.DB.Where("auction_id = ?", auctionID).
+		Preload("BundleItems", preloadBundleItems).
+		First(&auction).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.ErrNotFound.WithMessage("auction not found")
 		}
 		return nil, fmt.Errorf("failed to get auction: %w", err)
 	}
+	auction.Outcome = saleOutcome(&auction)
 	return &auction, nil
 }
 
@@ -619,7 +673,8 @@ func (s *AuctionService) GetDetailByID(id uint64) (*models.AuctionDetailResponse
 	}
 
 	if err := database.DB.Table("auctions").
-		Select("auctions.*, users.wallet_address as seller_wallet_address").
+		// 卖家钱包优先取上架时持有 NFT 的钱包（可能是已关联钱包）
+		Select("auctions.*, COALESCE(NULLIF(auctions.owner_address, ''), users.wallet_address) as seller_wallet_address").
 		Joins("LEFT JOIN users ON auctions.user_id = users.id").
 		Where("auctions.id = ?", id).
 		First(&result).Error; err != nil {
@@ -629,10 +684,36 @@ func (s *AuctionService) GetDetailByID(id uint64) (*models.AuctionDetailResponse
 		return nil, fmt.Errorf("failed to get auction detail: %w", err)
 	}
 
-	return &models.AuctionDetailResponse{
+	detail := &models.AuctionDetailResponse{
 		Auction:             result.Auction,
 		SellerWalletAddress: result.SellerWalletAddress,
-	}, nil
+	}
+	detail.Outcome = saleOutcome(&detail.Auction)
+
+	// 打包拍卖：返回所有成员 NFT 的元数据
+	if detail.BundleSize > 0 {
+		items, err := loadBundleItems(database.DB, detail.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		detail.BundleItems = items
+	}
+
+	// 荷兰式拍卖：计算当前时刻的价格
+	if detail.IsDutch() {
+		now := time.Now()
+		currentPrice := detail.Auction.CurrentPrice(now)
+		currentPriceUSD := detail.Auction.CurrentPriceUSD(now)
+		detail.CurrentPrice = &currentPrice
+		detail.CurrentPriceUSD = &currentPriceUSD
+		detail.NextPriceDropAt = detail.Auction.NextPriceDropAt(now)
+	}
+	// 密封拍卖：返回当前阶段
+	if detail.IsSealed() {
+		detail.SealedPhase = detail.Auction.SealedPhase(time.Now())
+	}
+
+	return detail, nil
 }
 
 func (s *AuctionService) GetByContractID(contractAuctionID uint64) (*models.Auction, error) {
@@ -656,7 +737,39 @@ func (s *AuctionService) GetByContractID(contractAuctionID uint64) (*models.Auct
 // 5. 计算起拍价USD价值
 // 6. 生成拍卖ID和OnlineLock
 // 7. 构建并保存拍卖记录
+// 传入 templateId 时先用模板填充未传入的字段；草稿（draft）跳过步骤2和步骤4，提交草稿时再校验
 func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*models.Auction, error) {
+	prepared, err := s.prepareAuction(userID, payload, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保存到数据库（打包拍卖的成员 NFT 与拍卖记录在同一事务中写入，任一成员被锁定则整体失败）
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return savePreparedAuction(tx, prepared)
+	}); err != nil {
+		return nil, err
+	}
+	return &prepared.auction, nil
+}
+
+// preparedAuction 已通过校验、待写入数据库的拍卖记录及其打包成员和白名单
+type preparedAuction struct {
+	auction          models.Auction
+	bundleItems      []models.AuctionBundleItem
+	allowlistEntries []models.AuctionAllowlistEntry
+}
+
+// prepareAuction 执行创建拍卖的校验并构建拍卖记录（不写数据库）
+// verifyOnChain 为 false 表示调用方已批量完成主 NFT 的链上校验；
+// pricer 不为 nil 时使用调用方缓存的代币报价换算起拍价USD（批量创建），否则直接调用合约换算
+func (s *AuctionService) prepareAuction(userID uint64, payload models.AuctionPayload, verifyOnChain bool, pricer *tokenUSDPricer) (*preparedAuction, error) {
+	if payload.TemplateID != 0 {
+		if err := s.applyTemplate(userID, &payload); err != nil {
+			return nil, err
+		}
+	}
+
 	// ========== 步骤1: 验证NFT所有权 ==========
 	// 通过 nft_ownerships 表验证用户是否拥有该NFT（状态为 holding）
 	var ownership models.NFTOwnership
@@ -679,12 +792,14 @@ func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*
 	// ========== 步骤4: 检查NFT是否已有在线拍卖 ==========
 	// OnlineLock 格式: nft_id:1，用于锁定NFT的唯一性，防止同一NFT同时存在多个在线拍卖
 	onlineLock := fmt.Sprintf("%s:1", payload.NFTID)
-	var existingAuction models.Auction
-	if err := database.DB.Where("online_lock = ?", onlineLock).First(&existingAuction).Error; err == nil {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("该 NFT 已经在拍卖中，拍卖ID: %s", existingAuction.AuctionID))
-	} else if err != gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("failed to check existing auction: %w", err)
+	if !payload.Draft {
+		// nft_locks 同时记录单个拍卖和打包拍卖成员的锁，写入时由主键保证并发创建只有一个成功
+		if lockedBy, err := findOnlineLock(database.DB, payload.NFTID); err != nil {
+			return nil, err
+		} else if lockedBy != "" {
+			return nil, errors.BadRequest(
+				fmt.Sprintf("该 NFT 已经在拍卖中，拍卖ID: %s", lockedBy))
+		}
 	}
 	// ========== 步骤2: 链上验证NFT所有权和授权状态 ==========
 	// 获取当前用户的钱包地址
@@ -696,80 +811,93 @@ func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// 创建NFT合约实例
-	platformContractAddress := common.HexToAddress(s.config.AuctionContractAddress)
-	nftContractAddress := common.HexToAddress(payload.NFTAddress)
-
-	_ethClient := s.ethClient.GetClient()
-	nftContract, err := erc721_nft.NewMyNFT(nftContractAddress, _ethClient)
+	// NFT 可能由用户的已关联钱包持有
+	sellerWallet, err := sellerWalletAddress(database.DB, &user, payload.NFTID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create NFT contract instance: %w", err)
+		return nil, err
 	}
 
-	opts := &bind.CallOpts{Context: context.Background()}
-	tokenIDBigInt := big.NewInt(int64(payload.TokenID))
-
-	// 检查链上NFT的实际owner是否是当前用户
-	chainOwner, err := nftContract.OwnerOf(opts, tokenIDBigInt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get NFT owner for token %d: %w", payload.TokenID, err)
+	_ethClient := s.ethClient.GetClient()
+	if !payload.Draft && verifyOnChain {
+		if err := s.verifyNFTOnChain(sellerWallet, payload.NFTID, payload.NFTAddress, payload.TokenID); err != nil {
+			return nil, err
+		}
 	}
 
-	// 规范化地址（转为小写）进行比较
-	normalizedChainOwner := strings.ToLower(chainOwner.Hex())
-	// 验证链上owner是否是当前用户
-	if normalizedChainOwner != user.WalletAddress {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("NFT token %d is not owned by your wallet address. Chain owner: %s, Your wallet: %s. NFT ID: %s",
-				payload.TokenID, chainOwner.Hex(), user.WalletAddress, payload.NFTID))
+	// 打包拍卖：逐个验证成员 NFT 的所有权、在线锁和授权状态（草稿只验证所有权）
+	var bundleItems []models.AuctionBundleItem
+	if len(payload.BundleNFTs) > 0 {
+		items, err := s.prepareBundleItems(userID, sellerWallet, nft, payload.BundleNFTs, payload.Draft)
+		if err != nil {
+			return nil, err
+		}
+		bundleItems = items
 	}
 
-	// 检查指定TokenID的NFT是否已授权给平台合约
-	approvedAddress, err := nftContract.GetApproved(opts, tokenIDBigInt)
+	// 邀请制拍卖的白名单
+	allowlistEntries, err := buildAllowlistEntries(payload.VisibilityParams)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check GetApproved for token %d: %w", payload.TokenID, err)
-	}
-
-	if approvedAddress != platformContractAddress {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("NFT token %d has not been approved for the platform contract. Please approve the NFT first. NFT ID: %s, Contract: %s",
-				payload.TokenID, payload.NFTID, payload.NFTAddress))
+		return nil, err
 	}
 
 	// ========== 步骤3: 验证时间参数 ==========
-	if payload.StartTime == nil || payload.EndTime == nil {
-		return nil, errors.BadRequest("start time and end time are required")
+	if err := validateAuctionTimes(payload.StartTime, payload.EndTime); err != nil {
+		return nil, err
 	}
 
-	// 验证结束时间必须大于开始时间
-	if payload.EndTime.Before(*payload.StartTime) || payload.EndTime.Equal(*payload.StartTime) {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("end time must be after start time, start time: %s, end time: %s",
-				payload.StartTime.Format("2006-01-02 15:04:05"),
-				payload.EndTime.Format("2006-01-02 15:04:05")))
+	// 验证拍卖类型相关参数（荷兰式拍卖需要底价和降价间隔，密封拍卖需要提交/揭示截止时间）
+	auctionType := payload.AuctionType
+	if auctionType == "" {
+		auctionType = models.AuctionTypeEnglish
 	}
-
-	// 验证拍卖持续时间至少1分钟
-	minDuration := 1 * time.Minute
-	if payload.EndTime.Sub(*payload.StartTime) < minDuration {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("auction duration must be at least 1 minute, current duration: %v",
-				payload.EndTime.Sub(*payload.StartTime)))
+	if err := validateAuctionTypeParams(auctionType, payload.StartPrice, payload.AuctionTypeParams,
+		*payload.StartTime, *payload.EndTime); err != nil {
+		return nil, err
 	}
 
 	// ========== 步骤5: 计算起拍价USD价值 ==========
 	startPriceFloat, _ := payload.StartPrice.Float64()
-	usdResponse, err := ConvertTokenAmountToUSD(&s.config, payload.PaymentToken, startPriceFloat, _ethClient)
+	var usdResponse *models.ConvertToUSDResponse
+	if pricer != nil {
+		usdResponse, err = pricer.convert(payload.PaymentToken, startPriceFloat)
+	} else {
+		usdResponse, err = ConvertTokenAmountToUSD(&s.config, payload.PaymentToken, startPriceFloat, _ethClient)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert start price to USD: %w", err)
 	}
 	startPriceUSD := decimal.NewFromFloat(usdResponse.AmountUSD)
-	startPriceUnitUSD := usdResponse.AmountUnitUSD
+	startPriceUnitUSD := onChainStartPriceUnitUSD(auctionType, payload.StartPrice, payload.FloorPrice, usdResponse.AmountUnitUSD)
 
 	// ========== 步骤6: 生成拍卖ID和时间戳 ==========
 	// 使用 snowflake 算法生成唯一拍卖ID
 	auctionID := GenerateID()
 
+	// 草稿不占用在线锁（与已释放的锁格式相同: nft_id:auction_id）
+	status := AuctionStatusPending // 新创建的拍卖默认为待上架状态
+	if payload.Draft {
+		status = AuctionStatusDraft
+		onlineLock = fmt.Sprintf("%s:%s", payload.NFTID, auctionID)
+	}
+
+	// 底价和降价间隔只对荷兰式拍卖有效，提交/揭示截止时间只对密封拍卖有效；
+	// 保留价对英式和密封拍卖有效，加价幅度只对英式拍卖有效
+	var floorPrice, reservePrice *decimal.Decimal
+	var priceDecayInterval, bidIncrementPercent uint64
+	var commitEndTime, revealEndTime *time.Time
+	switch auctionType {
+	case models.AuctionTypeEnglish:
+		reservePrice = payload.ReservePrice
+		bidIncrementPercent = payload.BidIncrementPercent
+	case models.AuctionTypeDutch:
+		floorPrice = payload.FloorPrice
+		priceDecayInterval = payload.PriceDecayInterval
+	case models.AuctionTypeSealed:
+		reservePrice = payload.ReservePrice
+		commitEndTime = payload.CommitEndTime
+		revealEndTime = payload.RevealEndTime
+	}
+
 	// 计算时间戳（Unix 时间戳，秒）
 	startTimestamp := uint64(payload.StartTime.Unix())
 	endTimestamp := uint64(payload.EndTime.Unix())
@@ -781,14 +909,15 @@ func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*
 		// 基本信息
 		AuctionID:         auctionID,
 		UserID:            userID,
-		Status:            AuctionStatusPending, // 新创建的拍卖默认为待上架状态
-		ContractAuctionID: 0,                    // 将在链上创建后设置
+		AuctionType:       auctionType,
+		Status:            status,
+		ContractAuctionID: 0, // 将在链上创建后设置
 
 		// NFT基本信息（从关联的NFT获取）
 		NFTID:          nft.NFTID,
 		NFTAddress:     payload.NFTAddress,
 		TokenID:        payload.TokenID,
-		OnlineLock:     onlineLock,      // 格式: nft_id:1，用于锁定NFT唯一性
+		OnlineLock:     onlineLock,      // 格式: nft_id:1，用于锁定NFT唯一性（草稿为 nft_id:auction_id）
 		Online:         onlineTimestamp, // 创建时间戳（表示未上线，上架后会改为1）
 		TokenURI:       nft.TokenURI,
 		ContractName:   nft.ContractName,
@@ -800,14 +929,31 @@ func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*
 		Metadata:       nft.Metadata,
 
 		// 拍卖信息
-		PaymentToken:      payload.PaymentToken,
-		StartPrice:        &payload.StartPrice,
-		StartPriceUSD:     &startPriceUSD,
-		StartPriceUnitUSD: startPriceUnitUSD,
-		StartTime:         payload.StartTime,
-		EndTime:           payload.EndTime,
-		StartTimestamp:    startTimestamp,
-		EndTimestamp:      endTimestamp,
+		PaymentToken:        payload.PaymentToken,
+		StartPrice:          &payload.StartPrice,
+		StartPriceUSD:       &startPriceUSD,
+		StartPriceUnitUSD:   startPriceUnitUSD,
+		FloorPrice:          floorPrice,
+		PriceDecayInterval:  priceDecayInterval,
+		ReservePrice:        reservePrice,
+		BidIncrementPercent: bidIncrementPercent,
+		CommitEndTime:       commitEndTime,
+		RevealEndTime:       revealEndTime,
+		StartTime:           payload.StartTime,
+		EndTime:             payload.EndTime,
+		StartTimestamp:      startTimestamp,
+		EndTimestamp:        endTimestamp,
+
+		// 流拍自动重新上架设置
+		AutoRelist:           payload.AutoRelist,
+		RelistDuration:       payload.RelistDuration,
+		RelistPriceReduction: payload.RelistPriceReduction,
+
+		// 打包拍卖
+		BundleSize: uint64(len(bundleItems)),
+
+		// 可见性
+		Visibility: normalizeVisibility(payload.Visibility),
 
 		// 出价信息（初始值）
 		HighestBid:    &decimal.Zero,
@@ -815,11 +961,72 @@ func (s *AuctionService) Create(userID uint64, payload models.AuctionPayload) (*
 		BidCount:      0,
 	}
 
-	// 保存到数据库
-	if err := database.DB.Create(&auction).Error; err != nil {
-		return nil, fmt.Errorf("failed to create auction: %w", err)
+	return &preparedAuction{
+		auction:          auction,
+		bundleItems:      bundleItems,
+		allowlistEntries: allowlistEntries,
+	}, nil
+}
+
+// savePreparedAuction 在事务中写入拍卖记录、打包成员和白名单，pending 状态的拍卖同时为全部 NFT 加锁
+func savePreparedAuction(tx *gorm.DB, prepared *preparedAuction) error {
+	auction := &prepared.auction
+	if err := tx.Create(auction).Error; err != nil {
+		return fmt.Errorf("failed to create auction: %w", err)
 	}
-	return &auction, nil
+	if len(prepared.bundleItems) > 0 {
+		for i := range prepared.bundleItems {
+			prepared.bundleItems[i].AuctionID = auction.AuctionID
+		}
+		if err := tx.Create(&prepared.bundleItems).Error; err != nil {
+			return fmt.Errorf("failed to create bundle items: %w", err)
+		}
+		auction.BundleItems = prepared.bundleItems
+	}
+	if auction.Status == AuctionStatusPending {
+		if err := lockAuctionNFTs(tx, auction); err != nil {
+			return err
+		}
+	}
+	if err := recordAuctionCreated(tx, auction, userActor(auction.UserID), TransitionCauseAPI, "create"); err != nil {
+		return err
+	}
+	return saveAllowlistEntries(tx, auction.AuctionID, prepared.allowlistEntries)
+}
+
+// verifyNFTOnChain 链上验证 NFT 归属于卖家钱包，且已单独授权给平台合约
+func (s *AuctionService) verifyNFTOnChain(walletAddress string, nftID string, nftAddress string, tokenID uint64) error {
+	platformContractAddress := common.HexToAddress(s.config.AuctionContractAddress)
+	nftContract, err := erc721_nft.NewMyNFT(common.HexToAddress(nftAddress), s.ethClient.GetClient())
+	if err != nil {
+		return fmt.Errorf("failed to create NFT contract instance: %w", err)
+	}
+
+	opts := &bind.CallOpts{Context: context.Background()}
+	tokenIDBigInt := big.NewInt(int64(tokenID))
+
+	// 检查链上NFT的实际owner是否是当前用户（地址统一转小写比较）
+	chainOwner, err := nftContract.OwnerOf(opts, tokenIDBigInt)
+	if err != nil {
+		return fmt.Errorf("failed to get NFT owner for token %d: %w", tokenID, err)
+	}
+	if strings.ToLower(chainOwner.Hex()) != walletAddress {
+		return errors.BadRequest(
+			fmt.Sprintf("NFT token %d is not owned by your wallet address. Chain owner: %s, Your wallet: %s. NFT ID: %s",
+				tokenID, chainOwner.Hex(), walletAddress, nftID))
+	}
+
+	// 检查指定TokenID的NFT是否已授权给平台合约
+	approvedAddress, err := nftContract.GetApproved(opts, tokenIDBigInt)
+	if err != nil {
+		return fmt.Errorf("failed to check GetApproved for token %d: %w", tokenID, err)
+	}
+	if approvedAddress != platformContractAddress {
+		return errors.BadRequest(
+			fmt.Sprintf("NFT token %d has not been approved for the platform contract. Please approve the NFT first. NFT ID: %s, Contract: %s",
+				tokenID, nftID, nftAddress))
+	}
+	return nil
 }
 
 // GenerateID 使用 snowflake 算法生成唯一 ID（返回字符串格式）
@@ -838,6 +1045,92 @@ func GenerateID() string {
 	return fmt.Sprintf("%d", id)
 }
 
+// validateAuctionTypeParams 校验拍卖类型相关参数
+// 荷兰式拍卖：底价必填，且 0 < 底价 < 起拍价；降价间隔不能超过拍卖持续时间
+// 密封拍卖：提交/揭示截止时间必填，且 开始时间 < 提交截止 < 揭示截止 < 结束时间
+// 英式和密封拍卖：保留价可选，不能低于起拍价
+func validateAuctionTypeParams(auctionType string, startPrice decimal.Decimal, params models.AuctionTypeParams,
+	startTime, endTime time.Time) error {
+	switch auctionType {
+	case models.AuctionTypeEnglish:
+		return validateReservePrice(startPrice, params.ReservePrice)
+	case models.AuctionTypeFixed:
+		return nil
+	case models.AuctionTypeDutch:
+		floorPrice := params.FloorPrice
+		if floorPrice == nil {
+			return errors.BadRequest("floor price is required for dutch auctions")
+		}
+		if !floorPrice.IsPositive() {
+			return errors.BadRequest("floor price must be greater than 0")
+		}
+		if !floorPrice.LessThan(startPrice) {
+			return errors.BadRequest(
+				fmt.Sprintf("floor price must be lower than start price, start price: %s, floor price: %s",
+					startPrice.String(), floorPrice.String()))
+		}
+		duration := endTime.Sub(startTime)
+		if time.Duration(params.PriceDecayInterval)*time.Second >= duration {
+			return errors.BadRequest(
+				fmt.Sprintf("price decay interval must be shorter than auction duration, interval: %ds, duration: %v",
+					params.PriceDecayInterval, duration))
+		}
+		return nil
+	case models.AuctionTypeSealed:
+		if params.CommitEndTime == nil || params.RevealEndTime == nil {
+			return errors.BadRequest("commit end time and reveal end time are required for sealed auctions")
+		}
+		if !params.CommitEndTime.After(startTime) ||
+			!params.RevealEndTime.After(*params.CommitEndTime) ||
+			!endTime.After(*params.RevealEndTime) {
+			return errors.BadRequest(
+				fmt.Sprintf("sealed auction windows must satisfy start < commit end < reveal end < end, start: %s, commit end: %s, reveal end: %s, end: %s",
+					startTime.Format("2006-01-02 15:04:05"),
+					params.CommitEndTime.Format("2006-01-02 15:04:05"),
+					params.RevealEndTime.Format("2006-01-02 15:04:05"),
+					endTime.Format("2006-01-02 15:04:05")))
+		}
+		return validateReservePrice(startPrice, params.ReservePrice)
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported auction type: %s", auctionType))
+	}
+}
+
+// minAuctionDuration 拍卖最短持续时间
+const minAuctionDuration = time.Minute
+
+// validateAuctionTimes 校验拍卖时间（创建和更新共用）：开始、结束时间必填，结束时间晚于开始时间且持续至少 minAuctionDuration
+func validateAuctionTimes(startTime, endTime *time.Time) error {
+	if startTime == nil || endTime == nil {
+		return errors.BadRequest("start time and end time are required")
+	}
+	if !endTime.After(*startTime) {
+		return errors.BadRequest(
+			fmt.Sprintf("end time must be after start time, start time: %s, end time: %s",
+				startTime.Format("2006-01-02 15:04:05"),
+				endTime.Format("2006-01-02 15:04:05")))
+	}
+	if endTime.Sub(*startTime) < minAuctionDuration {
+		return errors.BadRequest(
+			fmt.Sprintf("auction duration must be at least 1 minute, current duration: %v",
+				endTime.Sub(*startTime)))
+	}
+	return nil
+}
+
+// validateReservePrice 校验保留价（可选）：不能低于起拍价
+func validateReservePrice(startPrice decimal.Decimal, reservePrice *decimal.Decimal) error {
+	if reservePrice == nil {
+		return nil
+	}
+	if reservePrice.LessThan(startPrice) {
+		return errors.BadRequest(
+			fmt.Sprintf("reserve price must not be lower than start price, start price: %s, reserve price: %s",
+				startPrice.String(), reservePrice.String()))
+	}
+	return nil
+}
+
 func (s *AuctionService) UpdateContractID(id uint64, contractAuctionID uint64) error {
 	if err := database.DB.Model(&models.Auction{}).
 		Where("id = ?", id).
@@ -847,13 +1140,30 @@ func (s *AuctionService) UpdateContractID(id uint64, contractAuctionID uint64) e
 	return nil
 }
 
+// UpdateStatus 由后台任务变更拍卖状态（经过状态机校验并记录变更历史）
 func (s *AuctionService) UpdateStatus(id uint64, status string) error {
-	if err := database.DB.Model(&models.Auction{}).
-		Where("id = ?", id).
-		Update("status", status).Error; err != nil {
-		return fmt.Errorf("failed to update auction status: %w", err)
-	}
-	return nil
+	var auction models.Auction
+	if err := database.DB.Where("id = ?", id).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFound("auction not found")
+		}
+		return fmt.Errorf("failed to get auction: %w", err)
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		changed, err := transitionAuction(tx, &auction, StatusTransition{
+			To:     status,
+			Actor:  TransitionActorSystem,
+			Cause:  TransitionCauseTask,
+			Reason: "update_status",
+		})
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return errors.BadRequest("auction status has been changed concurrently")
+		}
+		return nil
+	})
 }
 
 func (s *AuctionService) UpdateHighestBid(id uint64, highestBidPaymentToken string, highestBid decimal.Decimal, highestBidUSD decimal.Decimal, highestBidder string) error {
@@ -905,6 +1215,7 @@ func (s *AuctionService) GetUserAuctions(userID uint64, query page.PageQuery, st
 
 	if err := queryDB.
 		Preload("User").
+		Preload("BundleItems", preloadBundleItems).
 		Order("created_at DESC").
 		Offset(query.Offset()).
 		Limit(query.Limit()).
@@ -912,6 +1223,7 @@ func (s *AuctionService) GetUserAuctions(userID uint64, query page.PageQuery, st
 		return nil, 0, err
 	}
 
+	fillSaleOutcomes(auctions)
 	return auctions, total, nil
 }
 
@@ -940,7 +1252,7 @@ func (s *AuctionService) GetUserAuctionHistory(userID uint64, query page.PageQue
 	}
 
 	if err := queryDB.
-		Select("auction_id, nft_id, nft_address, token_id, nft_name, image, contract_name, contract_symbol, status, payment_token, start_price, start_price_usd, end_time, end_timestamp, bid_count, highest_bid, highest_bid_usd, highest_bidder, highest_bid_payment_token").
+		Select("auction_id, nft_id, nft_address, token_id, nft_name, image, contract_name, contract_symbol, status, payment_token, start_price, start_price_usd, end_time, end_timestamp, bid_count, highest_bid, highest_bid_usd, highest_bidder, highest_bid_payment_token, relist_generation, relisted_from").
 		Order("created_at DESC").
 		Offset(query.Offset()).
 		Limit(query.Limit()).
@@ -967,6 +1279,9 @@ func (s *AuctionService) GetUserAuctionHistory(userID uint64, query page.PageQue
 			EndTime:        auction.EndTime,
 			EndTimestamp:   auction.EndTimestamp,
 			BidCount:       auction.BidCount,
+
+			RelistGeneration: auction.RelistGeneration,
+			RelistedFrom:     auction.RelistedFrom,
 		}
 
 		// 如果有出价，才显示出价信息
@@ -983,7 +1298,7 @@ func (s *AuctionService) GetUserAuctionHistory(userID uint64, query page.PageQue
 	return historyList, total, nil
 }
 
-// Update 更新拍卖信息（只能更新待上架状态的拍卖）
+// Update 更新拍卖信息（只能更新草稿或待上架状态的拍卖）
 func (s *AuctionService) Update(userID uint64, auctionID string, payload models.UpdateAuctionPayload) (*models.Auction, error) {
 	// 先查询拍卖是否存在且属于当前用户
 	var auction models.Auction
@@ -991,9 +1306,17 @@ func (s *AuctionService) Update(userID uint64, auctionID string, payload models.
 		return nil, errors.Forbidden("auction not found or access denied")
 	}
 
-	// 只有待上架（pending）状态的拍卖可以更新
-	if auction.Status != AuctionStatusPending {
-		return nil, errors.Forbidden("cannot update auction: only pending auctions can be updated")
+	// 只有草稿（draft）和待上架（pending）状态的拍卖可以更新
+	if auction.Status != AuctionStatusDraft && auction.Status != AuctionStatusPending {
+		return nil, errors.Forbidden("cannot update auction: only draft or pending auctions can be updated")
+	}
+
+	if err := validateAuctionTimes(payload.StartTime, payload.EndTime); err != nil {
+		return nil, err
+	}
+	if err := validateAuctionTypeParams(auction.AuctionType, payload.StartPrice, payload.AuctionTypeParams,
+		*payload.StartTime, *payload.EndTime); err != nil {
+		return nil, err
 	}
 
 	// 计算起始价的 USD 价值
@@ -1005,7 +1328,7 @@ func (s *AuctionService) Update(userID uint64, auctionID string, payload models.
 		return nil, fmt.Errorf("failed to convert start price to USD: %w", err)
 	}
 	startPriceUSD := usdResponse.AmountUSD
-	startPriceUnitUSD := usdResponse.AmountUnitUSD
+	startPriceUnitUSD := onChainStartPriceUnitUSD(auction.AuctionType, payload.StartPrice, payload.FloorPrice, usdResponse.AmountUnitUSD)
 
 	// 计算时间戳（Unix 时间戳，秒）
 	startTimestamp := uint64(payload.StartTime.Unix())
@@ -1013,14 +1336,29 @@ func (s *AuctionService) Update(userID uint64, auctionID string, payload models.
 
 	// 更新拍卖信息
 	updates := map[string]interface{}{
-		"payment_token":        payload.PaymentToken,
-		"start_price":          payload.StartPrice,
-		"start_price_usd":      startPriceUSD,
-		"start_price_unit_usd": startPriceUnitUSD,
-		"start_time":           payload.StartTime,
-		"end_time":             payload.EndTime,
-		"start_timestamp":      startTimestamp,
-		"end_timestamp":        endTimestamp,
+		"payment_token":          payload.PaymentToken,
+		"start_price":            payload.StartPrice,
+		"start_price_usd":        startPriceUSD,
+		"start_price_unit_usd":   startPriceUnitUSD,
+		"start_time":             payload.StartTime,
+		"end_time":               payload.EndTime,
+		"start_timestamp":        startTimestamp,
+		"end_timestamp":          endTimestamp,
+		"auto_relist":            payload.AutoRelist,
+		"relist_duration":        payload.RelistDuration,
+		"relist_price_reduction": payload.RelistPriceReduction,
+	}
+	switch {
+	case auction.IsEnglish():
+		updates["reserve_price"] = payload.ReservePrice
+		updates["bid_increment_percent"] = payload.BidIncrementPercent
+	case auction.IsDutch():
+		updates["floor_price"] = payload.FloorPrice
+		updates["price_decay_interval"] = payload.PriceDecayInterval
+	case auction.IsSealed():
+		updates["reserve_price"] = payload.ReservePrice
+		updates["commit_end_time"] = payload.CommitEndTime
+		updates["reveal_end_time"] = payload.RevealEndTime
 	}
 
 	if err := database.DB.Model(&auction).Updates(updates).Error; err != nil {
@@ -1035,104 +1373,195 @@ func (s *AuctionService) Update(userID uint64, auctionID string, payload models.
 	return &auction, nil
 }
 
-// Cancel 取消拍卖
-// TODO: 实现具体的取消逻辑
-func (s *AuctionService) Cancel(userID uint64, auctionID string) (*models.Auction, error) {
-	// 先查询拍卖是否存在且属于当前用户
+// Watch 关注拍卖（重复关注不报错），关注者会收到拍卖即将结束提醒
+func (s *AuctionService) Watch(userID uint64, auctionID string) error {
 	var auction models.Auction
-	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).First(&auction).Error; err != nil {
-		return nil, errors.Forbidden("auction not found or access denied")
-	}
-	if auction.Status == AuctionStatusPending { //直接执行取消
-		if err := database.DB.Model(&auction).Updates(map[string]interface{}{
-			"online_lock": fmt.Sprintf("%s:%d", auction.NFTID, auction.TokenID), //解锁nft唯一性锁定
-			"status":      AuctionStatusCancelled,
-		}).Error; err != nil {
-			//合约执行取消
-			client := s.ethClient.GetClient()
-			myAuction, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), client)
-			if err != nil {
-				logger.Error("failed to create my auction: %v", err)
-				return nil, fmt.Errorf("failed to create my auction: %w", err)
-			}
-			ctx := context.Background()
-			auth, err := s.ethClient.GetAuth(ctx, s.config.PlatformPrivateKey)
-			if err != nil {
-				logger.Error("failed to get auth: %v", err)
-				return nil, fmt.Errorf("failed to execute cancel auction")
-			}
-			if tx, err := myAuction.CancelAuction(auth, big.NewInt(int64(auction.ContractAuctionID))); err != nil {
-				logger.Error("failed to wait for transaction: %v", err)
-				return nil, fmt.Errorf("failed to wait for transaction")
-			} else {
-				logger.Info("cancel auction transaction sent: %v", tx.Hash())
-			}
+	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NotFound("auction not found")
 		}
+		return fmt.Errorf("failed to get auction: %w", err)
 	}
-	return &auction, nil
+	if auction.Status == AuctionStatusEnded || auction.Status == AuctionStatusCancelled || auction.Status == AuctionStatusExpired {
+		return errors.BadRequest("auction has already finished")
+	}
+	if err := s.CheckAccess(&auction, userID); err != nil {
+		return err
+	}
+
+	watch := models.AuctionWatch{
+		AuctionID: auctionID,
+		UserID:    userID,
+	}
+	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).
+		FirstOrCreate(&watch).Error; err != nil {
+		return fmt.Errorf("failed to watch auction: %w", err)
+	}
+	return nil
 }
 
-// Publish 上架拍卖（将状态从 pending 改为 active）
-func (s *AuctionService) Publish(userID uint64, auctionID uint64) (*models.Auction, error) {
-	// 先查询拍卖是否存在且属于当前用户
-	var auction models.Auction
-	if err := database.DB.Where("id = ? AND user_id = ?", auctionID, userID).First(&auction).Error; err != nil {
-		return nil, errors.Forbidden("auction not found or access denied")
+// Unwatch 取消关注拍卖
+func (s *AuctionService) Unwatch(userID uint64, auctionID string) error {
+	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).
+		Delete(&models.AuctionWatch{}).Error; err != nil {
+		return fmt.Errorf("failed to unwatch auction: %w", err)
 	}
+	return nil
+}
 
-	// 只有待上架（pending）状态的拍卖可以上架
-	if auction.Status != AuctionStatusPending {
-		return nil, errors.Forbidden("cannot publish auction: only pending auctions can be published")
+// Relist 流拍自动重新上架：以同一 NFT 创建新的 pending 拍卖，等待卖家重新授权并签名链上 createAuction
+// 新拍卖时长为 RelistDuration（0 沿用原时长），起拍价（荷兰式拍卖同时包括底价）按 RelistPriceReduction 百分比下调，
+// 密封拍卖的提交/揭示截止时间按新时长等比例换算；RelistedFrom 指向前一代拍卖
+func (s *AuctionService) Relist(ended *models.Auction) (*models.Auction, error) {
+	if ended.StartTime == nil || ended.EndTime == nil || ended.StartPrice == nil {
+		return nil, fmt.Errorf("auction time or start price is missing, cannot relist: auctionID=%s", ended.AuctionID)
 	}
 
-	// 校验拍卖时间
-	now := time.Now()
+	// 结束任务重试时不重复创建
+	var existing models.Auction
+	if err := database.DB.Where("relisted_from = ?", ended.AuctionID).First(&existing).Error; err == nil {
+		return &existing, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to check relisted auction: %w", err)
+	}
 
-	// 开始时间可以小于当前时间（允许已经开始）
-	// 结束时间必须大于当前时间（拍卖必须还未结束）
-	if auction.EndTime.Before(now) || auction.EndTime.Equal(now) {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("end time must be in the future, current end time: %s, now: %s",
-				auction.EndTime.Format("2006-01-02 15:04:05"),
-				now.Format("2006-01-02 15:04:05")))
+	// 卖家仍需持有该 NFT（流拍后 nft_ownerships 已恢复为 holding）
+	var ownership models.NFTOwnership
+	if err := database.DB.Where("nft_id = ? AND user_id = ? AND status = ?",
+		ended.NFTID, ended.UserID, models.NFTOwnershipStatusHolding).First(&ownership).Error; err != nil {
+		return nil, fmt.Errorf("seller no longer holds the NFT: nftID=%s, error=%w", ended.NFTID, err)
 	}
 
-	// 检查结束时间是否在开始时间之后
-	if auction.StartTime == nil || auction.EndTime == nil {
-		return nil, errors.BadRequest("start time and end time are required")
+	// 打包拍卖：所有成员 NFT 同样需要仍由卖家持有
+	var bundleItems []models.AuctionBundleItem
+	if ended.BundleSize > 0 {
+		items, err := loadBundleItems(database.DB, ended.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			var count int64
+			if err := database.DB.Model(&models.NFTOwnership{}).
+				Where("nft_id = ? AND user_id = ? AND status = ?", item.NFTID, ended.UserID, models.NFTOwnershipStatusHolding).
+				Count(&count).Error; err != nil {
+				return nil, fmt.Errorf("failed to verify bundle NFT ownership: %w", err)
+			}
+			if count == 0 {
+				return nil, fmt.Errorf("seller no longer holds bundle NFT: nftID=%s", item.NFTID)
+			}
+			item.ID = 0
+			item.CreatedAt = nil
+			bundleItems = append(bundleItems, item)
+		}
 	}
-	if auction.EndTime.Before(*auction.StartTime) || auction.EndTime.Equal(*auction.StartTime) {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("end time must be after start time, start time: %s, end time: %s",
-				auction.StartTime.Format("2006-01-02 15:04:05"),
-				auction.EndTime.Format("2006-01-02 15:04:05")))
+
+	// 邀请制拍卖沿用原白名单
+	allowlistEntries, err := loadAllowlistEntries(database.DB, ended.AuctionID)
+	if err != nil {
+		return nil, err
 	}
 
-	// 检查拍卖持续时间是否合理（至少1分钟）
-	minDuration := 1 * time.Minute
-	if auction.EndTime.Sub(*auction.StartTime) < minDuration {
-		return nil, errors.BadRequest(
-			fmt.Sprintf("auction duration must be at least 1 minute, current duration: %v",
-				auction.EndTime.Sub(*auction.StartTime)))
+	originalDuration := ended.EndTime.Sub(*ended.StartTime)
+	duration := originalDuration
+	if ended.RelistDuration > 0 {
+		duration = time.Duration(ended.RelistDuration) * time.Second
 	}
+	now := time.Now()
+	startTime := now
+	endTime := now.Add(duration)
 
-	// 不修改 status（保持为 pending），只更新 nft_online_id 为 nft_id:1（表示已上线），online 为 1
-	// pending 状态表示准备开卖了
-	nftOnlineID := fmt.Sprintf("%s:1", auction.NFTID)
-	online := uint64(1)
-	updates := map[string]interface{}{
-		"nft_online_id": nftOnlineID,
-		"online":        online,
+	ratio := decimal.NewFromInt(int64(100 - ended.RelistPriceReduction)).Div(decimal.NewFromInt(100))
+	startPrice := ended.StartPrice.Mul(ratio)
+
+	params := scaledTypeParams(ended, startTime, duration)
+	if params.FloorPrice != nil {
+		floorPrice := params.FloorPrice.Mul(ratio)
+		params.FloorPrice = &floorPrice
 	}
-	if err := database.DB.Model(&auction).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("failed to publish auction: %w", err)
+	if err := validateAuctionTypeParams(ended.AuctionType, startPrice, params, startTime, endTime); err != nil {
+		return nil, err
 	}
 
-	// 重新加载更新后的数据
-	if err := database.DB.Preload("User").First(&auction, auctionID).Error; err != nil {
-		return nil, fmt.Errorf("failed to reload auction: %w", err)
+	startPriceFloat, _ := startPrice.Float64()
+	usdResponse, err := ConvertTokenAmountToUSD(&s.config, ended.PaymentToken, startPriceFloat, s.ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert start price to USD: %w", err)
+	}
+	startPriceUSD := decimal.NewFromFloat(usdResponse.AmountUSD)
+
+	auction := models.Auction{
+		AuctionID:         GenerateID(),
+		UserID:            ended.UserID,
+		AuctionType:       ended.AuctionType,
+		Status:            AuctionStatusPending,
+		ContractAuctionID: 0,
+
+		NFTID:          ended.NFTID,
+		NFTAddress:     ended.NFTAddress,
+		TokenID:        ended.TokenID,
+		OnlineLock:     fmt.Sprintf("%s:1", ended.NFTID),
+		Online:         uint64(now.Unix()),
+		TokenURI:       ended.TokenURI,
+		ContractName:   ended.ContractName,
+		ContractSymbol: ended.ContractSymbol,
+		NftName:        ended.NftName,
+		OwnerAddress:   ownership.OwnerAddress,
+		Image:          ended.Image,
+		Description:    ended.Description,
+		Metadata:       ended.Metadata,
+
+		PaymentToken:        ended.PaymentToken,
+		StartPrice:          &startPrice,
+		StartPriceUSD:       &startPriceUSD,
+		StartPriceUnitUSD:   onChainStartPriceUnitUSD(ended.AuctionType, startPrice, params.FloorPrice, usdResponse.AmountUnitUSD),
+		FloorPrice:          params.FloorPrice,
+		PriceDecayInterval:  ended.PriceDecayInterval,
+		ReservePrice:        params.ReservePrice,
+		BidIncrementPercent: params.BidIncrementPercent,
+		CommitEndTime:       params.CommitEndTime,
+		RevealEndTime:       params.RevealEndTime,
+		StartTime:           &startTime,
+		EndTime:             &endTime,
+		StartTimestamp:      uint64(startTime.Unix()),
+		EndTimestamp:        uint64(endTime.Unix()),
+
+		AutoRelist:           ended.AutoRelist,
+		RelistDuration:       ended.RelistDuration,
+		RelistPriceReduction: ended.RelistPriceReduction,
+		RelistGeneration:     ended.RelistGeneration + 1,
+		RelistedFrom:         ended.AuctionID,
+		BundleSize:           ended.BundleSize,
+		Visibility:           normalizeVisibility(ended.Visibility),
+
+		HighestBid:    &decimal.Zero,
+		HighestBidUSD: &decimal.Zero,
+		BidCount:      0,
 	}
 
+	// nft_locks 主键保证卖家已手动重新上架（主 NFT 或任一成员已被锁定）时不会重复创建
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&auction).Error; err != nil {
+			return fmt.Errorf("failed to create relisted auction: %w", err)
+		}
+		if len(bundleItems) > 0 {
+			for i := range bundleItems {
+				bundleItems[i].AuctionID = auction.AuctionID
+			}
+			if err := tx.Create(&bundleItems).Error; err != nil {
+				return fmt.Errorf("failed to create relisted bundle items: %w", err)
+			}
+			auction.BundleItems = bundleItems
+		}
+		if err := lockAuctionNFTs(tx, &auction); err != nil {
+			return err
+		}
+		if err := recordAuctionCreated(tx, &auction, TransitionActorSystem, TransitionCauseTask, "relist"); err != nil {
+			return err
+		}
+		return saveAllowlistEntries(tx, auction.AuctionID, allowlistEntries)
+	}); err != nil {
+		return nil, err
+	}
 	return &auction, nil
 }
 
@@ -1162,7 +1591,7 @@ func (s *AuctionService) GetSupportedTokens() ([]map[string]interface{}, error)
 	return tokens, nil
 }
 
-func (s *AuctionService) OnEventAuctionCreated(auctionContractId uint64, ownerAddress string, nftAddress string, tokenId uint64) error {
+func (s *AuctionService) OnEventAuctionCreated(auctionContractId uint64, ownerAddress string, nftAddress string, tokenId uint64, txHash string) error {
 	// 开启事务
 	// 先查询拍卖是否存在且属于当前用户
 
@@ -1170,25 +1599,54 @@ func (s *AuctionService) OnEventAuctionCreated(auctionContractId uint64, ownerAd
 	nftOnlineLock := fmt.Sprintf("%s:1", nftID)
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		var auction models.Auction
-		if err := tx.Where("online_lock = ? and status = ?", nftOnlineLock, AuctionStatusPending).First(&auction).Error; err != nil {
-			logger.Error("failed to get auction: %v", err)
-			return fmt.Errorf("failed to get auction: %w", err)
+		// 加行锁，与待上架拍卖过期扫描互斥
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("online_lock = ? and status = ?", nftOnlineLock, AuctionStatusPending).First(&auction).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				logger.Error("failed to get auction: %v", err)
+				return fmt.Errorf("failed to get auction: %w", err)
+			}
+			// 拍卖已因超时或卖家放弃而过期，事件迟到时恢复该拍卖
+			revived, err := reviveExpiredAuction(tx, nftID, ownerAddress, txHash)
+			if err != nil {
+				logger.Error("failed to get auction: %v", err)
+				return err
+			}
+			auction = *revived
+		}
+		// 开始时间未到的拍卖先进入 upcoming，由 auction-start 任务切换为 active
+		status := AuctionStatusActive
+		if auction.StartTime != nil && auction.StartTime.After(time.Now()) {
+			status = AuctionStatusUpcoming
 		}
-		// 更新拍卖状态为 active
+		// 更新拍卖状态
 		// 更新拍卖的合同拍卖ID
-		if err := tx.Model(&auction).
-			Updates(map[string]interface{}{
-				"status":              AuctionStatusActive,
+		changed, err := transitionAuction(tx, &auction, StatusTransition{
+			To:     status,
+			Actor:  ownerAddress,
+			Cause:  TransitionCauseEvent,
+			Reason: "auction_created",
+			TxHash: txHash,
+			Updates: map[string]interface{}{
 				"contract_auction_id": auctionContractId,
 				"owner_address":       ownerAddress, //拍卖合约地址
 				"online":              1,            //上线
-			}).Error; err != nil {
+			},
+		})
+		if err != nil {
 			logger.Error("failed to update auction status: %v", err)
 			return fmt.Errorf("failed to update auction status: %w", err)
 		}
-		//更新nft_ownerships
+		if !changed {
+			return fmt.Errorf("auction %s is no longer pending", auction.AuctionID)
+		}
+		//更新nft_ownerships（打包拍卖的所有成员同时变为在售）
+		nftIDs, err := auctionNFTIDs(tx, &auction)
+		if err != nil {
+			return err
+		}
 		if err := tx.Model(&models.NFTOwnership{}).
-			Where("nft_id = ? and user_id = ?", nftID, auction.UserID).
+			Where("nft_id IN ? and user_id = ?", nftIDs, auction.UserID).
 			Updates(map[string]interface{}{
 				"owner_address": ownerAddress,                     //委托给拍卖合约	ownerAddress
 				"status":        models.NFTOwnershipStatusSelling, // 在售
@@ -1203,7 +1661,8 @@ func (s *AuctionService) OnEventAuctionCreated(auctionContractId uint64, ownerAd
 		// 获取最新拍卖信息开始调度拍卖结束任务
 		// 如果 end_time 改变了，重新调度任务
 		var auction models.Auction
-		if err := database.DB.Where("online_lock = ? and status = ?", nftOnlineLock, AuctionStatusActive).First(&auction).Error; err != nil {
+		if err := database.DB.Where("online_lock = ? and status IN ?", nftOnlineLock,
+			[]string{AuctionStatusActive, AuctionStatusUpcoming}).First(&auction).Error; err != nil {
 			logger.Error("failed to get auction: %v", err)
 			return fmt.Errorf("failed to get auction: %w", err)
 		} else {
@@ -1218,9 +1677,96 @@ func (s *AuctionService) OnEventAuctionCreated(auctionContractId uint64, ownerAd
 				} else {
 					logger.Info("Auction end task rescheduled: auctionID=%s, endTime=%v", auction.AuctionID, auction.EndTime)
 				}
+				// 开始时间未到：调度开始任务，到点切换为 active 并推送 auction_started
+				if auction.Status == AuctionStatusUpcoming {
+					if err := s.taskScheduler.ScheduleAuctionStartTask(&auction); err != nil {
+						logger.Error("Failed to schedule auction start task: auctionID=%s, error=%v", auction.AuctionID, err)
+					}
+				}
+				// 密封拍卖：揭示截止后由后端确定获胜者和成交价
+				if auction.IsSealed() {
+					if err := s.taskScheduler.ScheduleSealedRevealEndTask(&auction); err != nil {
+						logger.Error("Failed to schedule sealed reveal end task: auctionID=%s, error=%v", auction.AuctionID, err)
+					}
+				}
 			}
 			logger.Info("OnEventAuctionCreated: auction updated successfully, contractAuctionId=%d, nftAddress=%s, tokenId=%d", auctionContractId, nftAddress, tokenId)
 		}
 	}
 	return err
 }
+
+// FlagBidBelowIncrement 英式拍卖中标记未达到最小加价幅度的链上出价（BidPlaced 事件到达时调用）
+// 合约只要求出价高于当前最高价，无法拒绝这些出价；被标记的出价如果结束时仍是最高出价，拍卖不成交并由平台取消
+// 返回出价是否被标记
+func (s *AuctionService) FlagBidBelowIncrement(bid *models.Bid) (bool, error) {
+	if bid.Flagged {
+		return false, nil
+	}
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", bid.AuctionID).First(&auction).Error; err != nil {
+		return false, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !belowBidIncrement(&auction, bid) {
+		return false, nil
+	}
+
+	if err := flagBid(database.DB, bid, models.BidFlagBelowBidIncrement); err != nil {
+		return false, err
+	}
+	logger.Warn("bid below minimum increment flagged: auctionID=%s, bidID=%d, amountUnitUSD=%d, previousUnitUSD=%d, increment=%d%%",
+		bid.AuctionID, bid.ID, bid.AmountUnitUSD, bid.MinBidUnitUSD, auction.BidIncrementPercent)
+	return true, nil
+}
+
+// OnInstantSaleBidPlaced 荷兰式拍卖和固定价格出售的出价处理：首个达到当前价格（固定价格即标价）的出价直接成交
+// 将拍卖结束时间提前到出价时刻，并立即调度拍卖结束任务（由调度器强制结束并结算）
+// 获胜出价在结算时按链上最高出价者确定（结算交易上链前可能还有更高的出价）
+// 返回值表示该出价是否使拍卖成交
+func (s *AuctionService) OnInstantSaleBidPlaced(bid *models.Bid) (bool, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", bid.AuctionID).First(&auction).Error; err != nil {
+		return false, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.IsInstantSale() || auction.Status != AuctionStatusActive {
+		return false, nil
+	}
+	if bid.Flagged {
+		logger.Info("instant sale bid from non-allowlisted wallet ignored: auctionID=%s, bidID=%d", auction.AuctionID, bid.ID)
+		return false, nil
+	}
+
+	if !instantSaleQualified(&auction, bid) {
+		logger.Info("instant sale bid below current price: auctionID=%s, type=%s, bidID=%d", auction.AuctionID, auction.AuctionType, bid.ID)
+		return false, nil
+	}
+
+	bidTime := time.Now()
+	if bid.Timestamp > 0 {
+		bidTime = time.Unix(int64(bid.Timestamp), 0)
+	}
+
+	// 只有第一个达标出价会更新结束时间（end_timestamp 未被提前过）
+	result := database.DB.Model(&models.Auction{}).
+		Where("auction_id = ? AND status = ? AND end_timestamp > ?", auction.AuctionID, AuctionStatusActive, bidTime.Unix()).
+		Updates(map[string]interface{}{
+			"end_time":      bidTime,
+			"end_timestamp": bidTime.Unix(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to end instant sale auction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	auction.EndTime = &bidTime
+	auction.EndTimestamp = uint64(bidTime.Unix())
+	if s.taskScheduler != nil {
+		if err := s.taskScheduler.ScheduleAuctionEndTask(&auction); err != nil {
+			return true, fmt.Errorf("failed to schedule instant sale settlement: %w", err)
+		}
+	}
+	logger.Info("instant sale closed by first qualifying bid: auctionID=%s, type=%s, bidder=%s", auction.AuctionID, auction.AuctionType, bid.WalletAddress)
+	return true, nil
+}
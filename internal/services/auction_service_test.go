@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateAuctionTimes(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := start.Add(d)
+		return &v
+	}
+
+	tests := []struct {
+		name    string
+		start   *time.Time
+		end     *time.Time
+		wantErr bool
+	}{
+		{"valid", at(0), at(time.Hour), false},
+		{"exactly minimum duration", at(0), at(minAuctionDuration), false},
+		{"missing start", nil, at(time.Hour), true},
+		{"missing end", at(0), nil, true},
+		{"end before start", at(0), at(-time.Minute), true},
+		{"end equals start", at(0), at(0), true},
+		{"shorter than minimum", at(0), at(30 * time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAuctionTimes(tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAuctionTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/models"
+)
+
+// 拍卖结算校验：
+// 合约只按最高出价结算，荷兰式拍卖的价格递减、密封拍卖的揭示结果、保留价和加价幅度等规则由后端在结算前校验；
+// 校验不通过时不结算，由平台调用合约 cancelAuction 取消拍卖（合约退款给最高出价者，NFT 退回卖家）
+
+// 结算校验不通过的原因（记录在取消请求的 reason 中）
+const (
+	SettlementRejectBelowCurrentPrice        = "below_current_price"         // 荷兰式拍卖最高出价未达到出价时刻的当前价格
+	SettlementRejectNotAllowlisted           = "bidder_not_allowlisted"      // 邀请制拍卖链上最高出价者不在白名单中
+	SettlementRejectBelowReservePrice        = "below_reserve_price"         // 英式或密封拍卖最高出价低于保留价
+	SettlementRejectBelowBidIncrement        = "below_bid_increment"         // 英式拍卖最高出价未达到最小加价幅度
+	SettlementRejectSealedNoWinner           = "sealed_no_winner"            // 密封拍卖没有有效揭示，但链上有出价
+	SettlementRejectSealedWinnerMismatch     = "sealed_winner_mismatch"      // 密封拍卖链上最高出价者不是揭示结果的获胜者
+	SettlementRejectSealedOutsideSettlement  = "sealed_outside_settlement"   // 密封拍卖获胜者的链上出价不在结算阶段
+	SettlementRejectSealedBelowClearingPrice = "sealed_below_clearing_price" // 密封拍卖获胜者的链上出价低于成交价
+)
+
+// onChainStartPriceUnitUSD 合约 createAuction 使用的最低出价（USD*10**8）
+// 荷兰式拍卖的价格递减在链下执行，链上最低出价按底价与起拍价的比例换算，保证降价后的出价能通过合约校验
+func onChainStartPriceUnitUSD(auctionType string, startPrice decimal.Decimal, floorPrice *decimal.Decimal, startPriceUnitUSD uint64) uint64 {
+	if auctionType != models.AuctionTypeDutch || floorPrice == nil || !startPrice.IsPositive() {
+		return startPriceUnitUSD
+	}
+	return uint64(decimal.NewFromInt(int64(startPriceUnitUSD)).Mul(*floorPrice).Div(startPrice).IntPart())
+}
+
+// instantSaleQualified 出价是否达到出价时刻的当前价格（同币种直接比较代币金额，不同币种比较USD价值）
+func instantSaleQualified(auction *models.Auction, bid *models.Bid) bool {
+	bidTime := time.Now()
+	if bid.Timestamp > 0 {
+		bidTime = time.Unix(int64(bid.Timestamp), 0)
+	}
+	if auction.IsBidTokenSameAsAuction(bid.PaymentToken) && bid.Amount != nil {
+		return bid.Amount.GreaterThanOrEqual(auction.CurrentPrice(bidTime))
+	}
+	if bid.AmountUSD != nil {
+		return bid.AmountUSD.GreaterThanOrEqual(auction.CurrentPriceUSD(bidTime))
+	}
+	return false
+}
+
+// findWinningBid 链上最高出价者在拍卖中的最后一次出价（没有出价记录时返回 nil）
+func findWinningBid(tx *gorm.DB, auctionID string, bidder string) (*models.Bid, error) {
+	var bid models.Bid
+	if err := tx.Where("auction_id = ? AND wallet_address = ?", auctionID, bidder).
+		Order("id DESC").First(&bid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get winning bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// markWinningBid 结算时将链上最高出价者的出价标记为获胜出价
+func markWinningBid(tx *gorm.DB, bid *models.Bid) error {
+	if bid == nil {
+		return nil
+	}
+	if err := tx.Model(&models.Bid{}).Where("id = ?", bid.ID).Update("winner", true).Error; err != nil {
+		return fmt.Errorf("failed to mark winning bid: %w", err)
+	}
+	bid.Winner = true
+	return nil
+}
+
+// settlementRejection 拍卖不能按链上最高出价成交的原因，可以成交时返回空字符串
+func settlementRejection(auction *models.Auction, bid *models.Bid) string {
+	if bid == nil {
+		return ""
+	}
+	switch {
+	case bid.FlagReason == models.BidFlagBelowBidIncrement:
+		return SettlementRejectBelowBidIncrement
+	case auction.RequiresMembership() && bid.Flagged:
+		return SettlementRejectNotAllowlisted
+	}
+	switch {
+	case auction.IsDutch():
+		if !instantSaleQualified(auction, bid) {
+			return SettlementRejectBelowCurrentPrice
+		}
+	case auction.IsSealed():
+		if reason := sealedSettlementRejection(auction, bid); reason != "" {
+			return reason
+		}
+		if belowReservePrice(auction, bid) {
+			return SettlementRejectBelowReservePrice
+		}
+	case auction.IsEnglish():
+		if belowReservePrice(auction, bid) {
+			return SettlementRejectBelowReservePrice
+		}
+	}
+	return ""
+}
+
+// belowReservePrice 出价是否低于保留价（同币种直接比较代币金额，不同币种比较USD价值）
+func belowReservePrice(auction *models.Auction, bid *models.Bid) bool {
+	if auction.ReservePrice == nil {
+		return false
+	}
+	if auction.IsBidTokenSameAsAuction(bid.PaymentToken) && bid.Amount != nil {
+		return bid.Amount.LessThan(*auction.ReservePrice)
+	}
+	if bid.AmountUSD != nil {
+		return bid.AmountUSD.LessThan(auction.ReservePriceUSD())
+	}
+	return false
+}
+
+// belowBidIncrement 英式拍卖出价是否未达到最小加价幅度（按USD价值与上一个最高出价比较，首次出价不限制）
+func belowBidIncrement(auction *models.Auction, bid *models.Bid) bool {
+	if !auction.IsEnglish() || auction.BidIncrementPercent == 0 || bid.MinBidUnitUSD == 0 {
+		return false
+	}
+	required := decimal.NewFromInt(int64(bid.MinBidUnitUSD)).
+		Mul(decimal.NewFromInt(int64(100 + auction.BidIncrementPercent))).
+		Div(decimal.NewFromInt(100))
+	return decimal.NewFromInt(int64(bid.AmountUnitUSD)).LessThan(required)
+}
+
+// flagBid 标记出价并记录原因
+func flagBid(db *gorm.DB, bid *models.Bid, reason string) error {
+	if err := db.Model(bid).Updates(map[string]interface{}{"flagged": true, "flag_reason": reason}).Error; err != nil {
+		return fmt.Errorf("failed to flag bid: %w", err)
+	}
+	bid.Flagged = true
+	bid.FlagReason = reason
+	return nil
+}
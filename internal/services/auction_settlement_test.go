@@ -0,0 +1,225 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"my-auction-market-api/internal/models"
+)
+
+func TestOnChainStartPriceUnitUSD(t *testing.T) {
+	startPrice := decimal.NewFromInt(2)
+	floorPrice := decimal.RequireFromString("0.5")
+
+	tests := []struct {
+		name        string
+		auctionType string
+		startPrice  decimal.Decimal
+		floorPrice  *decimal.Decimal
+		want        uint64
+	}{
+		{"english uses start price", models.AuctionTypeEnglish, startPrice, nil, 600000000000},
+		{"fixed uses start price", models.AuctionTypeFixed, startPrice, &floorPrice, 600000000000},
+		{"dutch uses floor price", models.AuctionTypeDutch, startPrice, &floorPrice, 150000000000},
+		{"dutch without floor price", models.AuctionTypeDutch, startPrice, nil, 600000000000},
+		{"zero start price", models.AuctionTypeDutch, decimal.Zero, &floorPrice, 600000000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onChainStartPriceUnitUSD(tt.auctionType, tt.startPrice, tt.floorPrice, 600000000000); got != tt.want {
+				t.Errorf("onChainStartPriceUnitUSD() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettlementRejectionDutch(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(100 * time.Second)
+	startPrice := decimal.NewFromInt(100)
+	floorPrice := decimal.NewFromInt(50)
+	auction := &models.Auction{
+		AuctionType:  models.AuctionTypeDutch,
+		PaymentToken: "0xtoken",
+		StartPrice:   &startPrice,
+		FloorPrice:   &floorPrice,
+		StartTime:    &start,
+		EndTime:      &end,
+	}
+	bidAt := func(amount int64, at time.Time) *models.Bid {
+		value := decimal.NewFromInt(amount)
+		return &models.Bid{PaymentToken: "0xtoken", Amount: &value, Timestamp: uint64(at.Unix())}
+	}
+
+	tests := []struct {
+		name string
+		bid  *models.Bid
+		want string
+	}{
+		{"no bid", nil, ""},
+		{"meets current price", bidAt(75, start.Add(50*time.Second)), ""},
+		{"floor price bid before decay", bidAt(50, start.Add(10*time.Second)), SettlementRejectBelowCurrentPrice},
+		{"floor price bid at end", bidAt(50, end), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlementRejection(auction, tt.bid); got != tt.want {
+				t.Errorf("settlementRejection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettlementRejectionSealed(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	commitEnd := start.Add(time.Hour)
+	revealEnd := commitEnd.Add(time.Hour)
+	end := revealEnd.Add(time.Hour)
+	clearingPrice := decimal.NewFromInt(10)
+	clearingPriceUSD := decimal.NewFromInt(30000)
+	auction := &models.Auction{
+		AuctionType:            models.AuctionTypeSealed,
+		StartTime:              &start,
+		CommitEndTime:          &commitEnd,
+		RevealEndTime:          &revealEnd,
+		EndTime:                &end,
+		SealedWinner:           "0xwinner",
+		SealedPaymentToken:     "0xtoken",
+		SealedClearingPrice:    &clearingPrice,
+		SealedClearingPriceUSD: &clearingPriceUSD,
+	}
+	settlement := revealEnd.Add(10 * time.Minute)
+	bid := func(wallet, token string, amount, amountUSD int64, at time.Time) *models.Bid {
+		value := decimal.NewFromInt(amount)
+		valueUSD := decimal.NewFromInt(amountUSD)
+		return &models.Bid{WalletAddress: wallet, PaymentToken: token, Amount: &value, AmountUSD: &valueUSD, Timestamp: uint64(at.Unix())}
+	}
+	noWinner := *auction
+	noWinner.SealedWinner = ""
+
+	tests := []struct {
+		name    string
+		auction *models.Auction
+		bid     *models.Bid
+		want    string
+	}{
+		{"winner at clearing price", auction, bid("0xwinner", "0xtoken", 10, 30000, settlement), ""},
+		{"winner pays in other token", auction, bid("0xwinner", "0xother", 1, 30000, settlement), ""},
+		{"no revealed winner", &noWinner, bid("0xwinner", "0xtoken", 10, 30000, settlement), SettlementRejectSealedNoWinner},
+		{"other bidder", auction, bid("0xother", "0xtoken", 20, 60000, settlement), SettlementRejectSealedWinnerMismatch},
+		{"bid during commit phase", auction, bid("0xwinner", "0xtoken", 10, 30000, start.Add(time.Minute)), SettlementRejectSealedOutsideSettlement},
+		{"below clearing price", auction, bid("0xwinner", "0xtoken", 9, 27000, settlement), SettlementRejectSealedBelowClearingPrice},
+		{"below clearing price in USD", auction, bid("0xwinner", "0xother", 1, 29999, settlement), SettlementRejectSealedBelowClearingPrice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlementRejection(tt.auction, tt.bid); got != tt.want {
+				t.Errorf("settlementRejection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettlementRejectionAllowlist(t *testing.T) {
+	price := decimal.NewFromInt(10)
+	listing := &models.Auction{
+		AuctionType:  models.AuctionTypeFixed,
+		PaymentToken: "0xtoken",
+		StartPrice:   &price,
+		Visibility:   models.AuctionVisibilityAllowlist,
+	}
+	public := *listing
+	public.Visibility = models.AuctionVisibilityPublic
+	bid := func(flagged bool) *models.Bid {
+		return &models.Bid{PaymentToken: "0xtoken", Amount: &price, Flagged: flagged}
+	}
+
+	tests := []struct {
+		name    string
+		auction *models.Auction
+		bid     *models.Bid
+		want    string
+	}{
+		{"allowlisted bidder", listing, bid(false), ""},
+		{"flagged bidder", listing, bid(true), SettlementRejectNotAllowlisted},
+		{"flagged bid on public listing", &public, bid(true), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlementRejection(tt.auction, tt.bid); got != tt.want {
+				t.Errorf("settlementRejection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettlementRejectionReservePrice(t *testing.T) {
+	startPrice := decimal.NewFromInt(1)
+	startPriceUSD := decimal.NewFromInt(3000)
+	reservePrice := decimal.NewFromInt(2)
+	auction := &models.Auction{
+		AuctionType:   models.AuctionTypeEnglish,
+		PaymentToken:  "0xtoken",
+		StartPrice:    &startPrice,
+		StartPriceUSD: &startPriceUSD,
+		ReservePrice:  &reservePrice,
+	}
+	noReserve := *auction
+	noReserve.ReservePrice = nil
+	bid := func(token string, amount, amountUSD int64) *models.Bid {
+		value := decimal.NewFromInt(amount)
+		valueUSD := decimal.NewFromInt(amountUSD)
+		return &models.Bid{PaymentToken: token, Amount: &value, AmountUSD: &valueUSD}
+	}
+
+	tests := []struct {
+		name    string
+		auction *models.Auction
+		bid     *models.Bid
+		want    string
+	}{
+		{"meets reserve", auction, bid("0xtoken", 2, 6000), ""},
+		{"below reserve", auction, bid("0xtoken", 1, 3000), SettlementRejectBelowReservePrice},
+		{"other token meets reserve in USD", auction, bid("0xother", 1, 6000), ""},
+		{"other token below reserve in USD", auction, bid("0xother", 1, 5999), SettlementRejectBelowReservePrice},
+		{"no reserve", &noReserve, bid("0xtoken", 1, 3000), ""},
+		{"below increment", auction, &models.Bid{PaymentToken: "0xtoken", Amount: &reservePrice, Flagged: true, FlagReason: models.BidFlagBelowBidIncrement}, SettlementRejectBelowBidIncrement},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlementRejection(tt.auction, tt.bid); got != tt.want {
+				t.Errorf("settlementRejection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBelowBidIncrement(t *testing.T) {
+	english := &models.Auction{AuctionType: models.AuctionTypeEnglish, BidIncrementPercent: 10}
+	sealed := &models.Auction{AuctionType: models.AuctionTypeSealed, BidIncrementPercent: 10}
+	noIncrement := &models.Auction{AuctionType: models.AuctionTypeEnglish}
+
+	tests := []struct {
+		name     string
+		auction  *models.Auction
+		amount   uint64
+		previous uint64
+		want     bool
+	}{
+		{"first bid", english, 100, 0, false},
+		{"meets increment", english, 110, 100, false},
+		{"below increment", english, 109, 100, true},
+		{"no increment", noIncrement, 101, 100, false},
+		{"not english", sealed, 101, 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bid := &models.Bid{AmountUnitUSD: tt.amount, MinBidUnitUSD: tt.previous}
+			if got := belowBidIncrement(tt.auction, bid); got != tt.want {
+				t.Errorf("belowBidIncrement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
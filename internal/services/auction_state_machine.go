@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/models"
+)
+
+// 拍卖状态机：所有拍卖状态变更都通过 transitionAuction 执行，统一校验合法性、处理 NFT 在线锁并记录变更历史
+//
+//	(新建) -> draft | pending
+//	draft    -> pending（提交草稿） | cancelled
+//	pending  -> upcoming | active（AuctionCreated 事件） | cancelled | expired（超时或卖家放弃）
+//	upcoming -> active（开始任务） | ended | cancelled
+//	active   -> ended（结束任务） | cancelled
+//	expired  -> pending（迟到的 AuctionCreated 事件恢复）
+
+// 状态变更的触发来源
+const (
+	TransitionCauseAPI   = "api"   // 用户调用接口
+	TransitionCauseEvent = "event" // 链上合约事件
+	TransitionCauseTask  = "task"  // 后台任务（调度器、过期扫描）
+)
+
+// TransitionActorSystem 后台任务触发的状态变更的操作者
+const TransitionActorSystem = "system"
+
+// auctionTransitions 合法的状态变更（key 为当前状态，空字符串表示新建拍卖）
+var auctionTransitions = map[string][]string{
+	"":                     {AuctionStatusDraft, AuctionStatusPending},
+	AuctionStatusDraft:     {AuctionStatusPending, AuctionStatusCancelled},
+	AuctionStatusPending:   {AuctionStatusUpcoming, AuctionStatusActive, AuctionStatusCancelled, AuctionStatusExpired},
+	AuctionStatusUpcoming:  {AuctionStatusActive, AuctionStatusEnded, AuctionStatusCancelled},
+	AuctionStatusActive:    {AuctionStatusEnded, AuctionStatusCancelled},
+	AuctionStatusExpired:   {AuctionStatusPending},
+	AuctionStatusEnded:     {},
+	AuctionStatusCancelled: {},
+}
+
+// StatusTransition 一次拍卖状态变更
+type StatusTransition struct {
+	To      string                 // 目标状态
+	Actor   string                 // 操作者（user:用户ID、链上地址或 system）
+	Cause   string                 // 触发来源（api、event、task）
+	Reason  string                 // 变更原因（如 cancel、auction_created、auction_end）
+	TxHash  string                 // 关联的链上交易哈希
+	Updates map[string]interface{} // 与状态一起更新的其他字段
+
+	KeepBundleMemberLocks bool // 打包拍卖成交结束时保持成员 NFT 的在线锁（合约只托管主 NFT，成员等待平台转给获胜者）
+}
+
+// userActor 用户操作者标识
+func userActor(userID uint64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// CanTransition 判断拍卖状态变更是否合法
+func CanTransition(from, to string) bool {
+	for _, next := range auctionTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionAuction 在事务中执行拍卖状态变更
+// 守卫：变更必须合法，并以 status = 当前状态 为条件更新，并发变更时只有一方生效（返回 false，不做任何修改）
+// 副作用：进入 pending 时加 NFT 在线锁（online_lock 为 nft_id:1，主 NFT 和打包成员写入 nft_locks）；
+// 进入 ended/cancelled/expired 时释放在线锁（online_lock 为 nft_id:auction_id，删除 nft_locks，KeepBundleMemberLocks 时只释放主 NFT）；
+// 每次变更追加一条变更记录
+func transitionAuction(tx *gorm.DB, auction *models.Auction, t StatusTransition) (bool, error) {
+	from := auction.Status
+	if !CanTransition(from, t.To) {
+		return false, errors.BadRequest(fmt.Sprintf("cannot change auction status from %s to %s", from, t.To))
+	}
+
+	updates := map[string]interface{}{}
+	for k, v := range t.Updates {
+		updates[k] = v
+	}
+	updates["status"] = t.To
+	updates["updated_at"] = time.Now()
+	switch t.To {
+	case AuctionStatusPending:
+		updates["online_lock"] = fmt.Sprintf("%s:1", auction.NFTID)
+	case AuctionStatusEnded, AuctionStatusCancelled, AuctionStatusExpired:
+		updates["online_lock"] = fmt.Sprintf("%s:%s", auction.NFTID, auction.AuctionID)
+	}
+
+	result := tx.Model(&models.Auction{}).
+		Where("auction_id = ? AND status = ?", auction.AuctionID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to change auction status from %s to %s: %w", from, t.To, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	switch t.To {
+	case AuctionStatusPending:
+		if err := lockAuctionNFTs(tx, auction); err != nil {
+			return false, err
+		}
+	case AuctionStatusEnded, AuctionStatusCancelled, AuctionStatusExpired:
+		if err := unlockAuctionNFTs(tx, auction, t.KeepBundleMemberLocks); err != nil {
+			return false, err
+		}
+	}
+
+	if err := recordTransition(tx, auction.AuctionID, from, t); err != nil {
+		return false, err
+	}
+
+	auction.Status = t.To
+	if lock, ok := updates["online_lock"].(string); ok {
+		auction.OnlineLock = lock
+	}
+	return true, nil
+}
+
+// recordAuctionCreated 记录新建拍卖的初始状态
+func recordAuctionCreated(tx *gorm.DB, auction *models.Auction, actor string, cause string, reason string) error {
+	if !CanTransition("", auction.Status) {
+		return errors.BadRequest(fmt.Sprintf("cannot create auction in status %s", auction.Status))
+	}
+	return recordTransition(tx, auction.AuctionID, "", StatusTransition{
+		To:     auction.Status,
+		Actor:  actor,
+		Cause:  cause,
+		Reason: reason,
+	})
+}
+
+// recordTransition 追加一条状态变更记录
+func recordTransition(tx *gorm.DB, auctionID string, from string, t StatusTransition) error {
+	record := models.AuctionTransition{
+		AuctionID:  auctionID,
+		FromStatus: from,
+		ToStatus:   t.To,
+		Actor:      t.Actor,
+		Cause:      t.Cause,
+		Reason:     t.Reason,
+		TxHash:     t.TxHash,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to record auction status transition: %w", err)
+	}
+	return nil
+}
+
+// GetHistory 查询拍卖的状态变更历史（按时间正序）
+func (s *AuctionService) GetHistory(auctionID string) ([]models.AuctionTransition, error) {
+	var history []models.AuctionTransition
+	if err := database.DB.Where("auction_id = ?", auctionID).Order("id ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get auction history: %w", err)
+	}
+	return history, nil
+}
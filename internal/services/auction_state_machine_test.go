@@ -0,0 +1,41 @@
+package services
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{"", AuctionStatusDraft, true},
+		{"", AuctionStatusPending, true},
+		{"", AuctionStatusActive, false},
+		{AuctionStatusDraft, AuctionStatusPending, true},
+		{AuctionStatusDraft, AuctionStatusCancelled, true},
+		{AuctionStatusDraft, AuctionStatusActive, false},
+		{AuctionStatusPending, AuctionStatusUpcoming, true},
+		{AuctionStatusPending, AuctionStatusActive, true},
+		{AuctionStatusPending, AuctionStatusExpired, true},
+		{AuctionStatusPending, AuctionStatusEnded, false},
+		{AuctionStatusUpcoming, AuctionStatusActive, true},
+		{AuctionStatusUpcoming, AuctionStatusEnded, true},
+		{AuctionStatusUpcoming, AuctionStatusPending, false},
+		{AuctionStatusActive, AuctionStatusEnded, true},
+		{AuctionStatusActive, AuctionStatusCancelled, true},
+		{AuctionStatusActive, AuctionStatusPending, false},
+		{AuctionStatusExpired, AuctionStatusPending, true},
+		{AuctionStatusExpired, AuctionStatusActive, false},
+		{AuctionStatusEnded, AuctionStatusActive, false},
+		{AuctionStatusCancelled, AuctionStatusPending, false},
+		{AuctionStatusActive, AuctionStatusActive, false},
+		{"unknown", AuctionStatusActive, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
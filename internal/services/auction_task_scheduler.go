@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"my-auction-market-api/internal/config"
 	"my-auction-market-api/internal/contracts/my_auction"
@@ -19,6 +21,7 @@ import (
 	"my-auction-market-api/internal/logger"
 	"my-auction-market-api/internal/models"
 	"my-auction-market-api/internal/redisdb"
+	"my-auction-market-api/internal/websocket"
 
 	"github.com/hibiken/asynq"
 	"gorm.io/gorm"
@@ -35,6 +38,9 @@ type AuctionTaskScheduler struct {
 	redisClient     *redisdb.Client          // Redis 客户端，用于存储取消标记
 	ethClient       *ethereum.Client         // 以太坊客户端
 	auctionContract *my_auction.MyXAuctionV2 // 拍卖合约实例
+	wsHub           *websocket.Hub           // WebSocket Hub，用于推送任务触发的消息
+	notifier        *NotificationService     // 通知服务，用于发送拍卖即将结束提醒
+	auctionService  *AuctionService          // 拍卖服务，用于流拍自动重新上架
 	cfg             *config.Config
 	mu              sync.RWMutex
 }
@@ -120,14 +126,58 @@ func (s *AuctionTaskScheduler) registerHandlers() {
 	// 注册拍卖结束任务处理器
 	// 注意：任务类型必须与 NewTask 的第一个参数一致
 	s.mux.HandleFunc("auction-end", s.handleAuctionEndTask)
+	// 注册拍卖开始任务处理器（upcoming -> active）
+	s.mux.HandleFunc(TaskTypeAuctionStart, s.handleAuctionStartTask)
+	// 注册拍卖即将结束提醒任务处理器
+	s.mux.HandleFunc(TaskTypeAuctionEndingSoon, s.handleAuctionEndingSoonTask)
+}
+
+// SetWSHub 设置 WebSocket Hub（用于推送 auction_started 等消息）
+func (s *AuctionTaskScheduler) SetWSHub(hub *websocket.Hub) {
+	s.wsHub = hub
+}
+
+// SetAuctionService 设置拍卖服务（用于流拍自动重新上架）
+func (s *AuctionTaskScheduler) SetAuctionService(auctionService *AuctionService) {
+	s.auctionService = auctionService
+}
+
+// SetNotificationService 设置通知服务（用于发送拍卖即将结束提醒）
+func (s *AuctionTaskScheduler) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+// 拍卖相关的其他延迟任务类型（拍卖结束任务为 "auction-end"）
+const (
+	TaskTypeAuctionStart    = "auction-start"     // 拍卖开始，upcoming 切换为 active
+	TaskTypeSealedRevealEnd = "sealed-reveal-end" // 密封拍卖揭示截止，确定获胜者和成交价
+
+	TaskTypeAuctionEndingSoon = "auction-ending-soon" // 拍卖即将结束提醒（按配置的提前量各调度一次）
+	TaskTypeOfferExpire       = "offer-expire"        // NFT 报价过期（任务负载的 TaskID 为报价ID）
+)
+
+// AuctionTaskHandler 按拍卖ID处理的任务处理函数
+type AuctionTaskHandler func(ctx context.Context, auctionID string) error
+
+// RegisterHandler 注册其他服务的拍卖任务处理器（必须在 Start 之前调用）
+func (s *AuctionTaskScheduler) RegisterHandler(taskType string, handler AuctionTaskHandler) {
+	s.mux.HandleFunc(taskType, func(ctx context.Context, t *asynq.Task) error {
+		var payload AuctionTaskPayload
+		if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+		logger.Info("Processing %s task: auctionID=%s", taskType, payload.TaskID)
+		return handler(ctx, payload.TaskID)
+	})
 }
 
 // AuctionTaskPayload 拍卖任务负载
 type AuctionTaskPayload struct {
-	TaskID   string `json:"task_id"`   // auction_id (不重复)
-	TaskName string `json:"task_name"` // "auctions" (可能重复)
-	UserID   uint64 `json:"user_id"`   // 用户ID
-	NFTID    string `json:"nft_id"`    // NFT ID
+	TaskID   string `json:"task_id"`          // auction_id (不重复)
+	TaskName string `json:"task_name"`        // "auctions" (可能重复)
+	UserID   uint64 `json:"user_id"`          // 用户ID
+	NFTID    string `json:"nft_id"`           // NFT ID
+	Offset   int64  `json:"offset,omitempty"` // 提醒任务距结束时间的提前量（秒）
 }
 
 // getCustomTaskID 生成自定义 TaskID（统一格式）
@@ -140,7 +190,19 @@ func getCustomTaskID(auctionID string) string {
 // endTime: 结束时间
 // userID: 用户ID
 // nftID: NFT ID
+// 结束时间变化时同时重新调度即将结束提醒
 func (s *AuctionTaskScheduler) ScheduleAuctionEndTask(auction *models.Auction) error {
+	if err := s.scheduleAuctionEndTask(auction); err != nil {
+		return err
+	}
+	if err := s.ScheduleEndingSoonReminders(auction); err != nil {
+		logger.Error("Failed to schedule ending soon reminders: auctionID=%s, error=%v", auction.AuctionID, err)
+	}
+	return nil
+}
+
+// scheduleAuctionEndTask 调度拍卖结束任务（不包含提醒任务）
+func (s *AuctionTaskScheduler) scheduleAuctionEndTask(auction *models.Auction) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	auctionID := auction.AuctionID
@@ -220,6 +282,147 @@ func (s *AuctionTaskScheduler) ScheduleAuctionEndTask(auction *models.Auction) e
 	return nil
 }
 
+// getEndingSoonTaskID 生成即将结束提醒任务的 TaskID（每个提前量一个任务）
+func getEndingSoonTaskID(auctionID string, offset time.Duration) string {
+	return fmt.Sprintf("%s:%s:%d", TaskTypeAuctionEndingSoon, auctionID, int64(offset/time.Second))
+}
+
+// ScheduleEndingSoonReminders 按配置的提前量调度拍卖即将结束提醒
+// 已存在的提醒任务会先删除；提醒时间已过的提前量直接跳过
+func (s *AuctionTaskScheduler) ScheduleEndingSoonReminders(auction *models.Auction) error {
+	if auction.EndTime == nil {
+		return fmt.Errorf("endTime is nil, cannot schedule ending soon reminders: auctionID=%s", auction.AuctionID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, offset := range s.cfg.Auction.EndingSoonReminders {
+		if offset <= 0 {
+			continue
+		}
+		customTaskID := getEndingSoonTaskID(auction.AuctionID, offset)
+		if s.inspector != nil {
+			s.inspector.DeleteTask("auctions", customTaskID)
+		}
+
+		processAt := auction.EndTime.Add(-offset)
+		if !processAt.After(now) {
+			continue
+		}
+
+		payloadBytes, err := json.Marshal(AuctionTaskPayload{
+			TaskID:   auction.AuctionID,
+			TaskName: TaskTypeAuctionEndingSoon,
+			UserID:   auction.UserID,
+			NFTID:    auction.NFTID,
+			Offset:   int64(offset / time.Second),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		task := asynq.NewTask(TaskTypeAuctionEndingSoon, payloadBytes, asynq.TaskID(customTaskID))
+		if _, err := s.client.Enqueue(task, asynq.Queue("auctions"), asynq.ProcessAt(processAt)); err != nil {
+			return fmt.Errorf("failed to enqueue ending soon reminder: %w", err)
+		}
+		logger.Info("Ending soon reminder scheduled: auctionID=%s, offset=%v, processAt=%v", auction.AuctionID, offset, processAt)
+	}
+	return nil
+}
+
+// ScheduleAuctionStartTask 调度拍卖开始任务
+func (s *AuctionTaskScheduler) ScheduleAuctionStartTask(auction *models.Auction) error {
+	if auction.StartTime == nil {
+		return fmt.Errorf("startTime is nil, cannot schedule auction start task: auctionID=%s", auction.AuctionID)
+	}
+	return s.scheduleTaskAt(TaskTypeAuctionStart, auction, *auction.StartTime)
+}
+
+// ScheduleSealedRevealEndTask 调度密封拍卖揭示截止任务
+func (s *AuctionTaskScheduler) ScheduleSealedRevealEndTask(auction *models.Auction) error {
+	if auction.RevealEndTime == nil {
+		return fmt.Errorf("revealEndTime is nil, cannot schedule sealed reveal end task: auctionID=%s", auction.AuctionID)
+	}
+	return s.scheduleTaskAt(TaskTypeSealedRevealEnd, auction, *auction.RevealEndTime)
+}
+
+// scheduleTaskAt 在指定时间调度一次性拍卖任务
+// TaskID 格式为 {taskType}:{auctionID}，重复调度时先删除旧任务
+func (s *AuctionTaskScheduler) scheduleTaskAt(taskType string, auction *models.Auction, processAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customTaskID := fmt.Sprintf("%s:%s", taskType, auction.AuctionID)
+	if s.inspector != nil {
+		if err := s.inspector.DeleteTask("auctions", customTaskID); err == nil {
+			logger.Info("Deleted existing task before rescheduling: auctionID=%s, taskID=%s", auction.AuctionID, customTaskID)
+		}
+	}
+
+	payloadBytes, err := json.Marshal(AuctionTaskPayload{
+		TaskID:   auction.AuctionID,
+		TaskName: taskType,
+		UserID:   auction.UserID,
+		NFTID:    auction.NFTID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskType, payloadBytes, asynq.TaskID(customTaskID))
+	opts := []asynq.Option{asynq.Queue("auctions")}
+	if processAt.After(time.Now()) {
+		opts = append(opts, asynq.ProcessAt(processAt))
+	}
+	if _, err := s.client.Enqueue(task, opts...); err != nil {
+		return fmt.Errorf("failed to enqueue %s task: %w", taskType, err)
+	}
+
+	logger.Info("Auction task scheduled: type=%s, auctionID=%s, processAt=%v", taskType, auction.AuctionID, processAt)
+	return nil
+}
+
+// ScheduleOfferExpireTask 在报价过期时间调度过期任务
+// TaskID 格式为 offer-expire:{offerID}；处理器由 OfferService 通过 RegisterHandler 注册
+func (s *AuctionTaskScheduler) ScheduleOfferExpireTask(offer *models.Offer) error {
+	offerID := strconv.FormatUint(offer.ID, 10)
+	payloadBytes, err := json.Marshal(AuctionTaskPayload{
+		TaskID:   offerID,
+		TaskName: TaskTypeOfferExpire,
+		UserID:   offer.BuyerUserID,
+		NFTID:    offer.NFTID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	customTaskID := fmt.Sprintf("%s:%s", TaskTypeOfferExpire, offerID)
+	task := asynq.NewTask(TaskTypeOfferExpire, payloadBytes, asynq.TaskID(customTaskID))
+	opts := []asynq.Option{asynq.Queue("auctions")}
+	if processAt := time.Unix(offer.Expiry, 0); processAt.After(time.Now()) {
+		opts = append(opts, asynq.ProcessAt(processAt))
+	}
+	if _, err := s.client.Enqueue(task, opts...); err != nil && err != asynq.ErrTaskIDConflict {
+		return fmt.Errorf("failed to enqueue offer expire task: %w", err)
+	}
+
+	logger.Info("Offer expire task scheduled: offerID=%s, expiry=%d", offerID, offer.Expiry)
+	return nil
+}
+
+// DeleteOfferExpireTask 删除报价过期任务（报价被接受、拒绝或撤回后调用）
+func (s *AuctionTaskScheduler) DeleteOfferExpireTask(offerID uint64) {
+	if s.inspector == nil {
+		return
+	}
+	customTaskID := fmt.Sprintf("%s:%d", TaskTypeOfferExpire, offerID)
+	if err := s.inspector.DeleteTask("auctions", customTaskID); err == nil {
+		logger.Info("Offer expire task deleted: offerID=%d", offerID)
+	}
+}
+
 // CancelAuctionEndTask 取消拍卖结束任务
 // 通过设置 Redis 取消标记来实现任务删除
 func (s *AuctionTaskScheduler) CancelAuctionEndTask(auctionID string) error {
@@ -281,6 +484,18 @@ func (s *AuctionTaskScheduler) DeleteTask(auctionID string) error {
 		}
 	}
 
+	// 同时删除尚未执行的拍卖开始任务（开始任务执行时也会检查状态）
+	if s.inspector != nil {
+		startTaskID := fmt.Sprintf("%s:%s", TaskTypeAuctionStart, auctionID)
+		if err := s.inspector.DeleteTask("auctions", startTaskID); err == nil {
+			logger.Info("Auction start task deleted: auctionID=%s, taskID=%s", auctionID, startTaskID)
+		}
+		// 删除即将结束提醒任务（提醒任务执行时也会检查取消标记）
+		for _, offset := range s.cfg.Auction.EndingSoonReminders {
+			s.inspector.DeleteTask("auctions", getEndingSoonTaskID(auctionID, offset))
+		}
+	}
+
 	// 方法2: 设置取消标记作为备用方案（即使任务已从队列删除，也设置标记以防万一）
 	if s.redisClient != nil {
 		cancelKey := s.getCancelKey(auctionID)
@@ -351,8 +566,8 @@ func (s *AuctionTaskScheduler) handleAuctionEndTask(ctx context.Context, t *asyn
 		return nil // 不返回错误，避免重试
 	}
 
-	// 检查拍卖状态
-	if auction.Status != "active" {
+	// 检查拍卖状态（开始任务未执行的 upcoming 拍卖同样需要结算）
+	if auction.Status != "active" && auction.Status != "upcoming" {
 		logger.Info("Auction is not active, skipping: auctionID=%s, status=%s", payload.TaskID, auction.Status)
 		return nil
 	}
@@ -382,17 +597,160 @@ func (s *AuctionTaskScheduler) handleAuctionEndTask(ctx context.Context, t *asyn
 	return nil
 }
 
+// handleAuctionStartTask 处理拍卖开始任务：upcoming -> active，并推送 auction_started
+func (s *AuctionTaskScheduler) handleAuctionStartTask(ctx context.Context, t *asynq.Task) error {
+	var payload AuctionTaskPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	logger.Info("Processing auction start task: auctionID=%s", payload.TaskID)
+
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", payload.TaskID).First(&auction).Error; err != nil {
+		logger.Warn("Auction not found for start task: auctionID=%s, error=%v", payload.TaskID, err)
+		return nil // 不返回错误，避免重试
+	}
+	if auction.Status != "upcoming" {
+		logger.Info("Auction is not upcoming, skipping start task: auctionID=%s, status=%s", payload.TaskID, auction.Status)
+		return nil
+	}
+
+	// 开始时间被修改后延后执行
+	// 当前任务仍在执行中，不能复用 auction-start:{auctionID} 这个 TaskID（会 ErrTaskIDConflict），与结束任务一样不带 TaskID 重新入队
+	if auction.StartTime != nil && auction.StartTime.After(time.Now()) {
+		logger.Warn("Auction start time not reached yet, rescheduling: auctionID=%s, startTime=%v", payload.TaskID, auction.StartTime)
+		task := asynq.NewTask(TaskTypeAuctionStart, t.Payload())
+		if _, err := s.client.Enqueue(task, asynq.Queue("auctions"), asynq.ProcessAt(*auction.StartTime)); err != nil {
+			return fmt.Errorf("failed to reschedule auction start task: %w", err) // 返回错误由 asynq 重试
+		}
+		return nil
+	}
+
+	started := false
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		changed, err := transitionAuction(tx, &auction, StatusTransition{
+			To:     AuctionStatusActive,
+			Actor:  TransitionActorSystem,
+			Cause:  TransitionCauseTask,
+			Reason: "auction_start",
+		})
+		started = changed
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to start auction: %w", err)
+	}
+	if !started {
+		return nil
+	}
+
+	if s.wsHub != nil {
+		message := websocket.NewMessage(websocket.MessageTypeAuctionStarted, map[string]interface{}{
+			"auctionId":         auction.AuctionID,
+			"auctionContractId": auction.ContractAuctionID,
+			"auctionType":       auction.AuctionType,
+			"startTime":         auction.StartTime,
+			"endTime":           auction.EndTime,
+		})
+		if err := broadcastAuctionMessage(s.wsHub, &auction, message); err != nil {
+			logger.Error("failed to broadcast auction started message: %v", err)
+		}
+	}
+
+	logger.Info("Auction started: auctionID=%s", auction.AuctionID)
+	return nil
+}
+
+// endingSoonTolerance 提醒任务允许的提前执行误差，超过则视为结束时间已变更的过期任务
+const endingSoonTolerance = 30 * time.Second
+
+// handleAuctionEndingSoonTask 处理拍卖即将结束提醒：通知出价者和关注者，并推送 auction_ending_soon 到拍卖房间
+func (s *AuctionTaskScheduler) handleAuctionEndingSoonTask(ctx context.Context, t *asynq.Task) error {
+	var payload AuctionTaskPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	// 已取消的拍卖不再提醒（取消标记由结束任务负责清除）
+	if s.isTaskCancelled(payload.TaskID) {
+		logger.Info("Auction has been cancelled, skipping ending soon reminder: auctionID=%s", payload.TaskID)
+		return nil
+	}
+
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", payload.TaskID).First(&auction).Error; err != nil {
+		logger.Warn("Auction not found for ending soon reminder: auctionID=%s, error=%v", payload.TaskID, err)
+		return nil // 不返回错误，避免重试
+	}
+	if auction.Status != "active" && auction.Status != "upcoming" {
+		logger.Info("Auction is not active, skipping ending soon reminder: auctionID=%s, status=%s", payload.TaskID, auction.Status)
+		return nil
+	}
+
+	// 结束时间变更后旧任务会被重新调度覆盖，这里再做一次兜底检查
+	now := time.Now()
+	offset := time.Duration(payload.Offset) * time.Second
+	if auction.EndTime == nil || !auction.EndTime.After(now) ||
+		now.Before(auction.EndTime.Add(-offset).Add(-endingSoonTolerance)) {
+		logger.Info("Stale ending soon reminder, skipping: auctionID=%s, offset=%v, endTime=%v", payload.TaskID, offset, auction.EndTime)
+		return nil
+	}
+
+	// 收件人：出价者 + 关注者（去重，排除卖家）
+	var userIDs []uint64
+	if err := database.DB.Raw(
+		"SELECT user_id FROM bids WHERE auction_id = ? UNION SELECT user_id FROM auction_watches WHERE auction_id = ?",
+		auction.AuctionID, auction.AuctionID).Scan(&userIDs).Error; err != nil {
+		return fmt.Errorf("failed to load reminder recipients: %w", err)
+	}
+	recipients := make([]uint64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != 0 && userID != auction.UserID {
+			recipients = append(recipients, userID)
+		}
+	}
+
+	remaining := auction.EndTime.Sub(now).Round(time.Second)
+	if s.notifier != nil && len(recipients) > 0 {
+		s.notifier.Notify(ctx, recipients, &models.Notification{
+			Type:      models.NotificationTypeAuctionEndingSoon,
+			Title:     "拍卖即将结束",
+			Content:   fmt.Sprintf("您参与或关注的拍卖将在 %v 后结束", remaining),
+			AuctionID: auction.AuctionID,
+			Data: map[string]interface{}{
+				"endTime":          auction.EndTime,
+				"remainingSeconds": int64(remaining / time.Second),
+			},
+			CreatedAt: now,
+		})
+	}
+
+	if s.wsHub != nil {
+		message := websocket.NewMessage(websocket.MessageTypeAuctionEndingSoon, map[string]interface{}{
+			"auctionId":         auction.AuctionID,
+			"auctionContractId": auction.ContractAuctionID,
+			"endTime":           auction.EndTime,
+			"remainingSeconds":  int64(remaining / time.Second),
+		})
+		if err := s.wsHub.BroadcastToRoom(fmt.Sprintf("auction:%s", auction.AuctionID), message); err != nil {
+			logger.Error("failed to broadcast auction ending soon message: %v", err)
+		}
+	}
+
+	logger.Info("Ending soon reminder sent: auctionID=%s, offset=%v, recipients=%d", auction.AuctionID, offset, len(recipients))
+	return nil
+}
+
 // processAuctionEnd 处理拍卖结束逻辑
 func (s *AuctionTaskScheduler) processAuctionEnd(auction *models.Auction) error {
 	// 更新拍卖状态为 ended（只更新 Status 字段，避免更新所有字段）
 	// 更新 online_lock 为 0，表示拍卖结束，可以被其他用户竞拍
-	return database.DB.Transaction(func(tx *gorm.DB) error {
-		onlineLock := fmt.Sprintf("%s:%s", auction.NFTID, auction.AuctionID) //相当于给现在的拍卖与NFT直接解锁了,后续其他购买的用户也可以进行竞拍
-		if err := tx.Model(auction).
-			Updates(map[string]interface{}{
-				"status":      "ended",
-				"online_lock": onlineLock}).Error; err != nil {
-			return fmt.Errorf("failed to update auction status: %w", err)
+	unsold := false
+	rejected := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		nftIDs, err := auctionNFTIDs(tx, auction)
+		if err != nil {
+			return err
 		}
 
 		// 如果有最高出价者，调用合约方法将 NFT 转移给最高出价者
@@ -418,13 +776,44 @@ func (s *AuctionTaskScheduler) processAuctionEnd(auction *models.Auction) error
 				return fmt.Errorf("failed to get auction on chain: %w", err)
 			}
 			highestBidder := strings.ToLower(auctionOnChain.HighestBidder.Hex())
+
+			// 链上最高出价不满足链下成交规则时不结算，由平台取消拍卖并退款
+			var winningBid *models.Bid
+			if auctionOnChain.HighestBidder != (common.Address{}) {
+				winningBid, err = findWinningBid(tx, auction.AuctionID, highestBidder)
+				if err != nil {
+					return err
+				}
+				if reason := settlementRejection(auction, winningBid); reason != "" {
+					rejected = true
+					return s.cancelRejectedSettlement(auction, reason)
+				}
+			}
+
 			logger.Info("Auction ended with highest bidder, should transfer NFT: auctionID=%s, contractAuctionID=%d, highestBidder=%s", auction.AuctionID, auction.ContractAuctionID, highestBidder)
-			tx, err := s.auctionContract.EndAuctionAndClaimNFT(auth, contractAuctionID)
+			settleTx, err := s.settleAuctionOnChain(auth, contractAuctionID, auction, auctionOnChain.EndTime)
 			if err != nil {
-				logger.Error("Failed to call EndAuctionAndClaimNFT: %v", err)
-				return fmt.Errorf("failed to call EndAuctionAndClaimNFT: %w", err)
+				return err
+			}
+			logger.Info("NFT transfer transaction sent: txHash=%s, auctionID=%s", settleTx.Hash().Hex(), auction.AuctionID)
+
+			// 状态机释放 online_lock（nft_id:auction_id），相当于给现在的拍卖与NFT直接解锁了，后续其他用户也可以重新上架；
+			// 打包拍卖流拍时同时释放所有成员 NFT 的在线锁；成交时合约只转移主 NFT，其余成员保持锁定直到平台转给获胜者（AuctionEnded 事件触发）
+			changed, err := transitionAuction(tx, auction, StatusTransition{
+				To:     AuctionStatusEnded,
+				Actor:  TransitionActorSystem,
+				Cause:  TransitionCauseTask,
+				Reason: "auction_end",
+				TxHash: settleTx.Hash().Hex(),
+
+				KeepBundleMemberLocks: auctionOnChain.HighestBidder != (common.Address{}),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update auction status: %w", err)
+			}
+			if !changed {
+				return fmt.Errorf("auction status has been changed concurrently: auctionID=%s", auction.AuctionID)
 			}
-			logger.Info("NFT transfer transaction sent: txHash=%s, auctionID=%s", tx.Hash().Hex(), auction.AuctionID)
 
 			if auctionOnChain.HighestBidder != common.BigToAddress(big.NewInt(0)) {
 				auctionOnChainHighestBidder := strings.ToLower(auctionOnChain.HighestBidder.Hex())
@@ -432,9 +821,9 @@ func (s *AuctionTaskScheduler) processAuctionEnd(auction *models.Auction) error
 					logger.Error("Highest bidder is not the winner: auctionID=%s, highestBidder=%s, winner-on-chain=%s", auction.AuctionID, highestBidder, auctionOnChainHighestBidder)
 					return fmt.Errorf("highest bidder is not the winner: auctionID=%s, highestBidder=%s, winner-on-chain=%s", auction.AuctionID, highestBidder, auctionOnChainHighestBidder)
 				}
-				//将nft_ownerships里面状态改为已出售
-				if err := database.DB.Model(&models.NFTOwnership{}).
-					Where("nft_id = ? and user_id = ?", auction.NFTID, auction.UserID).
+				//将nft_ownerships里面状态改为已出售（打包拍卖的全部成员一起，成员由平台签名账户转给获胜者）
+				if err := tx.Model(&models.NFTOwnership{}).
+					Where("nft_id IN ? and user_id = ?", nftIDs, auction.UserID).
 					Updates(
 						map[string]interface{}{
 							"status":        models.NFTOwnershipStatusSold,
@@ -445,9 +834,13 @@ func (s *AuctionTaskScheduler) processAuctionEnd(auction *models.Auction) error
 					logger.Error("Failed to update NFT ownership status: %v", err)
 					return fmt.Errorf("failed to update NFT ownership status: %w", err)
 				}
+				if err := markWinningBid(tx, winningBid); err != nil {
+					return err
+				}
 			} else { //没有最高出价者，则将nft_ownerships里面状态改为卖出者
-				if err := database.DB.Model(&models.NFTOwnership{}).
-					Where("nft_id = ? and user_id = ?", auction.NFTID, auction.UserID).
+				unsold = true
+				if err := tx.Model(&models.NFTOwnership{}).
+					Where("nft_id IN ? and user_id = ?", nftIDs, auction.UserID).
 					Updates(
 						map[string]interface{}{
 							"status":        models.NFTOwnershipStatusHolding,
@@ -466,6 +859,93 @@ func (s *AuctionTaskScheduler) processAuctionEnd(auction *models.Auction) error
 		logger.Info("Auction ended successfully: auctionID=%s", auction.AuctionID)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	if rejected {
+		return nil
+	}
+
+	// 流拍且卖家开启了自动重新上架
+	if unsold && auction.AutoRelist {
+		s.relistAuction(auction)
+	}
+	return nil
+}
+
+// cancelRejectedSettlement 结算校验不通过：平台调用合约 cancelAuction 取消拍卖，拍卖状态以 AuctionCancelled 事件为准
+func (s *AuctionTaskScheduler) cancelRejectedSettlement(auction *models.Auction, reason string) error {
+	if s.auctionService == nil {
+		return fmt.Errorf("auction service not available, cannot cancel rejected settlement: auctionID=%s", auction.AuctionID)
+	}
+	logger.Warn("Auction settlement rejected, cancelling: auctionID=%s, reason=%s", auction.AuctionID, reason)
+	if _, err := s.auctionService.SystemCancel(auction, reason); err != nil {
+		return fmt.Errorf("failed to cancel rejected settlement: %w", err)
+	}
+	return nil
+}
+
+// relistAuction 流拍后自动创建下一代 pending 拍卖，并通知卖家重新授权 NFT、签名链上 createAuction
+// 重新上架失败只记录日志，不影响拍卖结束
+func (s *AuctionTaskScheduler) relistAuction(auction *models.Auction) {
+	if s.auctionService == nil {
+		logger.Warn("Auction service not available, skipping relist: auctionID=%s", auction.AuctionID)
+		return
+	}
+	if auction.RelistGeneration >= uint64(s.cfg.Auction.MaxRelistGenerations) {
+		logger.Info("Max relist generations reached, skipping relist: auctionID=%s, generation=%d",
+			auction.AuctionID, auction.RelistGeneration)
+		return
+	}
+
+	relisted, err := s.auctionService.Relist(auction)
+	if err != nil {
+		logger.Error("Failed to relist auction: auctionID=%s, error=%v", auction.AuctionID, err)
+		return
+	}
+	logger.Info("Auction relisted: auctionID=%s, relistedAuctionID=%s, generation=%d",
+		auction.AuctionID, relisted.AuctionID, relisted.RelistGeneration)
+
+	if s.notifier != nil {
+		s.notifier.Notify(context.Background(), []uint64{auction.UserID}, &models.Notification{
+			Type:      models.NotificationTypeAuctionRelisted,
+			Title:     "拍卖已自动重新上架",
+			Content:   "您的拍卖无人出价，已按设置创建新的待上架拍卖，请重新授权 NFT 并签名链上 createAuction 完成上架",
+			AuctionID: relisted.AuctionID,
+			Data: map[string]interface{}{
+				"relistedFrom":     auction.AuctionID,
+				"relistGeneration": relisted.RelistGeneration,
+				"startPrice":       relisted.StartPrice,
+				"paymentToken":     relisted.PaymentToken,
+				"startTime":        relisted.StartTime,
+				"endTime":          relisted.EndTime,
+			},
+			CreatedAt: time.Now(),
+		})
+	}
+}
+
+// settleAuctionOnChain 按拍卖类型调用合约结算
+// 英式拍卖在结束时间到达后调用 endAuctionAndClaimNFT；
+// 荷兰式拍卖和固定价格出售首个达标出价即成交，链上结束时间尚未到达，需要调用 forceEndAuctionAndClaimNFT
+func (s *AuctionTaskScheduler) settleAuctionOnChain(auth *bind.TransactOpts, contractAuctionID *big.Int,
+	auction *models.Auction, chainEndTime *big.Int) (*types.Transaction, error) {
+	if auction.IsInstantSale() && chainEndTime != nil && chainEndTime.Int64() > time.Now().Unix() {
+		tx, err := s.auctionContract.ForceEndAuctionAndClaimNFT(auth, contractAuctionID)
+		if err != nil {
+			logger.Error("Failed to call ForceEndAuctionAndClaimNFT: %v", err)
+			return nil, fmt.Errorf("failed to call ForceEndAuctionAndClaimNFT: %w", err)
+		}
+		logger.Info("Instant sale settled by force end: auctionID=%s, txHash=%s", auction.AuctionID, tx.Hash().Hex())
+		return tx, nil
+	}
+
+	tx, err := s.auctionContract.EndAuctionAndClaimNFT(auth, contractAuctionID)
+	if err != nil {
+		logger.Error("Failed to call EndAuctionAndClaimNFT: %v", err)
+		return nil, fmt.Errorf("failed to call EndAuctionAndClaimNFT: %w", err)
+	}
+	return tx, nil
 }
 
 // RestoreAuctionTasks 恢复未执行的拍卖任务（系统启动时调用）
@@ -475,7 +955,7 @@ func (s *AuctionTaskScheduler) RestoreAuctionTasks() error {
 	now := time.Now()
 
 	if err := database.DB.Where("status IN ? AND end_timestamp > ? and online=1 ",
-		[]string{"active"}, now.Unix()).Find(&auctions).Error; err != nil {
+		[]string{"active", "upcoming"}, now.Unix()).Find(&auctions).Error; err != nil {
 		return fmt.Errorf("failed to load auctions: %w", err)
 	}
 
@@ -493,6 +973,43 @@ func (s *AuctionTaskScheduler) RestoreAuctionTasks() error {
 	}
 
 	logger.Info("Restored %d/%d auction end tasks", successCount, len(auctions))
+
+	// 恢复 upcoming 拍卖的开始任务（开始时间已过的会立即执行）
+	startCount := 0
+	for _, auction := range auctions {
+		if auction.Status != "upcoming" {
+			continue
+		}
+		if err := s.ScheduleAuctionStartTask(auction); err != nil {
+			logger.Error("Failed to restore start task for auction %s: %v", auction.AuctionID, err)
+			continue
+		}
+		startCount++
+	}
+	logger.Info("Restored %d auction start tasks", startCount)
+
+	// 恢复尚未确定获胜者的密封拍卖揭示截止任务
+	var sealedAuctions []*models.Auction
+	if err := database.DB.Where("auction_type = ? AND status IN ? AND online = 1 AND (sealed_winner IS NULL OR sealed_winner = '')",
+		models.AuctionTypeSealed, []string{"active", "upcoming"}).Find(&sealedAuctions).Error; err != nil {
+		return fmt.Errorf("failed to load sealed auctions: %w", err)
+	}
+	for _, auction := range sealedAuctions {
+		if err := s.ScheduleSealedRevealEndTask(auction); err != nil {
+			logger.Error("Failed to restore sealed reveal end task for auction %s: %v", auction.AuctionID, err)
+		}
+	}
+
+	// 恢复待处理报价的过期任务（已过期的会立即执行）
+	var offers []*models.Offer
+	if err := database.DB.Where("status = ?", models.OfferStatusPending).Find(&offers).Error; err != nil {
+		return fmt.Errorf("failed to load pending offers: %w", err)
+	}
+	for _, offer := range offers {
+		if err := s.ScheduleOfferExpireTask(offer); err != nil {
+			logger.Error("Failed to restore expire task for offer %d: %v", offer.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/models"
+)
+
+// 拍卖模板：保存卖家常用的拍卖参数（支付代币、起拍价、时长、荷兰式/密封拍卖参数、流拍重新上架和可见性设置）
+// 合约不支持加价幅度和保留价，模板只保存合约支持的参数（荷兰式拍卖的底价相当于保留价）
+
+// maxTemplatesPerUser 每个用户最多保存的模板数量
+const maxTemplatesPerUser = 50
+
+// ListTemplates 查询用户的拍卖模板（按创建时间倒序）
+func (s *AuctionService) ListTemplates(userID uint64) ([]models.AuctionTemplate, error) {
+	var templates []models.AuctionTemplate
+	if err := database.DB.Where("user_id = ?", userID).Order("id DESC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list auction templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate 查询属于用户的拍卖模板
+func (s *AuctionService) GetTemplate(userID uint64, templateID uint64) (*models.AuctionTemplate, error) {
+	var template models.AuctionTemplate
+	if err := database.DB.Where("id = ? AND user_id = ?", templateID, userID).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("auction template not found")
+		}
+		return nil, fmt.Errorf("failed to get auction template: %w", err)
+	}
+	return &template, nil
+}
+
+// CreateTemplate 创建拍卖模板
+func (s *AuctionService) CreateTemplate(userID uint64, payload models.AuctionTemplatePayload) (*models.AuctionTemplate, error) {
+	var count int64
+	if err := database.DB.Model(&models.AuctionTemplate{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count auction templates: %w", err)
+	}
+	if count >= maxTemplatesPerUser {
+		return nil, errors.BadRequest(fmt.Sprintf("you can save at most %d auction templates", maxTemplatesPerUser))
+	}
+
+	template := models.AuctionTemplate{UserID: userID}
+	if err := fillTemplate(&template, payload); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Create(&template).Error; err != nil {
+		return nil, fmt.Errorf("failed to create auction template: %w", err)
+	}
+	return &template, nil
+}
+
+// UpdateTemplate 整体更新拍卖模板
+func (s *AuctionService) UpdateTemplate(userID uint64, templateID uint64, payload models.AuctionTemplatePayload) (*models.AuctionTemplate, error) {
+	template, err := s.GetTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillTemplate(template, payload); err != nil {
+		return nil, err
+	}
+	if err := database.DB.Select("*").Omit("id", "user_id", "created_at", "updated_at").
+		Updates(template).Error; err != nil {
+		return nil, fmt.Errorf("failed to update auction template: %w", err)
+	}
+	return s.GetTemplate(userID, templateID)
+}
+
+// DeleteTemplate 删除拍卖模板（不影响已使用该模板创建的拍卖）
+func (s *AuctionService) DeleteTemplate(userID uint64, templateID uint64) error {
+	result := database.DB.Where("id = ? AND user_id = ?", templateID, userID).Delete(&models.AuctionTemplate{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete auction template: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound.WithMessage("auction template not found")
+	}
+	return nil
+}
+
+// fillTemplate 校验模板参数并写入模板
+// 按模板时长校验荷兰式/密封拍卖参数，保证应用模板后的拍卖参数合法
+func fillTemplate(template *models.AuctionTemplate, payload models.AuctionTemplatePayload) error {
+	auctionType := payload.AuctionType
+	if auctionType == "" {
+		auctionType = models.AuctionTypeEnglish
+	}
+	template.Name = payload.Name
+	template.AuctionType = auctionType
+	template.PaymentToken = payload.PaymentToken
+	template.StartPrice = payload.StartPrice
+	template.Duration = payload.Duration
+	template.FloorPrice = nil
+	template.PriceDecayInterval = 0
+	template.CommitDuration = 0
+	template.RevealDuration = 0
+	template.ReservePrice = nil
+	template.BidIncrementPercent = 0
+	switch auctionType {
+	case models.AuctionTypeEnglish:
+		template.ReservePrice = payload.ReservePrice
+		template.BidIncrementPercent = payload.BidIncrementPercent
+	case models.AuctionTypeDutch:
+		template.FloorPrice = payload.FloorPrice
+		template.PriceDecayInterval = payload.PriceDecayInterval
+	case models.AuctionTypeSealed:
+		template.ReservePrice = payload.ReservePrice
+		template.CommitDuration = payload.CommitDuration
+		template.RevealDuration = payload.RevealDuration
+	}
+	template.AutoRelist = payload.AutoRelist
+	template.RelistDuration = payload.RelistDuration
+	template.RelistPriceReduction = payload.RelistPriceReduction
+	template.Visibility = normalizeVisibility(payload.Visibility)
+
+	startTime := time.Now()
+	endTime := startTime.Add(time.Duration(template.Duration) * time.Second)
+	return validateAuctionTypeParams(auctionType, template.StartPrice, templateTypeParams(template, startTime),
+		startTime, endTime)
+}
+
+// templateTypeParams 按开始时间计算模板的拍卖类型参数
+func templateTypeParams(template *models.AuctionTemplate, startTime time.Time) models.AuctionTypeParams {
+	params := models.AuctionTypeParams{
+		FloorPrice:          template.FloorPrice,
+		PriceDecayInterval:  template.PriceDecayInterval,
+		ReservePrice:        template.ReservePrice,
+		BidIncrementPercent: template.BidIncrementPercent,
+	}
+	if template.CommitDuration > 0 && template.RevealDuration > 0 {
+		commitEndTime := startTime.Add(time.Duration(template.CommitDuration) * time.Second)
+		revealEndTime := commitEndTime.Add(time.Duration(template.RevealDuration) * time.Second)
+		params.CommitEndTime = &commitEndTime
+		params.RevealEndTime = &revealEndTime
+	}
+	return params
+}
+
+// applyTemplate 用模板填充创建拍卖请求中未传入的字段（请求中显式传入的字段优先）
+func (s *AuctionService) applyTemplate(userID uint64, payload *models.AuctionPayload) error {
+	template, err := s.GetTemplate(userID, payload.TemplateID)
+	if err != nil {
+		return err
+	}
+	if payload.StartTime == nil {
+		return errors.BadRequest("start time is required")
+	}
+
+	if payload.AuctionType == "" {
+		payload.AuctionType = template.AuctionType
+	}
+	if payload.PaymentToken == "" {
+		payload.PaymentToken = template.PaymentToken
+	}
+	if payload.StartPrice.IsZero() {
+		payload.StartPrice = template.StartPrice
+	}
+	if payload.EndTime == nil {
+		endTime := payload.StartTime.Add(time.Duration(template.Duration) * time.Second)
+		payload.EndTime = &endTime
+	}
+
+	// 拍卖类型与模板一致时才沿用模板的类型参数
+	if payload.AuctionType == template.AuctionType {
+		params := templateTypeParams(template, *payload.StartTime)
+		if payload.FloorPrice == nil {
+			payload.FloorPrice = params.FloorPrice
+		}
+		if payload.PriceDecayInterval == 0 {
+			payload.PriceDecayInterval = params.PriceDecayInterval
+		}
+		if payload.CommitEndTime == nil && payload.RevealEndTime == nil {
+			payload.CommitEndTime = params.CommitEndTime
+			payload.RevealEndTime = params.RevealEndTime
+		}
+		if payload.ReservePrice == nil {
+			payload.ReservePrice = params.ReservePrice
+		}
+		if payload.BidIncrementPercent == 0 {
+			payload.BidIncrementPercent = params.BidIncrementPercent
+		}
+	}
+
+	if !payload.AutoRelist && template.AutoRelist {
+		payload.AutoRelist = true
+		if payload.RelistDuration == 0 {
+			payload.RelistDuration = template.RelistDuration
+		}
+		if payload.RelistPriceReduction == 0 {
+			payload.RelistPriceReduction = template.RelistPriceReduction
+		}
+	}
+	if payload.Visibility == "" {
+		payload.Visibility = template.Visibility
+	}
+	return nil
+}
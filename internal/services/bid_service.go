@@ -77,6 +77,8 @@ func (s *BidService) ConvertBidToResponse(bid *models.Bid) models.BidResponse {
 		Timestamp:          bid.Timestamp,
 		BidCount:           bid.BidCount,
 		IsHighest:          bid.IsHighest,
+		Flagged:            bid.Flagged,
+		FlagReason:         bid.FlagReason,
 		MinBidder:          bid.MinBidder,
 		MinBidUnitUSD:      bid.MinBidUnitUSD,
 		CreatedAt:          bid.CreatedAt,
@@ -144,7 +146,8 @@ func (s *BidService) GetBidsByTransactionHash(transactionHash string, query page
 
 	// 使用 JOIN 一次性查询出价和钱包地址
 	if err := database.DB.Table("bids").
-		Select("bids.*, users.wallet_address as bidder_wallet_address").
+		// 出价钱包可能是用户的已关联钱包
+		Select("bids.*, COALESCE(NULLIF(bids.wallet_address, ''), users.wallet_address) as bidder_wallet_address").
 		Joins("LEFT JOIN users ON bids.user_id = users.id").
 		Where("bids.transaction_hash = ?", transactionHash).
 		Order("bids.created_at DESC").
@@ -191,8 +194,8 @@ func (s *BidService) OnEventBidPlaced(event *my_auction.MyXAuctionV2BidPlaced, l
 	bidder := strings.ToLower(event.Bidder.Hex())
 
 	// 根据出价者钱包地址查询用户（必须是已登录系统的用户才能出价）
-	var user models.User
-	if err := database.DB.Where("wallet_address = ?", bidder).First(&user).Error; err != nil {
+	user, err := findUserByWallet(database.DB, bidder)
+	if err != nil {
 		logger.Warn("bidder wallet address not found in system: wallet=%s, auctionId=%d", bidder, contractAuctionId)
 		return nil, fmt.Errorf("bidder wallet address %s is not registered in the system", bidder)
 	}
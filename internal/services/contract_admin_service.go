@@ -0,0 +1,624 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/contracts/my_auction"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+)
+
+const (
+	// contractActionPollInterval 合约管理交易回执轮询间隔
+	contractActionPollInterval = 5 * time.Second
+	// contractActionTimeout 合约管理交易回执最长等待时间
+	contractActionTimeout = 10 * time.Minute
+	// chainSnapshotLogIndex 直接读取链上状态（或交易回执）写入设置时使用的日志索引，
+	// 表示该区块结束时的状态，同一区块内优先于事件
+	chainSnapshotLogIndex = 4294967295
+)
+
+// ContractAdminService 拍卖合约管理服务
+// 管理员通过接口触发强制结束拍卖、暂停/恢复合约、设置手续费和价格预言机，后端使用平台私钥发送交易，
+// 每次操作记录到 contract_actions（操作者、参数、交易哈希）并跟踪回执；
+// 合约的 Paused/Unpaused、PlatformFeeUpdated、FeeTierUpdated、DynamicFeeEnabled 事件写入 contract_settings，供 GET /config 读取
+type ContractAdminService struct {
+	config    config.EthereumConfig
+	ethClient *ethclientwrapper.Client
+}
+
+func NewContractAdminService(ethCfg config.EthereumConfig, ethClient *ethclientwrapper.Client) *ContractAdminService {
+	return &ContractAdminService{
+		config:    ethCfg,
+		ethClient: ethClient,
+	}
+}
+
+// ForceEndAuction 管理员强制结束拍卖（合约 forceEndAuctionAndClaimNFT：NFT 转给最高出价者，无出价时退回卖家）
+// 拍卖状态以 AuctionForceEnded 事件为准（见 AuctionService.OnEventAuctionForceEnded）
+func (s *ContractAdminService) ForceEndAuction(adminUserID uint64, auctionID string, reason string) (*models.ContractAction, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("auction not found")
+		}
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if auction.Status != AuctionStatusUpcoming && auction.Status != AuctionStatusActive {
+		return nil, errors.BadRequest(fmt.Sprintf("cannot force end auction in status %s", auction.Status))
+	}
+	if auction.ContractAuctionID == 0 {
+		return nil, errors.BadRequest("auction is not on chain yet")
+	}
+
+	var pending int64
+	if err := database.DB.Model(&models.ContractAction{}).
+		Where("action = ? AND target = ? AND status IN ?", models.ContractActionForceEnd, auction.AuctionID,
+			[]string{models.ContractActionStatusRequested, models.ContractActionStatusSubmitted}).
+		Count(&pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to check pending force end: %w", err)
+	}
+	if pending > 0 {
+		return nil, errors.BadRequest("force end transaction already submitted")
+	}
+
+	return s.submit(adminUserID, models.ContractActionForceEnd, auction.AuctionID, reason,
+		map[string]interface{}{"contractAuctionId": auction.ContractAuctionID},
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.ForceEndAuctionAndClaimNFT(auth, new(big.Int).SetUint64(auction.ContractAuctionID))
+		})
+}
+
+// Pause 暂停合约（暂停期间无法创建拍卖和出价）
+func (s *ContractAdminService) Pause(adminUserID uint64, reason string) (*models.ContractAction, error) {
+	return s.setPaused(adminUserID, true, reason)
+}
+
+// Unpause 恢复合约
+func (s *ContractAdminService) Unpause(adminUserID uint64, reason string) (*models.ContractAction, error) {
+	return s.setPaused(adminUserID, false, reason)
+}
+
+func (s *ContractAdminService) setPaused(adminUserID uint64, paused bool, reason string) (*models.ContractAction, error) {
+	contract, err := s.contract()
+	if err != nil {
+		return nil, err
+	}
+	current, err := contract.Paused(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract paused state: %w", err)
+	}
+	if current == paused {
+		if paused {
+			return nil, errors.BadRequest("contract is already paused")
+		}
+		return nil, errors.BadRequest("contract is not paused")
+	}
+
+	if paused {
+		return s.submit(adminUserID, models.ContractActionPause, "", reason, nil,
+			func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+				return contract.Pause(auth)
+			})
+	}
+	return s.submit(adminUserID, models.ContractActionUnpause, "", reason, nil,
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.Unpause(auth)
+		})
+}
+
+// SetPlatformFee 设置平台手续费（基点）
+func (s *ContractAdminService) SetPlatformFee(adminUserID uint64, payload *models.SetPlatformFeePayload) (*models.ContractAction, error) {
+	return s.submit(adminUserID, models.ContractActionSetPlatformFee, "", payload.Reason,
+		map[string]interface{}{"fee": payload.Fee},
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetPlatformFee(auth, new(big.Int).SetUint64(payload.Fee))
+		})
+}
+
+// SetFeeTiers 整体替换动态手续费档次
+func (s *ContractAdminService) SetFeeTiers(adminUserID uint64, payload *models.SetFeeTiersPayload) (*models.ContractAction, error) {
+	thresholds := make([]*big.Int, 0, len(payload.Tiers))
+	feeRates := make([]*big.Int, 0, len(payload.Tiers))
+	for i, tier := range payload.Tiers {
+		threshold, ok := new(big.Int).SetString(tier.Threshold, 10)
+		if !ok || threshold.Sign() < 0 {
+			return nil, errors.BadRequest(fmt.Sprintf("invalid threshold for tier %d: %s", i, tier.Threshold))
+		}
+		if i > 0 && threshold.Cmp(thresholds[i-1]) <= 0 {
+			return nil, errors.BadRequest("fee tier thresholds must be strictly increasing")
+		}
+		thresholds = append(thresholds, threshold)
+		feeRates = append(feeRates, new(big.Int).SetUint64(tier.FeeRate))
+	}
+
+	return s.submit(adminUserID, models.ContractActionSetFeeTiers, "", payload.Reason,
+		map[string]interface{}{"tiers": payload.Tiers},
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetFeeTiers(auth, thresholds, feeRates)
+		})
+}
+
+// SetDynamicFee 启用或禁用动态手续费
+func (s *ContractAdminService) SetDynamicFee(adminUserID uint64, payload *models.SetDynamicFeePayload) (*models.ContractAction, error) {
+	enabled := *payload.Enabled
+	return s.submit(adminUserID, models.ContractActionSetDynamicFee, "", payload.Reason,
+		map[string]interface{}{"enabled": enabled},
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetDynamicFeeEnabled(auth, enabled)
+		})
+}
+
+// SetPriceFeeds 设置代币的 Chainlink 价格预言机（单个使用 setPriceFeed，多个使用 setPriceFeeds）
+func (s *ContractAdminService) SetPriceFeeds(adminUserID uint64, payload *models.SetPriceFeedsPayload) (*models.ContractAction, error) {
+	tokens := make([]common.Address, 0, len(payload.Feeds))
+	feeds := make([]common.Address, 0, len(payload.Feeds))
+	params := make([]models.PriceFeedPayload, 0, len(payload.Feeds))
+	for _, feed := range payload.Feeds {
+		if !common.IsHexAddress(feed.Token) {
+			return nil, errors.BadRequest(fmt.Sprintf("invalid token address: %s", feed.Token))
+		}
+		if !common.IsHexAddress(feed.PriceFeed) || common.HexToAddress(feed.PriceFeed) == (common.Address{}) {
+			return nil, errors.BadRequest(fmt.Sprintf("invalid price feed address: %s", feed.PriceFeed))
+		}
+		tokens = append(tokens, common.HexToAddress(feed.Token))
+		feeds = append(feeds, common.HexToAddress(feed.PriceFeed))
+		params = append(params, models.PriceFeedPayload{
+			Token:     strings.ToLower(feed.Token),
+			PriceFeed: strings.ToLower(feed.PriceFeed),
+		})
+	}
+
+	return s.submit(adminUserID, models.ContractActionSetPriceFeeds, "", payload.Reason,
+		map[string]interface{}{"feeds": params},
+		func(contract *my_auction.MyXAuctionV2, auth *bind.TransactOpts) (*types.Transaction, error) {
+			if len(tokens) == 1 {
+				return contract.SetPriceFeed(auth, tokens[0], feeds[0])
+			}
+			return contract.SetPriceFeeds(auth, tokens, feeds)
+		})
+}
+
+// ListActions 分页查询合约管理操作记录（可按操作类型和状态过滤）
+func (s *ContractAdminService) ListActions(action string, status string, query page.PageQuery) ([]models.ContractAction, int64, error) {
+	db := database.DB.Model(&models.ContractAction{})
+	if action != "" {
+		db = db.Where("action = ?", action)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count contract actions: %w", err)
+	}
+
+	var actions []models.ContractAction
+	if err := db.Order("id DESC").
+		Offset(query.Offset()).
+		Limit(query.Limit()).
+		Find(&actions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list contract actions: %w", err)
+	}
+	return actions, total, nil
+}
+
+// GetAction 查询合约管理操作记录
+func (s *ContractAdminService) GetAction(id uint64) (*models.ContractAction, error) {
+	var action models.ContractAction
+	if err := database.DB.First(&action, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("contract action not found")
+		}
+		return nil, fmt.Errorf("failed to get contract action: %w", err)
+	}
+	return &action, nil
+}
+
+// GetSettings 读取合约当前设置
+func (s *ContractAdminService) GetSettings() (*models.ContractSettingsResponse, error) {
+	var settings []models.ContractSetting
+	if err := database.DB.Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get contract settings: %w", err)
+	}
+
+	result := &models.ContractSettingsResponse{
+		FeeTiers:   []models.ContractFeeTier{},
+		PriceFeeds: map[string]string{},
+	}
+	for _, setting := range settings {
+		if setting.BlockNumber > result.BlockNumber {
+			result.BlockNumber = setting.BlockNumber
+		}
+		switch {
+		case setting.Key == models.ContractSettingPaused:
+			result.Paused = setting.Value == "true"
+		case setting.Key == models.ContractSettingPlatformFee:
+			result.PlatformFee = setting.Value
+		case setting.Key == models.ContractSettingDynamicFeeEnabled:
+			result.DynamicFeeEnabled = setting.Value == "true"
+		case strings.HasPrefix(setting.Key, models.ContractSettingFeeTierPrefix):
+			var tier models.ContractFeeTier
+			if err := json.Unmarshal([]byte(setting.Value), &tier); err != nil {
+				logger.Warn("invalid fee tier setting: key=%s, value=%s", setting.Key, setting.Value)
+				continue
+			}
+			result.FeeTiers = append(result.FeeTiers, tier)
+		case strings.HasPrefix(setting.Key, models.ContractSettingPriceFeedPrefix):
+			result.PriceFeeds[strings.TrimPrefix(setting.Key, models.ContractSettingPriceFeedPrefix)] = setting.Value
+		}
+	}
+	sort.Slice(result.FeeTiers, func(i, j int) bool { return result.FeeTiers[i].Index < result.FeeTiers[j].Index })
+	return result, nil
+}
+
+// SyncSettings 从链上读取合约当前设置（启动时调用，补齐监听服务未运行期间的变更）
+// 价格预言机无法从合约枚举，只保留通过接口设置的记录
+func (s *ContractAdminService) SyncSettings(ctx context.Context) error {
+	contract, err := s.contract()
+	if err != nil {
+		return err
+	}
+	blockNumber, err := s.ethClient.GetBlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)}
+
+	paused, err := contract.Paused(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get paused state: %w", err)
+	}
+	platformFee, err := contract.PlatformFee(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get platform fee: %w", err)
+	}
+	dynamicFee, err := contract.UseDynamicFee(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get dynamic fee state: %w", err)
+	}
+	tiers, err := contract.GetAllFeeTiers(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get fee tiers: %w", err)
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		values := map[string]string{
+			models.ContractSettingPaused:            strconv.FormatBool(paused),
+			models.ContractSettingPlatformFee:       platformFee.String(),
+			models.ContractSettingDynamicFeeEnabled: strconv.FormatBool(dynamicFee),
+		}
+		for i, tier := range tiers {
+			values[feeTierKey(uint64(i))] = feeTierValue(uint64(i), tier.Threshold, tier.FeeRate)
+		}
+		for key, value := range values {
+			if err := applyContractSetting(tx, key, value, blockNumber, chainSnapshotLogIndex, ""); err != nil {
+				return err
+			}
+		}
+		return trimFeeTiers(tx, len(tiers), blockNumber)
+	})
+}
+
+// OnEventPaused 处理合约 Paused/Unpaused 事件
+func (s *ContractAdminService) OnEventPaused(paused bool, blockNumber uint64, logIndex uint, txHash string) error {
+	return applyContractSetting(database.DB, models.ContractSettingPaused, strconv.FormatBool(paused), blockNumber, logIndex, txHash)
+}
+
+// OnEventPlatformFeeUpdated 处理合约 PlatformFeeUpdated 事件
+func (s *ContractAdminService) OnEventPlatformFeeUpdated(newFee *big.Int, blockNumber uint64, logIndex uint, txHash string) error {
+	return applyContractSetting(database.DB, models.ContractSettingPlatformFee, newFee.String(), blockNumber, logIndex, txHash)
+}
+
+// OnEventDynamicFeeEnabled 处理合约 DynamicFeeEnabled 事件
+func (s *ContractAdminService) OnEventDynamicFeeEnabled(enabled bool, blockNumber uint64, logIndex uint, txHash string) error {
+	return applyContractSetting(database.DB, models.ContractSettingDynamicFeeEnabled, strconv.FormatBool(enabled), blockNumber, logIndex, txHash)
+}
+
+// OnEventFeeTierUpdated 处理合约 FeeTierUpdated 事件
+func (s *ContractAdminService) OnEventFeeTierUpdated(tierIndex *big.Int, threshold *big.Int, feeRate *big.Int,
+	blockNumber uint64, logIndex uint, txHash string) error {
+	index := tierIndex.Uint64()
+	return applyContractSetting(database.DB, feeTierKey(index), feeTierValue(index, threshold, feeRate), blockNumber, logIndex, txHash)
+}
+
+// Close 关闭以太坊客户端
+func (s *ContractAdminService) Close() error {
+	if s.ethClient != nil {
+		s.ethClient.Close()
+	}
+	return nil
+}
+
+// submit 使用平台私钥发送合约管理交易，记录操作并开始跟踪回执
+// 先写入 requested 状态的操作记录再发送交易，发送成功后更新为 submitted 并记录交易哈希；
+// 发送失败时标记为 failed，保证每笔合约管理交易都有对应的操作记录
+func (s *ContractAdminService) submit(adminUserID uint64, action string, target string, reason string,
+	params map[string]interface{}, send func(*my_auction.MyXAuctionV2, *bind.TransactOpts) (*types.Transaction, error)) (*models.ContractAction, error) {
+	contract, err := s.contract()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := s.ethClient.GetAuth(context.Background(), s.config.PlatformPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction auth: %w", err)
+	}
+
+	paramsJSON := ""
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode action params: %w", err)
+		}
+		paramsJSON = string(data)
+	}
+
+	record := models.ContractAction{
+		Action:      action,
+		Target:      target,
+		Params:      paramsJSON,
+		Reason:      reason,
+		RequestedBy: adminUserID,
+		Status:      models.ContractActionStatusRequested,
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to save contract action: %w", err)
+	}
+
+	tx, err := send(contract, auth)
+	if err != nil {
+		if updateErr := database.DB.Model(&record).Updates(map[string]interface{}{
+			"status": models.ContractActionStatusFailed,
+			"error":  cancellationErrorMessage(err),
+		}).Error; updateErr != nil {
+			logger.Error("failed to mark contract action failed: actionID=%d, error=%v", record.ID, updateErr)
+		}
+		// 交易发送前会预估 gas，合约拒绝（权限、参数校验等）时在此返回
+		if strings.Contains(err.Error(), "execution reverted") {
+			return nil, errors.BadRequest(fmt.Sprintf("contract rejected %s: %v", action, err))
+		}
+		return nil, fmt.Errorf("failed to send %s transaction: %w", action, err)
+	}
+
+	// 交易已发送：记录交易哈希失败时操作停留在 requested，服务重启时标记为 failed，链上结果仍以合约事件为准
+	if err := database.DB.Model(&record).Updates(map[string]interface{}{
+		"status":  models.ContractActionStatusSubmitted,
+		"tx_hash": tx.Hash().Hex(),
+	}).Error; err != nil {
+		logger.Error("failed to record contract action transaction: actionID=%d, txHash=%s, error=%v", record.ID, tx.Hash().Hex(), err)
+		return nil, fmt.Errorf("transaction %s sent but failed to save contract action: %w", tx.Hash().Hex(), err)
+	}
+	record.Status = models.ContractActionStatusSubmitted
+	record.TxHash = tx.Hash().Hex()
+	logger.Info("contract admin transaction sent: action=%s, target=%s, adminUserID=%d, txHash=%s",
+		action, target, adminUserID, tx.Hash().Hex())
+
+	go s.trackAction(record.ID, tx.Hash())
+	return &record, nil
+}
+
+// ResumeActionTracking 服务启动时恢复跟踪已发送但尚未确认的合约管理交易（submitted 状态）；
+// 停留在 requested 状态的操作（发送过程中服务退出）无法确认交易是否发出，标记为 failed
+func (s *ContractAdminService) ResumeActionTracking() error {
+	if err := database.DB.Model(&models.ContractAction{}).
+		Where("status = ?", models.ContractActionStatusRequested).
+		Updates(map[string]interface{}{
+			"status": models.ContractActionStatusFailed,
+			"error":  "service restarted before transaction was recorded",
+		}).Error; err != nil {
+		return fmt.Errorf("failed to expire requested contract actions: %w", err)
+	}
+
+	var submitted []models.ContractAction
+	if err := database.DB.Where("status = ? AND tx_hash <> ''", models.ContractActionStatusSubmitted).
+		Find(&submitted).Error; err != nil {
+		return fmt.Errorf("failed to load submitted contract actions: %w", err)
+	}
+	for _, action := range submitted {
+		go s.trackAction(action.ID, common.HexToHash(action.TxHash))
+	}
+	if len(submitted) > 0 {
+		logger.Info("resumed tracking of %d submitted contract action(s)", len(submitted))
+	}
+	return nil
+}
+
+// trackAction 跟踪合约管理交易回执，更新操作状态；交易成功时写入无对应事件的设置
+func (s *ContractAdminService) trackAction(actionID uint64, txHash common.Hash) {
+	ctx, cancel := context.WithTimeout(context.Background(), contractActionTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(contractActionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 交易可能已被丢弃或因手续费过低长期未打包，标记为 failed 以便重新发起操作；之后交易仍被打包时，设置由合约事件写入
+			failAction(actionID, fmt.Sprintf("transaction receipt not found within %s", contractActionTimeout))
+			logger.Warn("contract action receipt not found before timeout: actionID=%d, txHash=%s", actionID, txHash.Hex())
+			return
+		case <-ticker.C:
+			receipt, err := s.ethClient.GetTransactionReceipt(ctx, txHash)
+			if err != nil {
+				if err != goethereum.NotFound {
+					logger.Warn("failed to get contract action receipt: txHash=%s, error=%v", txHash.Hex(), err)
+				}
+				continue
+			}
+			if err := s.completeAction(actionID, receipt); err != nil {
+				logger.Error("failed to complete contract action: actionID=%d, error=%v", actionID, err)
+			}
+			return
+		}
+	}
+}
+
+// failAction 将提交中的合约管理操作标记为 failed（等待回执超时）
+func failAction(actionID uint64, message string) {
+	if err := database.DB.Model(&models.ContractAction{}).
+		Where("id = ? AND status = ?", actionID, models.ContractActionStatusSubmitted).
+		Updates(map[string]interface{}{
+			"status": models.ContractActionStatusFailed,
+			"error":  message,
+		}).Error; err != nil {
+		logger.Error("failed to mark contract action failed: actionID=%d, error=%v", actionID, err)
+	}
+}
+
+// completeAction 根据交易回执更新操作状态
+func (s *ContractAdminService) completeAction(actionID uint64, receipt *types.Receipt) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var action models.ContractAction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&action, actionID).Error; err != nil {
+			return fmt.Errorf("failed to get contract action: %w", err)
+		}
+		if action.Status != models.ContractActionStatusSubmitted {
+			return nil
+		}
+
+		blockNumber := receipt.BlockNumber.Uint64()
+		updates := map[string]interface{}{
+			"status":       models.ContractActionStatusConfirmed,
+			"block_number": blockNumber,
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			updates["status"] = models.ContractActionStatusFailed
+			updates["error"] = "transaction reverted"
+			logger.Warn("contract admin transaction reverted: actionID=%d, action=%s, txHash=%s", action.ID, action.Action, action.TxHash)
+		} else {
+			if err := applyActionSettings(tx, &action, blockNumber); err != nil {
+				return err
+			}
+			logger.Info("contract admin transaction mined: actionID=%d, action=%s, txHash=%s, block=%d",
+				action.ID, action.Action, action.TxHash, blockNumber)
+		}
+		if err := tx.Model(&action).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update contract action: %w", err)
+		}
+		return nil
+	})
+}
+
+// applyActionSettings 交易成功后写入合约未发出事件的设置：
+// setPriceFeed(s) 没有事件；setFeeTiers 只对新档次发出 FeeTierUpdated，需要删除多余的旧档次
+func applyActionSettings(tx *gorm.DB, action *models.ContractAction, blockNumber uint64) error {
+	switch action.Action {
+	case models.ContractActionSetPriceFeeds:
+		var params struct {
+			Feeds []models.PriceFeedPayload `json:"feeds"`
+		}
+		if err := json.Unmarshal([]byte(action.Params), &params); err != nil {
+			return fmt.Errorf("failed to decode price feed params: %w", err)
+		}
+		for _, feed := range params.Feeds {
+			if err := applyContractSetting(tx, models.ContractSettingPriceFeedPrefix+feed.Token, feed.PriceFeed,
+				blockNumber, chainSnapshotLogIndex, action.TxHash); err != nil {
+				return err
+			}
+		}
+	case models.ContractActionSetFeeTiers:
+		var params struct {
+			Tiers []models.FeeTierPayload `json:"tiers"`
+		}
+		if err := json.Unmarshal([]byte(action.Params), &params); err != nil {
+			return fmt.Errorf("failed to decode fee tier params: %w", err)
+		}
+		return trimFeeTiers(tx, len(params.Tiers), blockNumber)
+	}
+	return nil
+}
+
+// applyContractSetting 写入合约设置：只有来源（区块号、日志索引）比现有记录更新时才覆盖
+func applyContractSetting(db *gorm.DB, key string, value string, blockNumber uint64, logIndex uint, txHash string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing models.ContractSetting
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("setting_key = ?", key).First(&existing).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get contract setting %s: %w", key, err)
+		}
+		if err == nil && (existing.BlockNumber > blockNumber ||
+			(existing.BlockNumber == blockNumber && existing.LogIndex >= logIndex)) {
+			return nil
+		}
+
+		setting := models.ContractSetting{
+			Key:         key,
+			Value:       value,
+			BlockNumber: blockNumber,
+			LogIndex:    logIndex,
+			TxHash:      txHash,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "setting_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "block_number", "log_index", "tx_hash", "updated_at"}),
+		}).Create(&setting).Error; err != nil {
+			return fmt.Errorf("failed to save contract setting %s: %w", key, err)
+		}
+		logger.Info("contract setting updated: key=%s, value=%s, block=%d", key, value, blockNumber)
+		return nil
+	})
+}
+
+// trimFeeTiers 删除索引超出当前档次数量、且早于指定区块写入的手续费档次
+func trimFeeTiers(tx *gorm.DB, count int, blockNumber uint64) error {
+	var tiers []models.ContractSetting
+	if err := tx.Where("setting_key LIKE ? AND block_number <= ?", models.ContractSettingFeeTierPrefix+"%", blockNumber).
+		Find(&tiers).Error; err != nil {
+		return fmt.Errorf("failed to get fee tier settings: %w", err)
+	}
+	for _, tier := range tiers {
+		index, err := strconv.Atoi(strings.TrimPrefix(tier.Key, models.ContractSettingFeeTierPrefix))
+		if err != nil || index < count {
+			continue
+		}
+		if err := tx.Where("setting_key = ?", tier.Key).Delete(&models.ContractSetting{}).Error; err != nil {
+			return fmt.Errorf("failed to delete fee tier setting %s: %w", tier.Key, err)
+		}
+	}
+	return nil
+}
+
+func feeTierKey(index uint64) string {
+	return fmt.Sprintf("%s%d", models.ContractSettingFeeTierPrefix, index)
+}
+
+func feeTierValue(index uint64, threshold *big.Int, feeRate *big.Int) string {
+	data, _ := json.Marshal(models.ContractFeeTier{
+		Index:     index,
+		Threshold: threshold.String(),
+		FeeRate:   feeRate.String(),
+	})
+	return string(data)
+}
+
+func (s *ContractAdminService) contract() (*my_auction.MyXAuctionV2, error) {
+	if s.config.AuctionContractAddress == "" {
+		return nil, errors.BadRequest("auction contract address is not configured")
+	}
+	contract, err := my_auction.NewMyXAuctionV2(common.HexToAddress(s.config.AuctionContractAddress), s.ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auction contract instance: %w", err)
+	}
+	return contract, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/websocket"
+)
+
+// DutchPriceTicker 荷兰式拍卖价格推送器
+// 定时计算所有进行中的荷兰式拍卖的当前价格，并推送到各自的拍卖房间（auction:{auctionID}）
+type DutchPriceTicker struct {
+	wsHub    *websocket.Hub
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+// NewDutchPriceTicker 创建荷兰式拍卖价格推送器
+func NewDutchPriceTicker(wsHub *websocket.Hub, interval time.Duration) *DutchPriceTicker {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &DutchPriceTicker{
+		wsHub:    wsHub,
+		interval: interval,
+	}
+}
+
+// Start 启动价格推送（重复调用无副作用）
+func (t *DutchPriceTicker) Start(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cancel != nil {
+		return
+	}
+	tickerCtx, cancel := context.WithCancel(ctx)
+	t.cancel = cancel
+
+	t.wg.Add(1)
+	go t.run(tickerCtx)
+	logger.Info("dutch auction price ticker started (interval: %v)", t.interval)
+}
+
+// Stop 停止价格推送
+func (t *DutchPriceTicker) Stop() {
+	t.mu.Lock()
+	cancel := t.cancel
+	t.cancel = nil
+	t.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		t.wg.Wait()
+		logger.Info("dutch auction price ticker stopped")
+	}
+}
+
+func (t *DutchPriceTicker) run(ctx context.Context) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.broadcastPrices(); err != nil {
+				logger.Warn("failed to broadcast dutch auction prices: %v", err)
+			}
+		}
+	}
+}
+
+// broadcastPrices 推送所有进行中的荷兰式拍卖的当前价格
+func (t *DutchPriceTicker) broadcastPrices() error {
+	if t.wsHub == nil {
+		return nil
+	}
+
+	now := time.Now()
+	var auctions []models.Auction
+	if err := database.DB.
+		Where("auction_type = ? AND status = ? AND online = ? AND start_timestamp <= ? AND end_timestamp > ?",
+			models.AuctionTypeDutch, AuctionStatusActive, 1, now.Unix(), now.Unix()).
+		Find(&auctions).Error; err != nil {
+		return fmt.Errorf("failed to load dutch auctions: %w", err)
+	}
+
+	for i := range auctions {
+		auction := &auctions[i]
+		tick := models.AuctionPriceTick{
+			AuctionID:       auction.AuctionID,
+			PaymentToken:    auction.PaymentToken,
+			CurrentPrice:    auction.CurrentPrice(now),
+			CurrentPriceUSD: auction.CurrentPriceUSD(now),
+			NextPriceDropAt: auction.NextPriceDropAt(now),
+		}
+		if auction.FloorPrice != nil {
+			tick.FloorPrice = *auction.FloorPrice
+		}
+
+		roomID := fmt.Sprintf("auction:%s", auction.AuctionID)
+		message := websocket.NewMessage(websocket.MessageTypeAuctionPriceTick, tick)
+		if err := t.wsHub.BroadcastToRoom(roomID, message); err != nil {
+			logger.Error("failed to broadcast price tick to room %s: %v", roomID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/mail"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/redisdb"
+)
+
+// emailVerifyCooldownKeyPrefix Redis 中验证邮件发送冷却的键前缀
+const emailVerifyCooldownKeyPrefix = "auth:email:verify:cooldown:"
+
+// emailVerificationClaims 验证令牌内容，令牌绑定用户和邮箱，邮箱修改后旧令牌失效
+type emailVerificationClaims struct {
+	UserID    uint64 `json:"uid"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// EmailVerificationService 邮箱验证：签发带签名和有效期的验证令牌，发送验证邮件，验证后标记邮箱已验证
+// 令牌格式为 base64url(内容).base64url(HMAC-SHA256 签名)，不落库
+type EmailVerificationService struct {
+	cfg    config.EmailConfig
+	redis  *redisdb.Client
+	sender mail.Sender
+}
+
+func NewEmailVerificationService(cfg config.EmailConfig, redisClient *redisdb.Client, sender mail.Sender) *EmailVerificationService {
+	return &EmailVerificationService{
+		cfg:    cfg,
+		redis:  redisClient,
+		sender: sender,
+	}
+}
+
+func emailVerifyCooldownKey(userID uint64) string {
+	return emailVerifyCooldownKeyPrefix + strconv.FormatUint(userID, 10)
+}
+
+// issueToken 签发验证令牌
+func (s *EmailVerificationService) issueToken(userID uint64, email string, now time.Time) (string, error) {
+	payload, err := json.Marshal(emailVerificationClaims{
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: now.Add(s.cfg.TokenTTL).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode verification token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// parseToken 校验签名和有效期并解析令牌
+func (s *EmailVerificationService) parseToken(token string, now time.Time) (*emailVerificationClaims, error) {
+	invalid := errors.BadRequest("invalid or expired verification token")
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(encoded)) {
+		return nil, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	var claims emailVerificationClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, invalid
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, invalid
+	}
+	return &claims, nil
+}
+
+func (s *EmailVerificationService) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.TokenSecret))
+	mac.Write([]byte("email-verification:" + encoded))
+	return mac.Sum(nil)
+}
+
+// verifyLink 验证邮件中的链接（verify_url 附加 token 查询参数）
+func (s *EmailVerificationService) verifyLink(token string) (string, error) {
+	u, err := url.Parse(s.cfg.VerifyURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid email verify URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// SendVerification 向待验证的邮箱（新邮箱，或未验证的当前邮箱）发送验证邮件，没有时不发送，并开始重新发送的冷却
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *models.User) error {
+	email := user.EmailToVerify()
+	if email == "" {
+		return nil
+	}
+
+	token, err := s.issueToken(user.ID, email, time.Now())
+	if err != nil {
+		return err
+	}
+	link, err := s.verifyLink(token)
+	if err != nil {
+		return err
+	}
+
+	message := &mail.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm this email address for your My Auction Market account by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. If you did not add this address to an account, you can ignore this email.\n",
+			user.Username, link, s.cfg.TokenTTL),
+	}
+	if err := s.sender.Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	if err := s.redis.Set(ctx, emailVerifyCooldownKey(user.ID), 1, s.cfg.ResendInterval).Err(); err != nil {
+		logger.Warn("failed to set email verification cooldown: userID=%d, error=%v", user.ID, err)
+	}
+	logger.Info("verification email sent: userID=%d, email=%s", user.ID, email)
+	return nil
+}
+
+// ResendVerification 用户重新请求验证邮件，两次请求间隔不少于 email.resend_interval
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, userID uint64) error {
+	var user models.User
+	if err := database.DB.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.EmailToVerify() == "" {
+		if user.EmailAddress() == "" {
+			return errors.BadRequest("no email address to verify")
+		}
+		return errors.BadRequest("email address is already verified")
+	}
+
+	ttl, err := s.redis.TTL(ctx, emailVerifyCooldownKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check email verification cooldown: %w", err)
+	}
+	if ttl > 0 {
+		return errors.NewAppError("TOO_MANY_REQUESTS",
+			fmt.Sprintf("please wait %d seconds before requesting another verification email", int(ttl.Seconds())+1),
+			http.StatusTooManyRequests)
+	}
+
+	return s.SendVerification(ctx, &user)
+}
+
+// VerifyEmail 校验验证令牌：令牌中的邮箱是待验证的新邮箱时替换当前邮箱并标记已验证，
+// 是未验证的当前邮箱时标记已验证；邮箱已不在账户上时返回错误
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) (*models.UserProfile, error) {
+	claims, err := s.parseToken(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := database.DB.WithContext(ctx).
+		Where("id = ? AND status != ?", claims.UserID, models.UserStatusDeleted).
+		First(&user).Error; err != nil {
+		return nil, errors.BadRequest("invalid or expired verification token")
+	}
+	staleLink := errors.BadRequest("verification link is for an email address that is no longer on the account")
+
+	switch claims.Email {
+	case user.EmailToVerify():
+		// 条件更新，避免验证期间邮箱被修改
+		query := database.DB.WithContext(ctx).Model(&models.User{})
+		updates := map[string]interface{}{"email_verified": true}
+		if claims.Email == user.PendingEmailAddress() {
+			// 待验证期间地址可能已被其他账户验证
+			if err := checkEmailAvailable(user.ID, claims.Email); err != nil {
+				return nil, err
+			}
+			query = query.Where("id = ? AND pending_email = ?", user.ID, claims.Email)
+			updates["email"] = claims.Email
+			updates["pending_email"] = nil
+		} else {
+			query = query.Where("id = ? AND email = ? AND pending_email IS NULL", user.ID, claims.Email)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to verify email: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil, staleLink
+		}
+		user.Email = &claims.Email
+		user.EmailVerified = true
+		user.PendingEmail = nil
+		logger.Info("email verified: userID=%d, email=%s", user.ID, claims.Email)
+	case user.EmailAddress():
+		// 已验证（重复打开链接）
+	default:
+		return nil, staleLink
+	}
+
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"my-auction-market-api/internal/config"
+)
+
+func TestEmailVerificationToken(t *testing.T) {
+	s := &EmailVerificationService{cfg: config.EmailConfig{TokenSecret: "test-secret", TokenTTL: time.Hour}}
+	other := &EmailVerificationService{cfg: config.EmailConfig{TokenSecret: "other-secret", TokenTTL: time.Hour}}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	token, err := s.issueToken(42, "alice@example.com", now)
+	if err != nil {
+		t.Fatalf("issueToken() error = %v", err)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"uid":1,"email":"alice@example.com","exp":9999999999}`))
+
+	tests := []struct {
+		name    string
+		service *EmailVerificationService
+		token   string
+		at      time.Time
+		wantErr bool
+	}{
+		{"valid", s, token, now, false},
+		{"valid just before expiry", s, token, now.Add(time.Hour - time.Second), false},
+		{"expired", s, token, now.Add(time.Hour), true},
+		{"wrong secret", other, token, now, true},
+		{"forged payload", s, forged + "." + signature, now, true},
+		{"tampered signature", s, encoded + "." + base64.RawURLEncoding.EncodeToString([]byte("bad")), now, true},
+		{"missing signature", s, encoded, now, true},
+		{"invalid base64", s, "!!!." + signature, now, true},
+		{"empty", s, "", now, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.service.parseToken(tt.token, tt.at)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseToken() = %+v, want error", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseToken() error = %v", err)
+			}
+			if claims.UserID != 42 || claims.Email != "alice@example.com" || claims.ExpiresAt != now.Add(time.Hour).Unix() {
+				t.Errorf("parseToken() = %+v, want user 42, alice@example.com, expiring at %d", claims, now.Add(time.Hour).Unix())
+			}
+		})
+	}
+}
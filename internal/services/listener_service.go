@@ -1060,6 +1060,17 @@ func (s *ListenerService) handleAuctionBidPlaced(event *my_auction.MyXAuctionV2B
 		logger.Error("failed to process bid placed event: %v", err)
 		return err
 	}
+	// 邀请制拍卖：合约无法限制出价者，标记非白名单钱包的出价
+	if bid != nil {
+		if _, err := s.serviceManager.AuctionService.FlagBidIfNotAllowlisted(bid); err != nil {
+			logger.Error("failed to check bid against allowlist: auctionID=%s, error=%v", bid.AuctionID, err)
+		}
+		// 英式拍卖：合约不校验加价幅度，标记未达到最小加价幅度的出价
+		if _, err := s.serviceManager.AuctionService.FlagBidBelowIncrement(bid); err != nil {
+			logger.Error("failed to check bid increment: auctionID=%s, error=%v", bid.AuctionID, err)
+		}
+	}
+
 	// 向前端推送消息 - 只推送给订阅了该拍卖的客户端
 	if s.wsHub != nil && bid != nil {
 		// 使用 BidService 的转换方法，统一数据格式
@@ -1075,6 +1086,17 @@ func (s *ListenerService) handleAuctionBidPlaced(event *my_auction.MyXAuctionV2B
 		}
 	}
 
+	// 荷兰式拍卖和固定价格出售：首个达到当前价格的出价直接成交
+	if bid != nil {
+		if _, err := s.serviceManager.AuctionService.OnInstantSaleBidPlaced(bid); err != nil {
+			logger.Error("failed to process instant sale bid: auctionID=%s, error=%v", bid.AuctionID, err)
+		}
+		// 密封拍卖：校验结算阶段的链上出价
+		if err := s.serviceManager.SealedBidService.OnSealedBidPlaced(bid); err != nil {
+			logger.Error("failed to process sealed auction bid: auctionID=%s, error=%v", bid.AuctionID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -1089,7 +1111,7 @@ func (s *ListenerService) handleAuctionCreated(event *my_auction.MyXAuctionV2Auc
 	nftAddress := strings.ToLower(event.NftAddress.Hex())
 	ownerAddress := strings.ToLower(event.Creator.Hex())
 	tokenId := event.TokenId.Uint64()
-	s.serviceManager.AuctionService.OnEventAuctionCreated(auctionContractId, ownerAddress, nftAddress, tokenId)
+	s.serviceManager.AuctionService.OnEventAuctionCreated(auctionContractId, ownerAddress, nftAddress, tokenId, log.TxHash.Hex())
 	// 向前端推送消息
 	if s.wsHub != nil {
 		message := websocket.NewMessage(websocket.MessageTypeAuctionCreated, map[string]interface{}{
@@ -1098,7 +1120,7 @@ func (s *ListenerService) handleAuctionCreated(event *my_auction.MyXAuctionV2Auc
 			"nftAddress":        nftAddress,
 			"tokenId":           tokenId,
 		})
-		s.wsHub.BroadcastMessage(message)
+		s.broadcastAuctionMessage(auctionContractId, message)
 	}
 
 	return nil
@@ -1118,6 +1140,13 @@ func (s *ListenerService) handleAuctionEnded(event *my_auction.MyXAuctionV2Aucti
 	paymentToken := strings.ToLower(event.PaymentToken.Hex())
 	bidValue := event.BidValue.Uint64()
 	minBidValue := event.MinBidValue.Uint64()
+
+	// 打包拍卖成交：平台把主 NFT 以外的成员转给获胜者
+	if err := s.serviceManager.AuctionService.OnEventAuctionEnded(contractAuctionId, event.Winner); err != nil {
+		logger.Error("failed to process auction ended event: %v", err)
+		return err
+	}
+
 	// 向前端推送消息
 	if s.wsHub != nil {
 		auction, err := s.serviceManager.AuctionService.GetByContractID(contractAuctionId)
@@ -1141,7 +1170,9 @@ func (s *ListenerService) handleAuctionEnded(event *my_auction.MyXAuctionV2Aucti
 				"nftName":      auction.NftName,
 				"nftId":        auction.NFTID,
 			})
-			s.wsHub.BroadcastMessage(message)
+			if err := broadcastAuctionMessage(s.wsHub, auction, message); err != nil {
+				logger.Error("failed to broadcast auction ended message: %v", err)
+			}
 		} else {
 
 			usdValue, err := s.serviceManager.AuctionService.ConvertToUSDFromTokenUnit(paymentToken, big.NewInt(int64(finalBid)))
@@ -1162,7 +1193,9 @@ func (s *ListenerService) handleAuctionEnded(event *my_auction.MyXAuctionV2Aucti
 				"nftName":      auction.NftName,
 				"nftId":        auction.NFTID,
 			})
-			s.wsHub.BroadcastMessage(message)
+			if err := broadcastAuctionMessage(s.wsHub, auction, message); err != nil {
+				logger.Error("failed to broadcast auction ended message: %v", err)
+			}
 		}
 
 	}
@@ -1182,7 +1215,7 @@ func (s *ListenerService) handleAuctionCancelled(event *my_auction.MyXAuctionV2A
 	paymentToken := strings.ToLower(event.PaymentToken.Hex())
 	refundAmount := event.RefundAmount.Uint64()
 	refundAmountValue := event.RefundAmountValue.Uint64()
-	auctionId, err := s.serviceManager.AuctionService.OnEventAuctionCancelled(auctionContractId, cancelledBy, bidder, paymentToken, refundAmount, refundAmountValue)
+	auctionId, err := s.serviceManager.AuctionService.OnEventAuctionCancelled(auctionContractId, cancelledBy, bidder, paymentToken, refundAmount, refundAmountValue, log.TxHash.Hex())
 	if err != nil {
 		logger.Error("failed to process auction cancelled event: %v", err)
 		return err
@@ -1208,7 +1241,7 @@ func (s *ListenerService) handleAuctionCancelled(event *my_auction.MyXAuctionV2A
 			"refundAmount":      refundAmount,
 			"refundAmountValue": refundAmountValue,
 		})
-		s.wsHub.BroadcastMessage(message)
+		s.broadcastAuctionMessage(auctionContractId, message)
 	}
 
 	return nil
@@ -1218,7 +1251,20 @@ func (s *ListenerService) handleAuctionCancelled(event *my_auction.MyXAuctionV2A
 func (s *ListenerService) handleAuctionForceEnded(event *my_auction.MyXAuctionV2AuctionForceEnded, log *types.Log) error {
 	logger.Info("Auction ForceEnded event: auctionId=%d, endedBy=%s, block=%d, tx=%s",
 		event.AuctionId, event.EndedBy.Hex(), log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现强制结束拍卖事件处理逻辑
+
+	auctionId, err := s.serviceManager.AuctionService.OnEventAuctionForceEnded(event.AuctionId.Uint64(),
+		strings.ToLower(event.EndedBy.Hex()), log.TxHash.Hex())
+	if err != nil {
+		logger.Error("failed to process auction force ended event: %v", err)
+		return err
+	}
+
+	// 删除调度器上的结束任务
+	if s.serviceManager.AuctionTaskScheduler != nil && auctionId != "" {
+		if err := s.serviceManager.AuctionTaskScheduler.CancelAuctionEndTask(auctionId); err != nil {
+			logger.Error("failed to cancel auction end task for auction %s: %v", auctionId, err)
+		}
+	}
 
 	// 向前端推送消息
 	if s.wsHub != nil {
@@ -1226,50 +1272,59 @@ func (s *ListenerService) handleAuctionForceEnded(event *my_auction.MyXAuctionV2
 			"auctionId": event.AuctionId.String(),
 			"endedBy":   event.EndedBy.Hex(),
 		})
-		s.wsHub.BroadcastMessage(message)
+		s.broadcastAuctionMessage(event.AuctionId.Uint64(), message)
 	}
 
 	return nil
 }
 
+// broadcastAuctionMessage 推送合约拍卖的全局消息（邀请制拍卖只推送给卖家和白名单成员）
+func (s *ListenerService) broadcastAuctionMessage(contractAuctionID uint64, message interface{}) {
+	auction, err := s.serviceManager.AuctionService.GetByContractID(contractAuctionID)
+	if err != nil {
+		// 拍卖不存在时无法判断可见性，不推送
+		logger.Error("failed to get auction for broadcast: contractAuctionID=%d, error=%v", contractAuctionID, err)
+		return
+	}
+	if err := broadcastAuctionMessage(s.wsHub, auction, message); err != nil {
+		logger.Error("failed to broadcast auction message: contractAuctionID=%d, error=%v", contractAuctionID, err)
+	}
+}
+
 // handleAuctionPlatformFeeUpdated 处理平台手续费更新事件
 func (s *ListenerService) handleAuctionPlatformFeeUpdated(event *my_auction.MyXAuctionV2PlatformFeeUpdated, log *types.Log) error {
 	logger.Info("Auction PlatformFeeUpdated event: oldFee=%s, newFee=%s, block=%d, tx=%s",
 		event.OldFee.String(), event.NewFee.String(), log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现平台手续费更新事件处理逻辑
-	return nil
+	return s.serviceManager.ContractAdminService.OnEventPlatformFeeUpdated(event.NewFee, log.BlockNumber, log.Index, log.TxHash.Hex())
 }
 
 // handleAuctionFeeTierUpdated 处理手续费档次更新事件
 func (s *ListenerService) handleAuctionFeeTierUpdated(event *my_auction.MyXAuctionV2FeeTierUpdated, log *types.Log) error {
 	logger.Info("Auction FeeTierUpdated event: tierIndex=%s, threshold=%s, feeRate=%s, block=%d, tx=%s",
 		event.TierIndex.String(), event.Threshold.String(), event.FeeRate.String(), log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现手续费档次更新事件处理逻辑
-	return nil
+	return s.serviceManager.ContractAdminService.OnEventFeeTierUpdated(event.TierIndex, event.Threshold, event.FeeRate,
+		log.BlockNumber, log.Index, log.TxHash.Hex())
 }
 
 // handleAuctionDynamicFeeEnabled 处理动态手续费启用/禁用事件
 func (s *ListenerService) handleAuctionDynamicFeeEnabled(event *my_auction.MyXAuctionV2DynamicFeeEnabled, log *types.Log) error {
 	logger.Info("Auction DynamicFeeEnabled event: enabled=%v, block=%d, tx=%s",
 		event.Enabled, log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现动态手续费启用/禁用事件处理逻辑
-	return nil
+	return s.serviceManager.ContractAdminService.OnEventDynamicFeeEnabled(event.Enabled, log.BlockNumber, log.Index, log.TxHash.Hex())
 }
 
 // handleAuctionPaused 处理合约暂停事件
 func (s *ListenerService) handleAuctionPaused(event *my_auction.MyXAuctionV2Paused, log *types.Log) error {
 	logger.Info("Auction Paused event: account=%s, block=%d, tx=%s",
 		event.Account.Hex(), log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现合约暂停事件处理逻辑
-	return nil
+	return s.serviceManager.ContractAdminService.OnEventPaused(true, log.BlockNumber, log.Index, log.TxHash.Hex())
 }
 
 // handleAuctionUnpaused 处理合约取消暂停事件
 func (s *ListenerService) handleAuctionUnpaused(event *my_auction.MyXAuctionV2Unpaused, log *types.Log) error {
 	logger.Info("Auction Unpaused event: account=%s, block=%d, tx=%s",
 		event.Account.Hex(), log.BlockNumber, log.TxHash.Hex())
-	// TODO: 实现合约取消暂停事件处理逻辑
-	return nil
+	return s.serviceManager.ContractAdminService.OnEventPaused(false, log.BlockNumber, log.Index, log.TxHash.Hex())
 }
 
 // handleAuctionTotalValueLockedUpdated 处理TVL更新事件
@@ -6,7 +6,10 @@ import (
 
 	"my-auction-market-api/internal/config"
 	ethclientwrapper "my-auction-market-api/internal/ethereum"
+	appJWT "my-auction-market-api/internal/jwt"
 	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/mail"
+	"my-auction-market-api/internal/redisdb"
 	"my-auction-market-api/internal/websocket"
 )
 
@@ -15,11 +18,21 @@ import (
 type ServiceManager struct {
 	AuctionService       *AuctionService
 	BidService           *BidService
+	SealedBidService     *SealedBidService
+	OfferService         *OfferService
 	NFTService           *NFTService
 	UserService          *UserService
+	TokenService         *TokenService
+	APIKeyService        *APIKeyService
+	EmailVerification    *EmailVerificationService
 	ListenerService      *ListenerService
 	AuctionTaskScheduler *AuctionTaskScheduler
+	DutchPriceTicker     *DutchPriceTicker
+	PendingSweeper       *PendingAuctionSweeper
+	ContractAdminService *ContractAdminService
+	NotificationService  *NotificationService
 	WSHub                *websocket.Hub
+	Redis                *redisdb.Client
 }
 
 // NewServiceManager 创建服务管理器并初始化所有服务
@@ -31,11 +44,49 @@ func NewServiceManager(cfg config.Config) (*ServiceManager, error) {
 	go manager.WSHub.Run()
 	logger.Info("websocket hub initialized and running")
 
-	// 初始化用户服务（不需要外部依赖）
-	manager.UserService = NewUserService()
+	// 初始化 Redis 客户端（SIWE 登录 nonce、刷新令牌和会话、API Key 限流）
+	redisClient, err := redisdb.NewClient(cfg.Redis.ToRedisConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize redis client: %w", err)
+	}
+	manager.Redis = redisClient
+
+	// 初始化用户服务（需要以太坊客户端验证合约钱包签名）
+	userEthClient, err := ethclientwrapper.NewClient(cfg.Ethereum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum client for user service: %w", err)
+	}
+	manager.UserService = NewUserService(cfg.SIWE, cfg.Ethereum.ChainID, manager.Redis, userEthClient)
+	// 初始化令牌服务（刷新令牌和会话存储在 Redis），并用于 JWT 撤销检查
+	manager.TokenService = NewTokenService(cfg.JWT, manager.Redis)
+	appJWT.SetRevocationChecker(manager.TokenService)
+	// 初始化 API Key 服务
+	manager.APIKeyService = NewAPIKeyService(cfg.APIKey)
+
+	// 初始化邮件发送器（未配置 SMTP 时只记录日志）和邮箱验证服务
+	mailSender, err := mail.NewSender(cfg.Email.SMTP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mail sender: %w", err)
+	}
+	manager.EmailVerification = NewEmailVerificationService(cfg.Email, manager.Redis, mailSender)
+	manager.UserService.SetEmailVerificationService(manager.EmailVerification)
+
+	// 初始化管理员：admin.wallets 配置中的钱包对应的用户设为管理员
+	if err := manager.UserService.SeedAdmins(cfg.Admin.Wallets); err != nil {
+		return nil, fmt.Errorf("failed to seed admins: %w", err)
+	}
 
 	// 初始化拍卖任务调度器（需要 Redis）
 	manager.AuctionTaskScheduler = GetAuctionTaskScheduler(&cfg)
+	manager.AuctionTaskScheduler.SetWSHub(manager.WSHub)
+
+	// 初始化通知服务（默认通过 WebSocket 推送），用于拍卖即将结束提醒
+	manager.NotificationService = NewNotificationService(NewWebSocketNotificationChannel(manager.WSHub))
+	// 邮件通知只发送到已验证的邮箱
+	if cfg.Email.Notifications {
+		manager.NotificationService.RegisterChannel(NewEmailNotificationChannel(mailSender))
+	}
+	manager.AuctionTaskScheduler.SetNotificationService(manager.NotificationService)
 
 	// 初始化拍卖服务（需要以太坊客户端）
 	auctionService, err := NewAuctionService(cfg.Ethereum)
@@ -54,6 +105,41 @@ func NewServiceManager(cfg config.Config) (*ServiceManager, error) {
 
 	// 将任务调度器传递给拍卖服务
 	manager.AuctionService.SetTaskScheduler(manager.AuctionTaskScheduler)
+	manager.AuctionService.SetNotificationService(manager.NotificationService)
+	manager.AuctionTaskScheduler.SetAuctionService(manager.AuctionService)
+	// 邀请制拍卖的房间只允许卖家和白名单成员订阅
+	manager.WSHub.SetSubscribeAuthorizer(manager.AuctionService.CanSubscribeRoom)
+
+	// 初始化密封拍卖服务，并注册揭示截止任务处理器
+	sealedEthClient, err := ethclientwrapper.NewClient(cfg.Ethereum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum client for sealed bid service: %w", err)
+	}
+	manager.SealedBidService = NewSealedBidService(cfg.Ethereum, sealedEthClient, manager.WSHub)
+	manager.AuctionTaskScheduler.RegisterHandler(TaskTypeSealedRevealEnd, manager.SealedBidService.FinalizeAuction)
+
+	// 初始化NFT报价服务，并注册报价过期任务处理器
+	offerEthClient, err := ethclientwrapper.NewClient(cfg.Ethereum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum client for offer service: %w", err)
+	}
+	manager.OfferService = NewOfferService(cfg.Ethereum, offerEthClient, manager.NotificationService,
+		manager.AuctionTaskScheduler, manager.AuctionService)
+	manager.AuctionTaskScheduler.RegisterHandler(TaskTypeOfferExpire, manager.OfferService.Expire)
+
+	// 初始化荷兰式拍卖价格推送器（推送到 WebSocket 拍卖房间）
+	manager.DutchPriceTicker = NewDutchPriceTicker(manager.WSHub, cfg.Auction.DutchPriceTickInterval)
+
+	// 初始化待上架拍卖过期扫描器（超时未上链的拍卖释放 NFT 在线锁并通知卖家）
+	manager.PendingSweeper = NewPendingAuctionSweeper(manager.AuctionService, manager.NotificationService,
+		cfg.Auction.PendingTTL, cfg.Auction.PendingSweepInterval)
+
+	// 初始化合约管理服务（管理员通过平台私钥发送合约管理交易，合约设置事件写入 contract_settings）
+	contractEthClient, err := ethclientwrapper.NewClient(cfg.Ethereum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum client for contract admin service: %w", err)
+	}
+	manager.ContractAdminService = NewContractAdminService(cfg.Ethereum, contractEthClient)
 
 	// 初始化NFT服务（需要以太坊客户端和Etherscan配置）
 	nftService, err := NewNFTService(cfg.Ethereum, cfg.Etherscan)
@@ -129,8 +215,74 @@ func (sm *ServiceManager) StartAuctionTaskScheduler(ctx context.Context) {
 	}
 }
 
+// StartDutchPriceTicker 启动荷兰式拍卖价格推送
+func (sm *ServiceManager) StartDutchPriceTicker(ctx context.Context) {
+	if sm.DutchPriceTicker != nil {
+		sm.DutchPriceTicker.Start(ctx)
+	}
+}
+
+// StartPendingSweeper 启动待上架拍卖过期扫描
+func (sm *ServiceManager) StartPendingSweeper(ctx context.Context) {
+	if sm.PendingSweeper != nil {
+		sm.PendingSweeper.Start(ctx)
+	}
+}
+
+// ResumeCancellationTracking 恢复跟踪服务重启前已发送的拍卖取消交易（失败只记录日志）
+func (sm *ServiceManager) ResumeCancellationTracking() {
+	if sm.AuctionService == nil {
+		return
+	}
+	if err := sm.AuctionService.ResumeCancellationTracking(); err != nil {
+		logger.Warn("failed to resume cancellation tracking: %v", err)
+	}
+}
+
+// ResumeContractActionTracking 恢复跟踪服务重启前已发送的合约管理交易（失败只记录日志）
+func (sm *ServiceManager) ResumeContractActionTracking() {
+	if sm.ContractAdminService == nil {
+		return
+	}
+	if err := sm.ContractAdminService.ResumeActionTracking(); err != nil {
+		logger.Warn("failed to resume contract action tracking: %v", err)
+	}
+}
+
+// ResumeBundleDeliveries 恢复服务重启前未完成的打包成员转移（失败只记录日志）
+func (sm *ServiceManager) ResumeBundleDeliveries() {
+	if sm.AuctionService == nil {
+		return
+	}
+	if err := sm.AuctionService.ResumeBundleDeliveries(); err != nil {
+		logger.Warn("failed to resume bundle deliveries: %v", err)
+	}
+}
+
+// SyncContractSettings 从链上同步合约当前设置（失败只记录日志）
+func (sm *ServiceManager) SyncContractSettings(ctx context.Context) {
+	if sm.ContractAdminService == nil {
+		return
+	}
+	if err := sm.ContractAdminService.SyncSettings(ctx); err != nil {
+		logger.Warn("failed to sync contract settings from chain: %v", err)
+		return
+	}
+	logger.Info("contract settings synced from chain")
+}
+
 // Close 关闭所有服务并释放资源
 func (sm *ServiceManager) Close() error {
+	// 停止荷兰式拍卖价格推送
+	if sm.DutchPriceTicker != nil {
+		sm.DutchPriceTicker.Stop()
+	}
+
+	// 停止待上架拍卖过期扫描
+	if sm.PendingSweeper != nil {
+		sm.PendingSweeper.Stop()
+	}
+
 	// 关闭拍卖任务调度器
 	if sm.AuctionTaskScheduler != nil {
 		sm.AuctionTaskScheduler.Shutdown()
@@ -156,5 +308,30 @@ func (sm *ServiceManager) Close() error {
 		sm.BidService.Close()
 	}
 
+	// 关闭密封拍卖服务的以太坊客户端
+	if sm.SealedBidService != nil {
+		sm.SealedBidService.Close()
+	}
+
+	// 关闭报价服务的以太坊客户端
+	if sm.OfferService != nil {
+		sm.OfferService.Close()
+	}
+
+	// 关闭用户服务的以太坊客户端
+	if sm.UserService != nil {
+		sm.UserService.Close()
+	}
+
+	// 关闭合约管理服务的以太坊客户端
+	if sm.ContractAdminService != nil {
+		sm.ContractAdminService.Close()
+	}
+
+	// 关闭 Redis 客户端
+	if sm.Redis != nil {
+		sm.Redis.Close()
+	}
+
 	return nil
 }
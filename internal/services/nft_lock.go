@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/models"
+)
+
+// NFT 在线锁：拍卖进入 pending 时为主 NFT 和全部打包成员写入 nft_locks，结束/取消/过期后删除。
+// nft_id 为主键，单个拍卖、打包主 NFT 和打包成员共用一张表，并发加锁时只有一个拍卖成功（Duplicate entry）
+
+// lockAuctionNFTs 为拍卖涉及的全部 NFT 加锁，NFT 已被其他拍卖锁定时返回包含 Duplicate entry 的数据库错误
+func lockAuctionNFTs(tx *gorm.DB, auction *models.Auction) error {
+	nftIDs, err := auctionNFTIDs(tx, auction)
+	if err != nil {
+		return err
+	}
+	locks := make([]models.NFTLock, 0, len(nftIDs))
+	for _, nftID := range nftIDs {
+		locks = append(locks, models.NFTLock{NFTID: nftID, AuctionID: auction.AuctionID})
+	}
+	if err := tx.Create(&locks).Error; err != nil {
+		return fmt.Errorf("failed to lock auction NFTs: %w", err)
+	}
+	return nil
+}
+
+// unlockAuctionNFTs 释放拍卖持有的 NFT 锁
+// keepMembers 为 true 时只释放主 NFT，打包成员保持锁定直到平台转移完成或失败（unlockBundleMember）
+func unlockAuctionNFTs(tx *gorm.DB, auction *models.Auction, keepMembers bool) error {
+	query := tx.Where("auction_id = ?", auction.AuctionID)
+	if keepMembers {
+		query = query.Where("nft_id = ?", auction.NFTID)
+	}
+	if err := query.Delete(&models.NFTLock{}).Error; err != nil {
+		return fmt.Errorf("failed to unlock auction NFTs: %w", err)
+	}
+	return nil
+}
+
+// unlockBundleMember 成员转移完成或失败后释放该成员保持的锁
+func unlockBundleMember(db *gorm.DB, item *models.AuctionBundleItem) error {
+	if err := db.Where("nft_id = ? AND auction_id = ?", item.NFTID, item.AuctionID).
+		Delete(&models.NFTLock{}).Error; err != nil {
+		return fmt.Errorf("failed to unlock bundle member: %w", err)
+	}
+	return nil
+}
+
+// findOnlineLock 查找锁定该 NFT 的拍卖ID（单个 NFT 拍卖或打包拍卖成员），未被锁定时返回空字符串
+func findOnlineLock(db *gorm.DB, nftID string) (string, error) {
+	var lock models.NFTLock
+	if err := db.Where("nft_id = ?", nftID).First(&lock).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check NFT lock: %w", err)
+	}
+	return lock.AuctionID, nil
+}
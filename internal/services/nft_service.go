@@ -58,9 +58,9 @@ type NFTService struct {
 // 返回值: nftId, error
 func (s *NFTService) OnNFTApproved(ownerAddress string, nftContractAddressStr string, tokenId uint64) (string, error) {
 	// 1. 根据 ownerAddress 获取 user_id
-	var user models.User
 	normalizedOwnerAddr := strings.ToLower(ownerAddress)
-	if err := database.DB.Where("wallet_address = ?", normalizedOwnerAddr).First(&user).Error; err != nil {
+	user, err := findUserByWallet(database.DB, normalizedOwnerAddr)
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			logger.Warn("User not found for wallet address: %s", ownerAddress)
 			return "", fmt.Errorf("user not found for wallet address: %w", err)
@@ -151,45 +151,52 @@ func (s *NFTService) SyncNFTs(userID uint64) (*models.NFTSyncResult, error) {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	// 规范化用户钱包地址
-	walletAddress := strings.ToLower(user.WalletAddress)
+	// 同步用户的所有钱包（主钱包和已关联钱包）
+	wallets, err := userWalletAddresses(database.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownWallets := make(map[string]bool, len(wallets))
+	for _, walletAddress := range wallets {
+		ownWallets[walletAddress] = true
+	}
 
-	// 查询最后一次同步的 NFTOwnership 记录，判断是否之前同步过
-	// 如果存在记录，使用其 blockNumber 作为起始区块；如果不存在，说明是首次同步，从区块 0 开始
-	var lastSyncedOwnership models.NFTOwnership
-	var startBlockNumber uint64 = 0
+	// 同一 NFT 可能出现在多个钱包的记录中（如在自己的钱包之间转移），保留区块号最大的记录
+	latest := make(map[string]ChainNFTData)
+	var order []string
+	for _, walletAddress := range wallets {
+		startBlockNumber, err := s.lastSyncedBlock(userID, walletAddress)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := database.DB.Model(&models.NFTOwnership{}).
-		Where("user_id = ?", userID).
-		Order("block_number DESC").
-		Preload("NFT").
-		First(&lastSyncedOwnership).Error; err != nil {
-		// 如果没有找到记录，说明是首次同步
-		if err == gorm.ErrRecordNotFound {
-			logger.Info("User %d has no previous sync record, starting first sync from block 0", userID)
-		} else {
-			// 其他数据库错误
-			logger.Error("failed to get last synced NFTOwnership for user %d: %s", userID, err.Error())
-			logger.Info("Continuing with first sync from block 0")
-			return nil, fmt.Errorf("failed to get last synced NFTOwnership for user %d: %w", userID, err)
+		// 从链上同步NFT数据（根据是否有上次同步记录决定起始区块号）
+		syncNFTsFromChainResponse, err := s.SyncNFTsFromChain(walletAddress, startBlockNumber, 1)
+		if err != nil {
+			logger.Error("failed to sync NFTs from chain: wallet=%s, error: %s", walletAddress, err.Error())
+			return nil, fmt.Errorf("failed to sync NFTs from chain!")
+		}
+		for _, nftdata := range syncNFTsFromChainResponse.NFTs {
+			existing, ok := latest[nftdata.NFTID]
+			if !ok {
+				order = append(order, nftdata.NFTID)
+			} else if existing.BlockNumber > nftdata.BlockNumber {
+				continue
+			}
+			latest[nftdata.NFTID] = nftdata
 		}
-		// startBlockNumber 保持为 0，表示从最开始同步
-	} else {
-		// 找到了上次同步记录，使用其 blockNumber 作为起始区块
-		startBlockNumber = lastSyncedOwnership.BlockNumber
-		logger.Info("User %d has previous sync record: NFTID=%s, Timestamp=%d, BlockNumber=%d, starting incremental sync from block %d",
-			userID, lastSyncedOwnership.NFTID, lastSyncedOwnership.Timestamp, lastSyncedOwnership.BlockNumber, startBlockNumber)
 	}
 
-	// 从链上同步NFT数据（根据是否有上次同步记录决定起始区块号）
 	var newNFTDatas *[]ChainNFTData
-	syncNFTsFromChainResponse, err := s.SyncNFTsFromChain(walletAddress, startBlockNumber, 1)
-	if err != nil {
-		logger.Error("failed to sync NFTs from chain: %s", err.Error())
-		return nil, fmt.Errorf("failed to sync NFTs from chain!")
-	}
-	if syncNFTsFromChainResponse.TotalFound > 0 {
-		newNFTDatas = &syncNFTsFromChainResponse.NFTs
+	if len(order) > 0 {
+		nftDatas := make([]ChainNFTData, 0, len(order))
+		for _, nftID := range order {
+			nftdata := latest[nftID]
+			// 接收方是用户的任一钱包即视为用户持有
+			nftdata.IsOwned = ownWallets[strings.ToLower(nftdata.To)]
+			nftDatas = append(nftDatas, nftdata)
+		}
+		newNFTDatas = &nftDatas
 	}
 
 	// 初始化同步结果
@@ -222,6 +229,26 @@ func (s *NFTService) SyncNFTs(userID uint64) (*models.NFTSyncResult, error) {
 	return result, nil
 }
 
+// lastSyncedBlock 钱包上次同步到的区块号：该用户 owner_address 为该钱包的 NFTOwnership 记录中最大的区块号
+// 没有记录时（首次同步或新关联的钱包）从区块 0 开始
+func (s *NFTService) lastSyncedBlock(userID uint64, walletAddress string) (uint64, error) {
+	var lastSyncedOwnership models.NFTOwnership
+	if err := database.DB.Model(&models.NFTOwnership{}).
+		Where("user_id = ? AND owner_address = ?", userID, walletAddress).
+		Order("block_number DESC").
+		First(&lastSyncedOwnership).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Info("User %d has no previous sync record for wallet %s, starting first sync from block 0", userID, walletAddress)
+			return 0, nil
+		}
+		logger.Error("failed to get last synced NFTOwnership for user %d: %s", userID, err.Error())
+		return 0, fmt.Errorf("failed to get last synced NFTOwnership for user %d: %w", userID, err)
+	}
+	logger.Info("User %d has previous sync record for wallet %s: NFTID=%s, BlockNumber=%d, starting incremental sync from block %d",
+		userID, walletAddress, lastSyncedOwnership.NFTID, lastSyncedOwnership.BlockNumber, lastSyncedOwnership.BlockNumber)
+	return lastSyncedOwnership.BlockNumber, nil
+}
+
 // GenerateNFTID 生成NFT的唯一ID（基于合约地址和TokenID）
 // 注意：contractAddress 应该在调用前已规范化
 func GenerateNFTID(contractAddress string, tokenID uint64) string {
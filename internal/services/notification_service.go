@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/mail"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/websocket"
+)
+
+// NotificationChannel 通知渠道（WebSocket、邮件等），新渠道实现该接口后通过 RegisterChannel 注册
+type NotificationChannel interface {
+	// Name 渠道名称（用于日志）
+	Name() string
+	// Send 向指定用户发送通知
+	Send(ctx context.Context, userID uint64, notification *models.Notification) error
+}
+
+// NotificationService 通知服务，将通知投递到所有已注册的渠道
+// 单个渠道失败只记录日志，不影响其他渠道
+type NotificationService struct {
+	channels []NotificationChannel
+}
+
+func NewNotificationService(channels ...NotificationChannel) *NotificationService {
+	return &NotificationService{
+		channels: channels,
+	}
+}
+
+// RegisterChannel 注册通知渠道
+func (s *NotificationService) RegisterChannel(channel NotificationChannel) {
+	s.channels = append(s.channels, channel)
+}
+
+// Notify 向多个用户发送通知
+func (s *NotificationService) Notify(ctx context.Context, userIDs []uint64, notification *models.Notification) {
+	for _, userID := range userIDs {
+		for _, channel := range s.channels {
+			if err := channel.Send(ctx, userID, notification); err != nil {
+				logger.Warn("failed to send notification via %s: userID=%d, type=%s, error=%v",
+					channel.Name(), userID, notification.Type, err)
+			}
+		}
+	}
+}
+
+// WebSocketNotificationChannel 通过 WebSocket 定向推送通知（用户需要携带 token 连接）
+type WebSocketNotificationChannel struct {
+	wsHub *websocket.Hub
+}
+
+func NewWebSocketNotificationChannel(wsHub *websocket.Hub) *WebSocketNotificationChannel {
+	return &WebSocketNotificationChannel{
+		wsHub: wsHub,
+	}
+}
+
+// Name 渠道名称
+func (c *WebSocketNotificationChannel) Name() string {
+	return "websocket"
+}
+
+// Send 推送通知给用户的所有 WebSocket 连接
+func (c *WebSocketNotificationChannel) Send(ctx context.Context, userID uint64, notification *models.Notification) error {
+	if c.wsHub == nil {
+		return fmt.Errorf("websocket hub not available")
+	}
+	message := websocket.NewMessage(websocket.MessageTypeNotification, notification)
+	return c.wsHub.SendToUser(uint(userID), message)
+}
+
+// EmailNotificationChannel 通过邮件发送通知，只发送到已验证的邮箱（未设置或未验证邮箱的用户跳过）
+// 邮件在后台发送，不阻塞其他渠道
+type EmailNotificationChannel struct {
+	sender mail.Sender
+}
+
+func NewEmailNotificationChannel(sender mail.Sender) *EmailNotificationChannel {
+	return &EmailNotificationChannel{
+		sender: sender,
+	}
+}
+
+// Name 渠道名称
+func (c *EmailNotificationChannel) Name() string {
+	return "email"
+}
+
+// Send 查询用户邮箱，已验证时在后台发送邮件
+func (c *EmailNotificationChannel) Send(ctx context.Context, userID uint64, notification *models.Notification) error {
+	var user models.User
+	if err := database.DB.WithContext(ctx).Select("id", "email", "email_verified", "status", "suspended_until").First(&user, userID).Error; err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.EmailAddress() == "" || !user.EmailVerified || user.EffectiveStatus(time.Now()) != models.UserStatusActive {
+		return nil
+	}
+
+	message := &mail.Message{
+		To:      user.EmailAddress(),
+		Subject: notification.Title,
+		Body:    notification.Content + "\n",
+	}
+	go func() {
+		if err := c.sender.Send(context.Background(), message); err != nil {
+			logger.Warn("failed to send notification email: userID=%d, type=%s, error=%v", userID, notification.Type, err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/contracts/erc20_metadata"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+	"my-auction-market-api/internal/utils"
+)
+
+const (
+	// offerMinLifetime 报价提交时距过期时间的最小间隔
+	offerMinLifetime = time.Minute
+	// offerListingDuration 接受报价后创建的固定价格出售时长
+	offerListingDuration = 24 * time.Hour
+)
+
+// OfferService 未上架 NFT 的链下签名报价服务
+// 流程：
+// 1. 买家对报价（NFT、代币、金额、过期时间、随机数）进行 EIP-712 签名并提交，后端校验签名、余额和对拍卖合约的授权额度
+// 2. NFT 持有者（nft_ownerships 中 holding 状态的用户）收到通知，可接受或拒绝
+// 3. 接受：再次校验买家余额和授权，以报价金额为持有者创建固定价格出售，通知买家在链上购买
+// 4. 到期未处理的报价由任务调度器标记为 expired
+type OfferService struct {
+	config         config.EthereumConfig
+	ethClient      *ethclientwrapper.Client
+	notifier       *NotificationService
+	scheduler      *AuctionTaskScheduler
+	auctionService *AuctionService
+}
+
+func NewOfferService(ethCfg config.EthereumConfig, ethClient *ethclientwrapper.Client, notifier *NotificationService,
+	scheduler *AuctionTaskScheduler, auctionService *AuctionService) *OfferService {
+	return &OfferService{
+		config:         ethCfg,
+		ethClient:      ethClient,
+		notifier:       notifier,
+		scheduler:      scheduler,
+		auctionService: auctionService,
+	}
+}
+
+// OfferTypedData 构建报价的 EIP-712 结构化数据（前端使用相同结构调用 eth_signTypedData_v4）
+// domain: {name: "MyAuctionMarket", version: "1", chainId}（报价由后端验证，不绑定合约地址）
+// Offer(string nftId,address nftAddress,uint256 tokenId,address buyer,address paymentToken,uint256 amount,uint256 expiry,uint256 nonce)
+func OfferTypedData(chainID int64, offer *models.Offer) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Offer": {
+				{Name: "nftId", Type: "string"},
+				{Name: "nftAddress", Type: "address"},
+				{Name: "tokenId", Type: "uint256"},
+				{Name: "buyer", Type: "address"},
+				{Name: "paymentToken", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+				{Name: "expiry", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Offer",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "MyAuctionMarket",
+			Version: "1",
+			ChainId: math.NewHexOrDecimal256(chainID),
+		},
+		Message: apitypes.TypedDataMessage{
+			"nftId":        offer.NFTID,
+			"nftAddress":   common.HexToAddress(offer.NFTAddress).Hex(),
+			"tokenId":      strconv.FormatUint(offer.TokenID, 10),
+			"buyer":        common.HexToAddress(offer.BuyerAddress).Hex(),
+			"paymentToken": common.HexToAddress(offer.PaymentToken).Hex(),
+			"amount":       offer.RawAmount,
+			"expiry":       strconv.FormatInt(offer.Expiry, 10),
+			"nonce":        offer.Nonce,
+		},
+	}
+}
+
+// Create 买家对未上架的 NFT 提交签名报价
+func (s *OfferService) Create(userID uint64, nftID string, payload models.OfferPayload) (*models.Offer, error) {
+	var buyer models.User
+	if err := database.DB.Where("id = ?", userID).First(&buyer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var ownership models.NFTOwnership
+	if err := database.DB.Where("nft_id = ? AND status = ?", nftID, models.NFTOwnershipStatusHolding).
+		Preload("NFT").
+		First(&ownership).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("NFT not found or not held by any user")
+		}
+		return nil, fmt.Errorf("failed to get NFT ownership: %w", err)
+	}
+	if uint64(ownership.UserID) == userID {
+		return nil, errors.BadRequest("cannot make an offer on your own NFT")
+	}
+
+	lockedBy, err := findOnlineLock(database.DB, nftID)
+	if err != nil {
+		return nil, err
+	}
+	if lockedBy != "" {
+		return nil, errors.BadRequest(fmt.Sprintf("NFT is already on sale, bid on auction %s instead", lockedBy))
+	}
+
+	// 报价需要链上授权额度，只支持 ERC20 代币
+	if strings.EqualFold(payload.PaymentToken, utils.ETHAddress) {
+		return nil, errors.BadRequest("offers must use an ERC20 payment token")
+	}
+	_, _, decimals, _, err := utils.ERC20Token(s.ethClient.GetClient(), payload.PaymentToken, s.config.ChainID)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	rawAmount, ok := new(big.Int).SetString(payload.Amount, 10)
+	if !ok || rawAmount.Sign() <= 0 {
+		return nil, errors.BadRequest("amount must be a positive integer in token base units")
+	}
+	if nonce, ok := new(big.Int).SetString(payload.Nonce, 10); !ok || nonce.Sign() < 0 {
+		return nil, errors.BadRequest("nonce must be a non-negative integer")
+	}
+	if time.Unix(payload.Expiry, 0).Before(time.Now().Add(offerMinLifetime)) {
+		return nil, errors.BadRequest(fmt.Sprintf("expiry must be at least %v in the future", offerMinLifetime))
+	}
+
+	offer := &models.Offer{
+		NFTID:        nftID,
+		NFTAddress:   strings.ToLower(ownership.NFT.ContractAddress),
+		TokenID:      ownership.NFT.TokenID,
+		OwnerUserID:  uint64(ownership.UserID),
+		BuyerUserID:  userID,
+		BuyerAddress: strings.ToLower(buyer.WalletAddress),
+		PaymentToken: strings.ToLower(payload.PaymentToken),
+		Amount:       decimal.NewFromBigInt(rawAmount, -int32(decimals)),
+		RawAmount:    rawAmount.String(),
+		Expiry:       payload.Expiry,
+		Nonce:        payload.Nonce,
+		Signature:    payload.Signature,
+		Status:       models.OfferStatusPending,
+	}
+
+	typedData := OfferTypedData(s.config.ChainID, offer)
+	valid, err := utils.VerifyTypedDataSignature(typedData, payload.Signature, buyer.WalletAddress)
+	if err != nil {
+		return nil, errors.BadRequest(fmt.Sprintf("invalid signature: %v", err))
+	}
+	if !valid {
+		return nil, errors.BadRequest("signature does not match wallet address")
+	}
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash offer: %w", err)
+	}
+	offer.OfferHash = hexutil.Encode(digest)
+
+	var existing int64
+	if err := database.DB.Model(&models.Offer{}).Where("offer_hash = ?", offer.OfferHash).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing offer: %w", err)
+	}
+	if existing > 0 {
+		return nil, errors.BadRequest("offer has already been submitted, use a new nonce")
+	}
+
+	if err := s.checkBuyerFunds(offer); err != nil {
+		return nil, err
+	}
+
+	usdResponse, err := ConvertToUSDFromTokenUnit(&s.config, offer.PaymentToken, rawAmount, s.ethClient.GetClient())
+	if err != nil {
+		logger.Warn("failed to convert offer amount to USD: nftID=%s, error=%v", nftID, err)
+	} else {
+		amountUSD := decimal.NewFromFloat(usdResponse.AmountUSD)
+		offer.AmountUSD = &amountUSD
+	}
+
+	if err := database.DB.Create(offer).Error; err != nil {
+		return nil, fmt.Errorf("failed to save offer: %w", err)
+	}
+
+	if s.scheduler != nil {
+		if err := s.scheduler.ScheduleOfferExpireTask(offer); err != nil {
+			logger.Error("failed to schedule offer expire task: offerID=%d, error=%v", offer.ID, err)
+		}
+	}
+
+	s.notify(offer.OwnerUserID, offer, models.NotificationTypeOfferReceived, "收到新报价",
+		fmt.Sprintf("您持有的 NFT %s 收到 %s 的报价", offer.NFTID, offer.Amount.String()))
+
+	logger.Info("offer created: offerID=%d, nftID=%s, buyerUserID=%d, amount=%s", offer.ID, nftID, userID, offer.RawAmount)
+	return offer, nil
+}
+
+// checkBuyerFunds 校验买家的代币余额和对拍卖合约的授权额度是否足够支付报价
+func (s *OfferService) checkBuyerFunds(offer *models.Offer) error {
+	amount, ok := new(big.Int).SetString(offer.RawAmount, 10)
+	if !ok {
+		return fmt.Errorf("invalid offer amount: %s", offer.RawAmount)
+	}
+
+	token, err := erc20_metadata.NewIERC20MetadataSolIERC20Metadata(
+		common.HexToAddress(offer.PaymentToken), bind.ContractBackend(s.ethClient.GetClient()))
+	if err != nil {
+		return fmt.Errorf("failed to create ERC20 contract: %w", err)
+	}
+
+	buyer := common.HexToAddress(offer.BuyerAddress)
+	balance, err := token.BalanceOf(&bind.CallOpts{}, buyer)
+	if err != nil {
+		return fmt.Errorf("failed to get token balance: %w", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return errors.BadRequest("insufficient token balance for this offer")
+	}
+
+	allowance, err := token.Allowance(&bind.CallOpts{}, buyer, common.HexToAddress(s.config.AuctionContractAddress))
+	if err != nil {
+		return fmt.Errorf("failed to get token allowance: %w", err)
+	}
+	if allowance.Cmp(amount) < 0 {
+		return errors.BadRequest("insufficient token allowance to the auction contract for this offer")
+	}
+	return nil
+}
+
+// ListReceived 获取当前用户作为持有者收到的报价
+func (s *OfferService) ListReceived(userID uint64, status string, query page.PageQuery) ([]models.Offer, int64, error) {
+	return s.list(database.DB.Where("owner_user_id = ?", userID), status, query)
+}
+
+// ListMade 获取当前用户作为买家提交的报价
+func (s *OfferService) ListMade(userID uint64, status string, query page.PageQuery) ([]models.Offer, int64, error) {
+	return s.list(database.DB.Where("buyer_user_id = ?", userID), status, query)
+}
+
+func (s *OfferService) list(db *gorm.DB, status string, query page.PageQuery) ([]models.Offer, int64, error) {
+	db = db.Model(&models.Offer{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var offers []models.Offer
+	if err := db.Order("id DESC").
+		Offset(query.Offset()).
+		Limit(query.Limit()).
+		Find(&offers).Error; err != nil {
+		return nil, 0, err
+	}
+	return offers, total, nil
+}
+
+// Accept 持有者接受报价
+// 以报价金额为持有者创建固定价格出售（pending，等待持有者签名上链），买家随后在链上按标价购买
+func (s *OfferService) Accept(userID uint64, offerID uint64) (*models.Offer, error) {
+	offer, err := s.getOwnerOffer(userID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() >= offer.Expiry {
+		return nil, errors.BadRequest("offer has expired")
+	}
+
+	var holding int64
+	if err := database.DB.Model(&models.NFTOwnership{}).
+		Where("nft_id = ? AND user_id = ? AND status = ?", offer.NFTID, userID, models.NFTOwnershipStatusHolding).
+		Count(&holding).Error; err != nil {
+		return nil, fmt.Errorf("failed to verify NFT ownership: %w", err)
+	}
+	if holding == 0 {
+		return nil, errors.BadRequest("you no longer hold this NFT")
+	}
+
+	if err := s.checkBuyerFunds(offer); err != nil {
+		return nil, err
+	}
+
+	endTime := time.Now().Add(offerListingDuration)
+	listing, err := s.auctionService.CreateListing(userID, models.ListingPayload{
+		NFTID:        offer.NFTID,
+		NFTAddress:   offer.NFTAddress,
+		TokenID:      offer.TokenID,
+		PaymentToken: offer.PaymentToken,
+		Price:        offer.Amount,
+		EndTime:      &endTime,
+		// 只允许报价钱包购买：其他钱包的出价会被标记，不会提前成交，结算时被拒绝
+		VisibilityParams: models.VisibilityParams{
+			Visibility:       models.AuctionVisibilityAllowlist,
+			AllowlistWallets: []string{offer.BuyerAddress},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := database.DB.Model(offer).
+		Where("status = ?", models.OfferStatusPending).
+		Updates(map[string]interface{}{
+			"status":             models.OfferStatusAccepted,
+			"listing_auction_id": listing.AuctionID,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		// 报价状态已被并发修改（过期或撤回），撤销刚创建的出售
+		if _, delistErr := s.auctionService.Delist(userID, listing.AuctionID); delistErr != nil {
+			logger.Error("failed to delist listing of unaccepted offer: offerID=%d, auctionID=%s, error=%v",
+				offer.ID, listing.AuctionID, delistErr)
+		}
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to accept offer: %w", result.Error)
+		}
+		return nil, errors.BadRequest("offer is no longer pending")
+	}
+	offer.Status = models.OfferStatusAccepted
+	offer.ListingAuctionID = listing.AuctionID
+
+	if s.scheduler != nil {
+		s.scheduler.DeleteOfferExpireTask(offer.ID)
+	}
+	s.notify(offer.BuyerUserID, offer, models.NotificationTypeOfferAccepted, "报价已被接受",
+		fmt.Sprintf("您对 NFT %s 的报价已被接受，卖家上链后请在 %s 内按报价金额购买",
+			offer.NFTID, endTime.Format(time.RFC3339)))
+
+	logger.Info("offer accepted: offerID=%d, listingAuctionID=%s", offer.ID, listing.AuctionID)
+	return offer, nil
+}
+
+// Reject 持有者拒绝报价
+func (s *OfferService) Reject(userID uint64, offerID uint64) (*models.Offer, error) {
+	offer, err := s.getOwnerOffer(userID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transition(offer, models.OfferStatusRejected); err != nil {
+		return nil, err
+	}
+
+	s.notify(offer.BuyerUserID, offer, models.NotificationTypeOfferRejected, "报价已被拒绝",
+		fmt.Sprintf("您对 NFT %s 的报价已被拒绝", offer.NFTID))
+	return offer, nil
+}
+
+// Cancel 买家撤回报价
+func (s *OfferService) Cancel(userID uint64, offerID uint64) (*models.Offer, error) {
+	var offer models.Offer
+	if err := database.DB.Where("id = ? AND buyer_user_id = ?", offerID, userID).First(&offer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Forbidden("offer not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+	if err := s.transition(&offer, models.OfferStatusCancelled); err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// Expire 报价过期（由任务调度器在报价过期时间调用，offerID 为任务负载中的 TaskID）
+func (s *OfferService) Expire(ctx context.Context, offerID string) error {
+	var offer models.Offer
+	if err := database.DB.Where("id = ?", offerID).First(&offer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("offer not found for expire task: offerID=%s", offerID)
+			return nil
+		}
+		return fmt.Errorf("failed to get offer: %w", err)
+	}
+	if offer.Status != models.OfferStatusPending {
+		return nil
+	}
+
+	result := database.DB.Model(&offer).
+		Where("status = ? AND expiry <= ?", models.OfferStatusPending, time.Now().Unix()).
+		Updates(map[string]interface{}{
+			"status":     models.OfferStatusExpired,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to expire offer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	offer.Status = models.OfferStatusExpired
+
+	s.notify(offer.BuyerUserID, &offer, models.NotificationTypeOfferExpired, "报价已过期",
+		fmt.Sprintf("您对 NFT %s 的报价已过期", offer.NFTID))
+	logger.Info("offer expired: offerID=%d", offer.ID)
+	return nil
+}
+
+// getOwnerOffer 查询当前用户收到的待处理报价
+func (s *OfferService) getOwnerOffer(userID uint64, offerID uint64) (*models.Offer, error) {
+	var offer models.Offer
+	if err := database.DB.Where("id = ? AND owner_user_id = ?", offerID, userID).First(&offer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.Forbidden("offer not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+	if offer.Status != models.OfferStatusPending {
+		return nil, errors.BadRequest(fmt.Sprintf("offer is not pending, status: %s", offer.Status))
+	}
+	return &offer, nil
+}
+
+// transition 将待处理报价更新为终态（rejected、cancelled），并删除过期任务
+func (s *OfferService) transition(offer *models.Offer, status string) error {
+	result := database.DB.Model(offer).
+		Where("status = ?", models.OfferStatusPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update offer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.BadRequest("offer is no longer pending")
+	}
+	offer.Status = status
+
+	if s.scheduler != nil {
+		s.scheduler.DeleteOfferExpireTask(offer.ID)
+	}
+	logger.Info("offer %s: offerID=%d", status, offer.ID)
+	return nil
+}
+
+// notify 发送报价相关通知
+func (s *OfferService) notify(userID uint64, offer *models.Offer, notificationType, title, content string) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(context.Background(), []uint64{userID}, &models.Notification{
+		Type:      notificationType,
+		Title:     title,
+		Content:   content,
+		AuctionID: offer.ListingAuctionID,
+		Data: map[string]interface{}{
+			"offerId":      offer.ID,
+			"nftId":        offer.NFTID,
+			"paymentToken": offer.PaymentToken,
+			"amount":       offer.Amount.String(),
+			"status":       offer.Status,
+		},
+		CreatedAt: time.Now(),
+	})
+}
+
+// Close 关闭服务并释放资源
+func (s *OfferService) Close() error {
+	if s.ethClient != nil {
+		s.ethClient.Close()
+	}
+	return nil
+}
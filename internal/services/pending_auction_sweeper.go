@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+// pendingSweepBatchSize 每次扫描最多处理的过期拍卖数量
+const pendingSweepBatchSize = 100
+
+// PendingAuctionSweeper 待上架拍卖过期扫描器
+// 定时将超过 TTL 仍未上链的 pending 拍卖标记为 expired，释放 NFT 在线锁并通知卖家
+type PendingAuctionSweeper struct {
+	auctionService *AuctionService
+	notifier       *NotificationService
+	ttl            time.Duration
+	interval       time.Duration
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+	mu             sync.Mutex
+}
+
+// NewPendingAuctionSweeper 创建待上架拍卖过期扫描器
+func NewPendingAuctionSweeper(auctionService *AuctionService, notifier *NotificationService,
+	ttl time.Duration, interval time.Duration) *PendingAuctionSweeper {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &PendingAuctionSweeper{
+		auctionService: auctionService,
+		notifier:       notifier,
+		ttl:            ttl,
+		interval:       interval,
+	}
+}
+
+// Start 启动过期扫描（重复调用无副作用）
+func (p *PendingAuctionSweeper) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return
+	}
+	sweeperCtx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go p.run(sweeperCtx)
+	logger.Info("pending auction sweeper started (ttl: %v, interval: %v)", p.ttl, p.interval)
+}
+
+// Stop 停止过期扫描
+func (p *PendingAuctionSweeper) Stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		p.wg.Wait()
+		logger.Info("pending auction sweeper stopped")
+	}
+}
+
+func (p *PendingAuctionSweeper) run(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.sweep(ctx); err != nil {
+				logger.Warn("failed to sweep pending auctions: %v", err)
+			}
+		}
+	}
+}
+
+// sweep 过期所有超过 TTL 的 pending 拍卖
+// pending 拍卖的 online 字段为进入 pending 的时间戳（创建、提交草稿或重新上架时写入）；
+// online 为 1（已标记上线但仍未上链）或 0 的 pending 拍卖没有有效时间戳，同样视为已过期
+func (p *PendingAuctionSweeper) sweep(ctx context.Context) error {
+	cutoff := time.Now().Add(-p.ttl).Unix()
+	var auctions []models.Auction
+	if err := database.DB.
+		Where("status = ? AND online <= ?", AuctionStatusPending, cutoff).
+		Order("online ASC").
+		Limit(pendingSweepBatchSize).
+		Find(&auctions).Error; err != nil {
+		return fmt.Errorf("failed to load stale pending auctions: %w", err)
+	}
+
+	for i := range auctions {
+		auction := &auctions[i]
+		expired, err := p.auctionService.ExpirePending(auction, TransitionActorSystem, TransitionCauseTask, "pending_ttl")
+		if err != nil {
+			logger.Error("failed to expire pending auction: auctionID=%s, error=%v", auction.AuctionID, err)
+			continue
+		}
+		if !expired {
+			continue
+		}
+		logger.Info("pending auction expired: auctionID=%s, nftID=%s", auction.AuctionID, auction.NFTID)
+
+		if p.notifier != nil {
+			p.notifier.Notify(ctx, []uint64{auction.UserID}, &models.Notification{
+				Type:      models.NotificationTypeAuctionExpired,
+				Title:     "待上架拍卖已过期",
+				Content:   "您的拍卖长时间未签名链上 createAuction，已自动过期并释放 NFT，可以重新创建拍卖",
+				AuctionID: auction.AuctionID,
+				Data: map[string]interface{}{
+					"nftId":   auction.NFTID,
+					"tokenId": auction.TokenID,
+					"ttl":     p.ttl.String(),
+				},
+				CreatedAt: time.Now(),
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/page"
+	"my-auction-market-api/internal/utils"
+	"my-auction-market-api/internal/websocket"
+)
+
+// sealedBidGenesisHash 每个拍卖承诺链的起始哈希
+const sealedBidGenesisHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+var commitHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// SealedBidService 密封拍卖（提交-揭示）服务
+// 流程：
+// 1. 提交阶段：出价者提交 keccak256(auctionId|bidder|paymentToken|amount|salt) 承诺并签名
+// 2. 揭示阶段：出价者提交明文金额和盐，后端重新计算哈希并校验签名
+// 3. 揭示截止：后端按USD价值排名，最高者获胜，成交价为第二高价（只有一个有效出价时为起拍价）
+// 4. 结算阶段：获胜者在拍卖结束前按成交价在链上出价，拍卖结束任务照常结算
+type SealedBidService struct {
+	config    config.EthereumConfig
+	ethClient *ethclientwrapper.Client
+	wsHub     *websocket.Hub
+}
+
+func NewSealedBidService(ethCfg config.EthereumConfig, ethClient *ethclientwrapper.Client, wsHub *websocket.Hub) *SealedBidService {
+	return &SealedBidService{
+		config:    ethCfg,
+		ethClient: ethClient,
+		wsHub:     wsHub,
+	}
+}
+
+// SealedBidCommitHash 计算出价承诺哈希（前端使用相同规则计算）
+// keccak256("{auctionId}|{bidder小写}|{paymentToken小写}|{amount}|{salt}")
+func SealedBidCommitHash(auctionID, bidder, paymentToken, amount, salt string) string {
+	preimage := strings.Join([]string{
+		auctionID,
+		strings.ToLower(bidder),
+		strings.ToLower(paymentToken),
+		amount,
+		salt,
+	}, "|")
+	return crypto.Keccak256Hash([]byte(preimage)).Hex()
+}
+
+// SealedBidCommitMessage 提交承诺时需要签名的消息
+func SealedBidCommitMessage(auctionID, commitHash string) string {
+	return fmt.Sprintf("Sealed bid commitment\nAuction: %s\nCommitment: %s", auctionID, strings.ToLower(commitHash))
+}
+
+// SealedBidRevealMessage 揭示出价时需要签名的消息
+func SealedBidRevealMessage(auctionID, commitHash string) string {
+	return fmt.Sprintf("Sealed bid reveal\nAuction: %s\nCommitment: %s", auctionID, strings.ToLower(commitHash))
+}
+
+// sealedBidChainHash 计算承诺的链式哈希
+func sealedBidChainHash(prevChainHash string, bid *models.SealedBid) string {
+	preimage := strings.Join([]string{
+		prevChainHash,
+		bid.AuctionID,
+		strconv.FormatUint(bid.UserID, 10),
+		bid.WalletAddress,
+		bid.CommitHash,
+		bid.CommitSignature,
+		strconv.FormatInt(bid.CommittedAt.UnixMicro(), 10),
+	}, "|")
+	return crypto.Keccak256Hash([]byte(preimage)).Hex()
+}
+
+// Commit 提交密封出价承诺（仅提交阶段可用，每个用户每个拍卖只能提交一次）
+func (s *SealedBidService) Commit(userID uint64, auctionID string, payload models.SealedBidCommitPayload) (*models.SealedBid, error) {
+	commitHash := strings.ToLower(payload.CommitHash)
+	if !commitHashPattern.MatchString(commitHash) {
+		return nil, errors.BadRequest("commit hash must be a 0x-prefixed 32-byte hex string")
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	valid, err := utils.VerifySignature(SealedBidCommitMessage(auctionID, commitHash), payload.Signature, user.WalletAddress)
+	if err != nil {
+		return nil, errors.BadRequest(fmt.Sprintf("invalid signature: %v", err))
+	}
+	if !valid {
+		return nil, errors.BadRequest("signature does not match wallet address")
+	}
+
+	var sealedBid models.SealedBid
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定拍卖记录，保证同一拍卖的承诺按顺序串联
+		var auction models.Auction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NotFound("auction not found")
+			}
+			return fmt.Errorf("failed to get auction: %w", err)
+		}
+		if !auction.IsSealed() {
+			return errors.BadRequest("auction is not a sealed-bid auction")
+		}
+		if !sealedAuctionStarted(&auction, time.Now()) {
+			return errors.BadRequest(fmt.Sprintf("auction is not active, status: %s", auction.Status))
+		}
+		if auction.UserID == userID {
+			return errors.Forbidden("seller cannot bid on own auction")
+		}
+		if phase := auction.SealedPhase(time.Now()); phase != models.SealedPhaseCommit {
+			return errors.BadRequest("commit window is not open")
+		}
+
+		var existing int64
+		if err := tx.Model(&models.SealedBid{}).
+			Where("auction_id = ? AND user_id = ?", auctionID, userID).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check existing commitment: %w", err)
+		}
+		if existing > 0 {
+			return errors.BadRequest("you have already committed a bid for this auction")
+		}
+
+		prevChainHash := sealedBidGenesisHash
+		var last models.SealedBid
+		if err := tx.Where("auction_id = ?", auctionID).Order("id DESC").First(&last).Error; err == nil {
+			prevChainHash = last.ChainHash
+		} else if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get last commitment: %w", err)
+		}
+
+		// datetime(6) 只保留到微秒，截断后再参与哈希计算
+		committedAt := time.Now().Truncate(time.Microsecond)
+		sealedBid = models.SealedBid{
+			AuctionID:       auctionID,
+			UserID:          userID,
+			WalletAddress:   user.WalletAddress,
+			CommitHash:      commitHash,
+			CommitSignature: payload.Signature,
+			PrevChainHash:   prevChainHash,
+			CommittedAt:     &committedAt,
+			Status:          models.SealedBidStatusCommitted,
+		}
+		sealedBid.ChainHash = sealedBidChainHash(prevChainHash, &sealedBid)
+
+		if err := tx.Create(&sealedBid).Error; err != nil {
+			return fmt.Errorf("failed to save commitment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("sealed bid committed: auctionID=%s, userID=%d, commitHash=%s", auctionID, userID, commitHash)
+	return &sealedBid, nil
+}
+
+// Reveal 揭示密封出价（仅揭示阶段可用）
+func (s *SealedBidService) Reveal(userID uint64, auctionID string, payload models.SealedBidRevealPayload) (*models.SealedBid, error) {
+	auction, err := s.getSealedAuction(auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if !sealedAuctionStarted(auction, time.Now()) {
+		return nil, errors.BadRequest(fmt.Sprintf("auction is not active, status: %s", auction.Status))
+	}
+	if phase := auction.SealedPhase(time.Now()); phase != models.SealedPhaseReveal {
+		return nil, errors.BadRequest("reveal window is not open")
+	}
+
+	var sealedBid models.SealedBid
+	if err := database.DB.Where("auction_id = ? AND user_id = ?", auctionID, userID).First(&sealedBid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("no commitment found for this auction")
+		}
+		return nil, fmt.Errorf("failed to get commitment: %w", err)
+	}
+	if sealedBid.Status != models.SealedBidStatusCommitted {
+		return nil, errors.BadRequest("bid has already been revealed")
+	}
+
+	// 揭示前先校验承诺链，防止承诺在提交后被修改
+	if err := s.verifyChain(auctionID); err != nil {
+		logger.Error("sealed bid chain verification failed: auctionID=%s, error=%v", auctionID, err)
+		return nil, fmt.Errorf("sealed bid commitments failed integrity check: %w", err)
+	}
+
+	if SealedBidCommitHash(auctionID, sealedBid.WalletAddress, payload.PaymentToken, payload.Amount, payload.Salt) != sealedBid.CommitHash {
+		return nil, errors.BadRequest("revealed bid does not match commitment")
+	}
+
+	valid, err := utils.VerifySignature(SealedBidRevealMessage(auctionID, sealedBid.CommitHash), payload.Signature, sealedBid.WalletAddress)
+	if err != nil {
+		return nil, errors.BadRequest(fmt.Sprintf("invalid signature: %v", err))
+	}
+	if !valid {
+		return nil, errors.BadRequest("signature does not match wallet address")
+	}
+
+	amount, err := decimal.NewFromString(payload.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, errors.BadRequest("amount must be a positive decimal")
+	}
+
+	amountFloat, _ := amount.Float64()
+	usdResponse, err := ConvertTokenAmountToUSD(&s.config, payload.PaymentToken, amountFloat, s.ethClient.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert bid amount to USD: %w", err)
+	}
+	amountUSD := decimal.NewFromFloat(usdResponse.AmountUSD)
+
+	// 低于起拍价的出价标记为无效（仍保留记录，不参与排名）
+	status := models.SealedBidStatusRevealed
+	if auction.IsBidTokenSameAsAuction(strings.ToLower(payload.PaymentToken)) && auction.StartPrice != nil {
+		if amount.LessThan(*auction.StartPrice) {
+			status = models.SealedBidStatusInvalid
+		}
+	} else if auction.StartPriceUSD != nil && amountUSD.LessThan(*auction.StartPriceUSD) {
+		status = models.SealedBidStatusInvalid
+	}
+
+	now := time.Now()
+	if err := database.DB.Model(&sealedBid).
+		Where("status = ?", models.SealedBidStatusCommitted).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"payment_token":    strings.ToLower(payload.PaymentToken),
+			"amount":           amount,
+			"amount_usd":       amountUSD,
+			"raw_amount":       payload.Amount,
+			"salt":             payload.Salt,
+			"reveal_signature": payload.Signature,
+			"revealed_at":      now,
+			"updated_at":       now,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to save revealed bid: %w", err)
+	}
+
+	if err := database.DB.First(&sealedBid, sealedBid.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload revealed bid: %w", err)
+	}
+
+	logger.Info("sealed bid revealed: auctionID=%s, userID=%d, status=%s", auctionID, userID, status)
+	return &sealedBid, nil
+}
+
+// List 获取拍卖的密封出价列表
+// 揭示阶段结束前只返回承诺信息，不返回任何金额
+func (s *SealedBidService) List(auctionID string, query page.PageQuery) ([]models.SealedBid, int64, error) {
+	auction, err := s.getSealedAuction(auctionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := database.DB.Model(&models.SealedBid{}).
+		Where("auction_id = ?", auctionID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bids []models.SealedBid
+	if err := database.DB.
+		Where("auction_id = ?", auctionID).
+		Order("id ASC").
+		Offset(query.Offset()).
+		Limit(query.Limit()).
+		Find(&bids).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if auction.RevealEndTime == nil || time.Now().Before(*auction.RevealEndTime) {
+		for i := range bids {
+			bids[i].PaymentToken = ""
+			bids[i].Amount = nil
+			bids[i].AmountUSD = nil
+		}
+	}
+
+	return bids, total, nil
+}
+
+// FinalizeAuction 揭示截止后确定获胜者和成交价（由任务调度器在 RevealEndTime 调用）
+func (s *SealedBidService) FinalizeAuction(ctx context.Context, auctionID string) error {
+	auction, err := s.getSealedAuction(auctionID)
+	if err != nil {
+		logger.Warn("sealed auction not found, skipping finalize: auctionID=%s, error=%v", auctionID, err)
+		return nil
+	}
+	if !sealedAuctionStarted(auction, time.Now()) {
+		logger.Info("sealed auction is not active, skipping finalize: auctionID=%s, status=%s", auctionID, auction.Status)
+		return nil
+	}
+	if auction.SealedWinner != "" {
+		return nil
+	}
+	if auction.RevealEndTime == nil || time.Now().Before(*auction.RevealEndTime) {
+		return fmt.Errorf("reveal window has not closed yet: auctionID=%s", auctionID)
+	}
+
+	// 承诺链被篡改时拒绝确定获胜者，需要人工介入
+	if err := s.verifyChain(auctionID); err != nil {
+		logger.Error("sealed bid chain verification failed, auction will not be settled: auctionID=%s, error=%v", auctionID, err)
+		return nil
+	}
+
+	var bids []models.SealedBid
+	if err := database.DB.Where("auction_id = ?", auctionID).Order("id ASC").Find(&bids).Error; err != nil {
+		return fmt.Errorf("failed to load sealed bids: %w", err)
+	}
+
+	revealed := make([]models.SealedBid, 0, len(bids))
+	for _, bid := range bids {
+		if bid.Status == models.SealedBidStatusRevealed && bid.AmountUSD != nil {
+			revealed = append(revealed, bid)
+		}
+	}
+	// USD 价值从高到低，价值相同时先提交者优先
+	sort.SliceStable(revealed, func(i, j int) bool {
+		if !revealed[i].AmountUSD.Equal(*revealed[j].AmountUSD) {
+			return revealed[i].AmountUSD.GreaterThan(*revealed[j].AmountUSD)
+		}
+		return revealed[i].CommittedAt.Before(*revealed[j].CommittedAt)
+	})
+
+	result := models.SealedAuctionResult{
+		AuctionID:         auctionID,
+		RevealedCount:     len(revealed),
+		CommittedCount:    len(bids),
+		SettlementEndTime: auction.EndTime,
+	}
+
+	if len(revealed) > 0 {
+		winner := revealed[0]
+
+		// 成交价为第二高价，只有一个有效出价时为起拍价
+		clearingUSD := decimal.Zero
+		if len(revealed) > 1 {
+			clearingUSD = *revealed[1].AmountUSD
+		} else if auction.StartPriceUSD != nil {
+			clearingUSD = *auction.StartPriceUSD
+		}
+		// 按获胜者揭示时的汇率换算为获胜者出价代币金额
+		clearingPrice := *winner.Amount
+		if winner.AmountUSD.IsPositive() {
+			clearingPrice = winner.Amount.Mul(clearingUSD).Div(*winner.AmountUSD)
+		}
+
+		result.Winner = winner.WalletAddress
+		result.PaymentToken = winner.PaymentToken
+		result.ClearingPrice = &clearingPrice
+		result.ClearingPriceUSD = &clearingUSD
+
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			for i, bid := range revealed {
+				if err := tx.Model(&models.SealedBid{}).Where("id = ?", bid.ID).
+					Updates(map[string]interface{}{
+						"rank":       i + 1,
+						"winner":     i == 0,
+						"updated_at": time.Now(),
+					}).Error; err != nil {
+					return fmt.Errorf("failed to update sealed bid rank: %w", err)
+				}
+			}
+			if err := tx.Model(&models.Auction{}).Where("auction_id = ?", auctionID).
+				Updates(map[string]interface{}{
+					"sealed_winner":             winner.WalletAddress,
+					"sealed_payment_token":      winner.PaymentToken,
+					"sealed_clearing_price":     clearingPrice,
+					"sealed_clearing_price_usd": clearingUSD,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to save sealed auction result: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		logger.Info("sealed auction finalized: auctionID=%s, winner=%s, clearingPrice=%s, clearingPriceUSD=%s",
+			auctionID, winner.WalletAddress, clearingPrice.String(), clearingUSD.String())
+	} else {
+		logger.Info("sealed auction finalized without valid reveals: auctionID=%s, commitments=%d", auctionID, len(bids))
+	}
+
+	if s.wsHub != nil {
+		roomID := fmt.Sprintf("auction:%s", auctionID)
+		message := websocket.NewMessage(websocket.MessageTypeSealedAuctionResult, result)
+		if err := s.wsHub.BroadcastToRoom(roomID, message); err != nil {
+			logger.Error("failed to broadcast sealed auction result to room %s: %v", roomID, err)
+		}
+	}
+
+	return nil
+}
+
+// OnSealedBidPlaced 检查密封拍卖的链上出价
+// 合约本身不区分拍卖类型，出价时只记录不符合结算规则的链上出价（非获胜者、低于成交价或不在结算阶段）；
+// 结算时由 sealedSettlementRejection 校验链上最高出价，不符合时平台取消拍卖并退款
+func (s *SealedBidService) OnSealedBidPlaced(bid *models.Bid) error {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", bid.AuctionID).First(&auction).Error; err != nil {
+		return fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.IsSealed() {
+		return nil
+	}
+
+	bidTime := time.Now()
+	if bid.Timestamp > 0 {
+		bidTime = time.Unix(int64(bid.Timestamp), 0)
+	}
+	if phase := auction.SealedPhase(bidTime); phase != models.SealedPhaseSettlement {
+		logger.Warn("on-chain bid placed outside sealed settlement phase: auctionID=%s, bidder=%s, phase=%s",
+			auction.AuctionID, bid.WalletAddress, phase)
+		return nil
+	}
+	if !strings.EqualFold(bid.WalletAddress, auction.SealedWinner) {
+		logger.Warn("on-chain bid placed by non-winner in sealed auction, auction will be cancelled at settlement: auctionID=%s, bidder=%s, winner=%s",
+			auction.AuctionID, bid.WalletAddress, auction.SealedWinner)
+		return nil
+	}
+	if sealedBidBelowClearingPrice(&auction, bid) {
+		logger.Warn("sealed auction winner bid below clearing price: auctionID=%s, amount=%s, clearingPrice=%s",
+			auction.AuctionID, bid.Amount.String(), auction.SealedClearingPrice.String())
+	}
+	return nil
+}
+
+// sealedAuctionStarted 拍卖已上链且开始时间已到（开始任务可能尚未把 upcoming 切换为 active）
+func sealedAuctionStarted(auction *models.Auction, now time.Time) bool {
+	switch auction.Status {
+	case AuctionStatusActive:
+		return true
+	case AuctionStatusUpcoming:
+		return auction.StartTime != nil && !now.Before(*auction.StartTime)
+	default:
+		return false
+	}
+}
+
+// sealedBidBelowClearingPrice 链上出价是否低于密封拍卖成交价（同币种比较代币金额，不同币种比较USD价值）
+func sealedBidBelowClearingPrice(auction *models.Auction, bid *models.Bid) bool {
+	if strings.EqualFold(bid.PaymentToken, auction.SealedPaymentToken) && auction.SealedClearingPrice != nil && bid.Amount != nil {
+		return bid.Amount.LessThan(*auction.SealedClearingPrice)
+	}
+	if auction.SealedClearingPriceUSD != nil && bid.AmountUSD != nil {
+		return bid.AmountUSD.LessThan(*auction.SealedClearingPriceUSD)
+	}
+	return false
+}
+
+// sealedSettlementRejection 密封拍卖结算校验：链上最高出价必须是揭示结果的获胜者在结算阶段按不低于成交价的出价
+func sealedSettlementRejection(auction *models.Auction, bid *models.Bid) string {
+	if auction.SealedWinner == "" {
+		return SettlementRejectSealedNoWinner
+	}
+	if !strings.EqualFold(bid.WalletAddress, auction.SealedWinner) {
+		return SettlementRejectSealedWinnerMismatch
+	}
+	bidTime := time.Now()
+	if bid.Timestamp > 0 {
+		bidTime = time.Unix(int64(bid.Timestamp), 0)
+	}
+	if auction.SealedPhase(bidTime) != models.SealedPhaseSettlement {
+		return SettlementRejectSealedOutsideSettlement
+	}
+	if sealedBidBelowClearingPrice(auction, bid) {
+		return SettlementRejectSealedBelowClearingPrice
+	}
+	return ""
+}
+
+// verifyChain 按提交顺序重新计算承诺链，并校验已揭示出价与承诺哈希一致
+func (s *SealedBidService) verifyChain(auctionID string) error {
+	var bids []models.SealedBid
+	if err := database.DB.Where("auction_id = ?", auctionID).Order("id ASC").Find(&bids).Error; err != nil {
+		return fmt.Errorf("failed to load sealed bids: %w", err)
+	}
+
+	prevChainHash := sealedBidGenesisHash
+	for i := range bids {
+		bid := &bids[i]
+		if bid.PrevChainHash != prevChainHash {
+			return fmt.Errorf("chain broken at sealed bid %d: prev hash mismatch", bid.ID)
+		}
+		if sealedBidChainHash(prevChainHash, bid) != bid.ChainHash {
+			return fmt.Errorf("chain broken at sealed bid %d: chain hash mismatch", bid.ID)
+		}
+		if bid.Status != models.SealedBidStatusCommitted &&
+			SealedBidCommitHash(bid.AuctionID, bid.WalletAddress, bid.PaymentToken, bid.RawAmount, bid.Salt) != bid.CommitHash {
+			return fmt.Errorf("revealed amount of sealed bid %d does not match its commitment", bid.ID)
+		}
+		prevChainHash = bid.ChainHash
+	}
+	return nil
+}
+
+// getSealedAuction 获取密封拍卖
+func (s *SealedBidService) getSealedAuction(auctionID string) (*models.Auction, error) {
+	var auction models.Auction
+	if err := database.DB.Where("auction_id = ?", auctionID).First(&auction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound("auction not found")
+		}
+		return nil, fmt.Errorf("failed to get auction: %w", err)
+	}
+	if !auction.IsSealed() {
+		return nil, errors.BadRequest("auction is not a sealed-bid auction")
+	}
+	return &auction, nil
+}
+
+// Close 关闭服务并释放资源
+func (s *SealedBidService) Close() error {
+	if s.ethClient != nil {
+		s.ethClient.Close()
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"my-auction-market-api/internal/config"
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	appJWT "my-auction-market-api/internal/jwt"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/redisdb"
+)
+
+// Redis 键（令牌只保存 sha256 哈希）
+const (
+	refreshTokenKeyPrefix     = "auth:refresh:"         // 刷新令牌 -> 会话ID
+	usedRefreshTokenKeyPrefix = "auth:refresh:used:"    // 已轮换的刷新令牌 -> 会话ID，用于检测刷新令牌重放
+	sessionKeyPrefix          = "auth:session:"         // 会话（hash）：user_id、wallet_address、user_agent、ip、created_at、last_seen_at、refresh_hash
+	sessionTouchKeyPrefix     = "auth:session:touch:"   // 会话最近活跃时间更新的节流标记
+	userSessionsKeyPrefix     = "auth:user:sessions:"   // 用户的会话ID集合
+	revokedSessionKeyPrefix   = "auth:revoked:session:" // 已撤销的会话，保留到该会话最后签发的访问令牌过期
+)
+
+const (
+	// sessionTouchInterval 认证请求更新会话最近活跃时间的最小间隔
+	sessionTouchInterval = time.Minute
+	// maxUserAgentLength 会话中保存的 User-Agent 最大长度
+	maxUserAgentLength = 255
+)
+
+// touchSessionScript 会话存在时才更新最近活跃信息，避免重新创建已撤销或过期的会话
+var touchSessionScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("HSET", KEYS[1], "last_seen_at", ARGV[1], "ip", ARGV[2], "user_agent", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// SessionClient 发起登录或请求的客户端信息
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+func (c SessionClient) fields() map[string]interface{} {
+	userAgent := c.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return map[string]interface{}{
+		"user_agent":   userAgent,
+		"ip":           c.IP,
+		"last_seen_at": time.Now().Unix(),
+	}
+}
+
+// TokenService 登录会话与令牌管理
+// 登录时创建会话并签发短期访问令牌（JWT，携带会话ID）和刷新令牌；刷新令牌一次性使用，每次刷新轮换；
+// 登出撤销会话：删除刷新令牌，并将会话ID加入撤销列表使其访问令牌立即失效
+type TokenService struct {
+	redis      *redisdb.Client
+	refreshTTL time.Duration
+}
+
+func NewTokenService(jwtCfg config.JWTConfig, redisClient *redisdb.Client) *TokenService {
+	return &TokenService{
+		redis:      redisClient,
+		refreshTTL: jwtCfg.RefreshExpiration,
+	}
+}
+
+func sessionKey(sessionID string) string {
+	return sessionKeyPrefix + sessionID
+}
+
+func userSessionsKey(userID uint64) string {
+	return userSessionsKeyPrefix + strconv.FormatUint(userID, 10)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IssueTokens 登录成功后创建会话（记录登录钱包和客户端信息）并签发令牌
+func (s *TokenService) IssueTokens(ctx context.Context, user *models.User, walletAddress string, client SessionClient) (*models.TokenPair, error) {
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.HSet(ctx, sessionKey(sessionID), map[string]interface{}{
+		"user_id":        user.ID,
+		"wallet_address": walletAddress,
+		"created_at":     time.Now().Unix(),
+	})
+	pipe.SAdd(ctx, userSessionsKey(user.ID), sessionID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issue(ctx, user, sessionID, client)
+}
+
+// Refresh 使用刷新令牌换取新的令牌（轮换刷新令牌），用户名、角色等以数据库当前值为准
+// 已轮换的刷新令牌再次使用视为泄露，撤销整个会话
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string, client SessionClient) (*models.TokenPair, error) {
+	hash := hashToken(refreshToken)
+
+	sessionID, err := s.redis.GetDel(ctx, refreshTokenKeyPrefix+hash).Result()
+	if err == redis.Nil {
+		usedSessionID, err := s.redis.Get(ctx, usedRefreshTokenKeyPrefix+hash).Result()
+		if err == nil {
+			logger.Warn("refresh token reuse detected, revoking session: sessionID=%s", usedSessionID)
+			if err := s.RevokeSession(ctx, usedSessionID); err != nil {
+				logger.Error("failed to revoke session after refresh token reuse: sessionID=%s, error=%v", usedSessionID, err)
+			}
+		}
+		return nil, errors.ErrUnauthorized.WithMessage("invalid or expired refresh token")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if err := s.redis.Set(ctx, usedRefreshTokenKeyPrefix+hash, sessionID, s.refreshTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to mark refresh token as used: %w", err)
+	}
+
+	session, err := s.redis.HGetAll(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session["user_id"] == "" || session["refresh_hash"] != hash {
+		return nil, errors.ErrUnauthorized.WithMessage("invalid or expired refresh token")
+	}
+	userID, err := strconv.ParseUint(session["user_id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session user id: %w", err)
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, errors.ErrUnauthorized.WithMessage("user not found")
+	}
+	// 暂停、封禁或已注销的账户不再签发令牌（即使状态变更后撤销会话失败）
+	if err := loginStatusError(&user); err != nil {
+		return nil, err
+	}
+
+	return s.issue(ctx, &user, sessionID, client)
+}
+
+// RevokeSession 撤销会话：删除刷新令牌，会话签发的访问令牌在过期前都会被拒绝
+func (s *TokenService) RevokeSession(ctx context.Context, sessionID string) error {
+	session, err := s.redis.HGetAll(ctx, sessionKey(sessionID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, appJWT.Expiration())
+	pipe.Del(ctx, sessionKey(sessionID))
+	if hash := session["refresh_hash"]; hash != "" {
+		pipe.Del(ctx, refreshTokenKeyPrefix+hash)
+	}
+	if userID, err := strconv.ParseUint(session["user_id"], 10, 64); err == nil {
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserSession 用户撤销自己的某个会话
+func (s *TokenService) RevokeUserSession(ctx context.Context, userID uint64, sessionID string) error {
+	owner, err := s.redis.HGet(ctx, sessionKey(sessionID), "user_id").Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if owner != strconv.FormatUint(userID, 10) {
+		return errors.ErrNotFound.WithMessage("session not found")
+	}
+	return s.RevokeSession(ctx, sessionID)
+}
+
+// ListSessions 获取用户的有效会话，按最近活跃时间倒序，currentSessionID 对应的会话标记为当前会话
+// 已过期的会话从用户会话集合中清理
+func (s *TokenService) ListSessions(ctx context.Context, userID uint64, currentSessionID string) ([]models.UserSession, error) {
+	sessionIDs, err := s.redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		cmds[i] = pipe.HGetAll(ctx, sessionKey(sessionID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	sessions := make([]models.UserSession, 0, len(sessionIDs))
+	var stale []interface{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if fields["user_id"] == "" {
+			stale = append(stale, sessionIDs[i])
+			continue
+		}
+		createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+		lastSeenAt, _ := strconv.ParseInt(fields["last_seen_at"], 10, 64)
+		sessions = append(sessions, models.UserSession{
+			ID:            sessionIDs[i],
+			WalletAddress: fields["wallet_address"],
+			UserAgent:     fields["user_agent"],
+			IP:            fields["ip"],
+			CreatedAt:     time.Unix(createdAt, 0),
+			LastSeenAt:    time.Unix(lastSeenAt, 0),
+			Current:       sessionIDs[i] == currentSessionID,
+		})
+	}
+	if len(stale) > 0 {
+		if err := s.redis.SRem(ctx, userSessionsKey(userID), stale...).Err(); err != nil {
+			logger.Warn("failed to remove expired sessions: userID=%d, error=%v", userID, err)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// TouchSession 认证请求时更新会话最近活跃时间和客户端信息，每个会话每 sessionTouchInterval 最多更新一次
+func (s *TokenService) TouchSession(ctx context.Context, sessionID string, userAgent string, ip string) error {
+	ok, err := s.redis.SetNX(ctx, sessionTouchKeyPrefix+sessionID, 1, sessionTouchInterval).Result()
+	if err != nil || !ok {
+		return err
+	}
+	fields := SessionClient{UserAgent: userAgent, IP: ip}.fields()
+	return touchSessionScript.Run(ctx, s.redis, []string{sessionKey(sessionID)},
+		fields["last_seen_at"], fields["ip"], fields["user_agent"]).Err()
+}
+
+// RevokeAllSessions 撤销用户的所有会话（登出所有设备）
+func (s *TokenService) RevokeAllSessions(ctx context.Context, userID uint64) error {
+	sessionIDs, err := s.redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	for _, sessionID := range sessionIDs {
+		if err := s.RevokeSession(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+	if err := s.redis.Del(ctx, userSessionsKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear user sessions: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked 实现 jwt.RevocationChecker：没有会话ID的令牌（旧版本签发）和已撤销会话的令牌均视为无效
+func (s *TokenService) IsRevoked(ctx context.Context, claims *appJWT.Claims) (bool, error) {
+	if claims.SessionID == "" {
+		return true, nil
+	}
+	n, err := s.redis.Exists(ctx, revokedSessionKeyPrefix+claims.SessionID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// issue 为会话签发访问令牌和新的刷新令牌，更新客户端信息并延长会话有效期
+func (s *TokenService) issue(ctx context.Context, user *models.User, sessionID string, client SessionClient) (*models.TokenPair, error) {
+	accessToken, err := appJWT.GenerateToken(user.ID, user.Username, user.EmailAddress(), user.Role, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	hash := hashToken(refreshToken)
+
+	pipe := s.redis.TxPipeline()
+	pipe.Set(ctx, refreshTokenKeyPrefix+hash, sessionID, s.refreshTTL)
+	fields := client.fields()
+	fields["refresh_hash"] = hash
+	pipe.HSet(ctx, sessionKey(sessionID), fields)
+	pipe.Expire(ctx, sessionKey(sessionID), s.refreshTTL)
+	pipe.Expire(ctx, userSessionsKey(user.ID), s.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &models.TokenPair{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(appJWT.Expiration().Seconds()),
+	}, nil
+}
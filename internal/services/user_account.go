@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+const (
+	// userStatusKeyPrefix Redis 中账户状态缓存的键前缀，值为当前生效的状态
+	userStatusKeyPrefix = "auth:user:status:"
+	// userStatusCacheTTL 账户状态缓存时长，状态变更时主动删除缓存
+	userStatusCacheTTL = time.Minute
+	// deletedUsernamePrefix 已注销账户的用户名前缀（保留，用户不能使用）
+	deletedUsernamePrefix = "deleted_"
+)
+
+// usernamePattern 用户名只能包含字母、数字和下划线
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+func userStatusKey(userID uint64) string {
+	return userStatusKeyPrefix + strconv.FormatUint(userID, 10)
+}
+
+// accountStatusError 账户状态对应的错误，正常状态返回 nil
+func accountStatusError(status string) error {
+	switch status {
+	case models.UserStatusSuspended:
+		return errors.Forbidden("account is suspended")
+	case models.UserStatusBanned:
+		return errors.Forbidden("account is banned")
+	case models.UserStatusDeleted:
+		return errors.ErrUnauthorized.WithMessage("account has been deleted")
+	}
+	return nil
+}
+
+// loginStatusError 登录时检查账户状态，返回包含原因和暂停截止时间的错误
+func loginStatusError(user *models.User) error {
+	switch user.EffectiveStatus(time.Now()) {
+	case models.UserStatusSuspended:
+		message := "account is suspended"
+		if user.SuspendedUntil != nil {
+			message += " until " + user.SuspendedUntil.UTC().Format(time.RFC3339)
+		}
+		if user.StatusReason != "" {
+			message += ": " + user.StatusReason
+		}
+		return errors.Forbidden(message)
+	case models.UserStatusBanned:
+		message := "account is banned"
+		if user.StatusReason != "" {
+			message += ": " + user.StatusReason
+		}
+		return errors.Forbidden(message)
+	case models.UserStatusDeleted:
+		return errors.ErrUnauthorized.WithMessage("account has been deleted")
+	}
+	return nil
+}
+
+// CheckAccountStatus 实现 middleware.AccountStatusChecker：暂停、封禁和已注销的账户返回错误
+// 状态缓存在 Redis 中（最长 userStatusCacheTTL，不超过暂停截止时间），修改状态时删除缓存
+func (s *UserService) CheckAccountStatus(ctx context.Context, userID uint64) error {
+	key := userStatusKey(userID)
+	status, err := s.redis.Get(ctx, key).Result()
+	if err == nil {
+		return accountStatusError(status)
+	}
+	if err != redis.Nil {
+		return fmt.Errorf("failed to get account status: %w", err)
+	}
+
+	ttl := userStatusCacheTTL
+	var user models.User
+	if err := database.DB.WithContext(ctx).Select("id", "status", "suspended_until").First(&user, userID).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		status = models.UserStatusDeleted
+	} else {
+		now := time.Now()
+		status = user.EffectiveStatus(now)
+		if status == models.UserStatusSuspended && user.SuspendedUntil != nil && user.SuspendedUntil.Sub(now) < ttl {
+			ttl = user.SuspendedUntil.Sub(now)
+		}
+	}
+
+	if err := s.redis.Set(ctx, key, status, ttl).Err(); err != nil {
+		logger.Warn("failed to cache account status: userID=%d, error=%v", userID, err)
+	}
+	return accountStatusError(status)
+}
+
+// clearAccountStatusCache 删除账户状态缓存，失败只记录日志（缓存最长 userStatusCacheTTL 后过期）
+func (s *UserService) clearAccountStatusCache(userID uint64) {
+	if err := s.redis.Del(context.Background(), userStatusKey(userID)).Err(); err != nil {
+		logger.Warn("failed to clear account status cache: userID=%d, error=%v", userID, err)
+	}
+}
+
+// CompleteOnboarding 新用户完成引导：设置用户名和资料，只能完成一次（之后通过更新资料接口修改）
+func (s *UserService) CompleteOnboarding(userID uint64, payload models.OnboardingPayload) (*models.UserProfile, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.OnboardedAt != nil {
+		return nil, errors.BadRequest("onboarding already completed")
+	}
+
+	updates := map[string]interface{}{
+		"display_name": strings.TrimSpace(payload.DisplayName),
+		"bio":          strings.TrimSpace(payload.Bio),
+		"onboarded_at": time.Now(),
+	}
+	if payload.Username != user.Username {
+		if err := checkUsernameAvailable(userID, payload.Username); err != nil {
+			return nil, err
+		}
+		updates["username"] = payload.Username
+	}
+	avatarURL, err := normalizeAvatarURL(payload.AvatarURL)
+	if err != nil {
+		return nil, err
+	}
+	updates["avatar_url"] = avatarURL
+	if payload.Email != "" {
+		if err := emailChangeUpdates(&user, payload.Email, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch updated user: %w", err)
+	}
+
+	if _, ok := updates["pending_email"].(string); ok {
+		s.sendEmailVerification(user)
+	}
+
+	logger.Info("user onboarded: userID=%d, username=%s", user.ID, user.Username)
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
+}
+
+// sendEmailVerification 异步发送验证邮件，失败只记录日志（用户可以重新请求发送）
+func (s *UserService) sendEmailVerification(user models.User) {
+	if s.emailVerification == nil {
+		return
+	}
+	go func() {
+		if err := s.emailVerification.SendVerification(context.Background(), &user); err != nil {
+			logger.Warn("failed to send verification email: userID=%d, error=%v", user.ID, err)
+		}
+	}()
+}
+
+// checkUsernameAvailable 检查用户名格式及是否被其他用户使用
+func checkUsernameAvailable(userID uint64, username string) error {
+	if !usernamePattern.MatchString(username) {
+		return errors.BadRequest("username may only contain letters, digits and underscores")
+	}
+	if strings.HasPrefix(strings.ToLower(username), deletedUsernamePrefix) {
+		return errors.BadRequest("username is reserved")
+	}
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("username = ? AND id != ?", username, userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("username already exists")
+	}
+	return nil
+}
+
+// emailChangeUpdates 修改邮箱：新邮箱写入 pending_email，验证后才替换 email，未验证的邮箱不占用地址
+// 改回当前邮箱时放弃待验证的新邮箱
+func emailChangeUpdates(user *models.User, email string, updates map[string]interface{}) error {
+	email = normalizeEmail(email)
+	switch email {
+	case user.EmailAddress():
+		if user.PendingEmail != nil {
+			updates["pending_email"] = nil
+		}
+		return nil
+	case user.PendingEmailAddress():
+		return nil
+	}
+	if err := checkEmailAvailable(user.ID, email); err != nil {
+		return err
+	}
+	updates["pending_email"] = email
+	return nil
+}
+
+// checkEmailAvailable 检查邮箱是否被其他用户使用（只检查已生效的邮箱，待验证的邮箱不占用地址）
+func checkEmailAvailable(userID uint64, email string) error {
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("email = ? AND id != ?", email, userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("email already exists")
+	}
+	return nil
+}
+
+// normalizeEmail 规范化邮箱（去除空白并转为小写）
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeAvatarURL 校验头像URL（http 或 https），空字符串表示不设置头像
+func normalizeAvatarURL(avatarURL string) (string, error) {
+	avatarURL = strings.TrimSpace(avatarURL)
+	if avatarURL == "" {
+		return "", nil
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.BadRequest("avatar URL must be an http or https URL")
+	}
+	return avatarURL, nil
+}
+
+// UpdateStatus 运营或管理员修改账户状态（暂停、封禁、恢复）
+// 不能修改自己的状态；运营只能修改普通用户的状态。调用方负责在暂停、封禁后撤销用户的登录会话
+func (s *UserService) UpdateStatus(operatorID uint64, operatorRole string, userID uint64, payload models.UpdateUserStatusPayload) (*models.UserProfile, error) {
+	if operatorID == userID {
+		return nil, errors.BadRequest("cannot change your own status")
+	}
+	var user models.User
+	if err := database.DB.Where("id = ? AND status != ?", userID, models.UserStatusDeleted).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if operatorRole != models.RoleAdmin && user.Role != models.RoleUser {
+		return nil, errors.Forbidden("only admins can change the status of moderators and admins")
+	}
+
+	updates := map[string]interface{}{
+		"status":          payload.Status,
+		"status_reason":   "",
+		"suspended_until": nil,
+	}
+	switch payload.Status {
+	case models.UserStatusSuspended:
+		if payload.SuspendedUntil != nil {
+			if !payload.SuspendedUntil.After(time.Now()) {
+				return nil, errors.BadRequest("suspension end time must be in the future")
+			}
+			updates["suspended_until"] = *payload.SuspendedUntil
+		}
+		updates["status_reason"] = payload.Reason
+	case models.UserStatusBanned:
+		updates["status_reason"] = payload.Reason
+	}
+
+	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update user status: %w", err)
+	}
+	s.clearAccountStatusCache(user.ID)
+	logger.Info("user status changed: userID=%d, from=%s, to=%s, reason=%s, operatorID=%d",
+		user.ID, user.Status, payload.Status, payload.Reason, operatorID)
+
+	if err := database.DB.First(&user, user.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch updated user: %w", err)
+	}
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
+}
+
+// DeleteAccount 注销账户：清除个人信息、解除所有钱包、撤销 API Key，钱包之后可以重新注册新账户
+// 拍卖、出价等交易记录保留（关联到已注销的账户）。有进行中交易时不能注销；调用方负责撤销用户的登录会话
+func (s *UserService) DeleteAccount(userID uint64) error {
+	var wallets []string
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行后再检查进行中的交易，避免检查期间创建的拍卖、出价落到已注销的账户上
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status != ?", userID, models.UserStatusDeleted).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.ErrNotFound.WithMessage("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		var err error
+		if wallets, err = userWalletAddresses(tx, userID); err != nil {
+			return err
+		}
+		for _, wallet := range wallets {
+			if err := checkWalletUnlinkable(tx, userID, wallet); err != nil {
+				return err
+			}
+		}
+
+		// 未处理的报价：撤回自己发出的，拒绝收到的
+		if err := tx.Model(&models.Offer{}).
+			Where("buyer_user_id = ? AND status = ?", userID, models.OfferStatusPending).
+			Update("status", models.OfferStatusCancelled).Error; err != nil {
+			return fmt.Errorf("failed to cancel offers: %w", err)
+		}
+		if err := tx.Model(&models.Offer{}).
+			Where("owner_user_id = ? AND status = ?", userID, models.OfferStatusPending).
+			Update("status", models.OfferStatusRejected).Error; err != nil {
+			return fmt.Errorf("failed to reject offers: %w", err)
+		}
+		// 草稿经状态机取消，记录变更历史
+		var drafts []models.Auction
+		if err := tx.Where("user_id = ? AND status = ?", userID, AuctionStatusDraft).Find(&drafts).Error; err != nil {
+			return fmt.Errorf("failed to get draft auctions: %w", err)
+		}
+		for i := range drafts {
+			if _, err := transitionAuction(tx, &drafts[i], StatusTransition{
+				To:     AuctionStatusCancelled,
+				Actor:  userActor(userID),
+				Cause:  TransitionCauseAPI,
+				Reason: "account_deleted",
+			}); err != nil {
+				return fmt.Errorf("failed to cancel draft auctions: %w", err)
+			}
+		}
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("failed to revoke API keys: %w", err)
+		}
+		for _, model := range []interface{}{&models.UserWallet{}, &models.AuctionWatch{}, &models.AuctionTemplate{}} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete user data: %w", err)
+			}
+		}
+		if err := tx.Where("user_id = ? AND status = ?", userID, models.NFTOwnershipStatusHolding).
+			Delete(&models.NFTOwnership{}).Error; err != nil {
+			return fmt.Errorf("failed to delete NFT ownerships: %w", err)
+		}
+
+		return retireUser(tx, &user, "", now)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.clearAccountStatusCache(userID)
+	logger.Info("user account deleted: userID=%d, wallets=%v", userID, wallets)
+	return nil
+}
+
+// retireUser 清除账户个人信息和主钱包并标记为已注销（注销账户、账户被合并时使用）
+func retireUser(tx *gorm.DB, user *models.User, reason string, now time.Time) error {
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"username":        deletedUsernamePrefix + strconv.FormatUint(user.ID, 10),
+		"email":           nil,
+		"email_verified":  false,
+		"pending_email":   nil,
+		"wallet_address":  "",
+		"display_name":    "",
+		"avatar_url":      "",
+		"bio":             "",
+		"status":          models.UserStatusDeleted,
+		"status_reason":   reason,
+		"suspended_until": nil,
+		"deleted_at":      now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -8,18 +9,58 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 
+	"my-auction-market-api/internal/config"
 	"my-auction-market-api/internal/database"
+	apperrors "my-auction-market-api/internal/errors"
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
 	"my-auction-market-api/internal/logger"
 	"my-auction-market-api/internal/models"
+	"my-auction-market-api/internal/redisdb"
 	"my-auction-market-api/internal/utils"
 )
 
-type UserService struct{}
+const (
+	// siweNonceKeyPrefix Redis 中 SIWE nonce 的键前缀，值为签发时的钱包地址（小写）
+	siweNonceKeyPrefix = "auth:siwe:nonce:"
+	// siweClockSkew 校验签发时间和生效时间时允许的时钟偏差
+	siweClockSkew = time.Minute
+)
+
+type UserService struct {
+	siwe              config.SIWEConfig
+	chainID           int64
+	redis             *redisdb.Client
+	ethClient         *ethclientwrapper.Client
+	verifier          *WalletSignatureVerifier
+	emailVerification *EmailVerificationService
+}
+
+func NewUserService(siweCfg config.SIWEConfig, chainID int64, redisClient *redisdb.Client, ethClient *ethclientwrapper.Client) *UserService {
+	return &UserService{
+		siwe:      siweCfg,
+		chainID:   chainID,
+		redis:     redisClient,
+		ethClient: ethClient,
+		verifier:  NewWalletSignatureVerifier(ethClient),
+	}
+}
+
+// SetEmailVerificationService 设置邮箱验证服务（修改邮箱后发送验证邮件），未设置时不发送
+func (s *UserService) SetEmailVerificationService(emailVerification *EmailVerificationService) {
+	s.emailVerification = emailVerification
+}
 
-func NewUserService() *UserService {
-	return &UserService{}
+// Close 关闭以太坊客户端
+func (s *UserService) Close() error {
+	if s.ethClient != nil {
+		s.ethClient.Close()
+	}
+	return nil
 }
 
 func (s *UserService) Register(payload models.RegisterPayload) (*models.RegisterResult, error) {
@@ -43,7 +84,7 @@ func (s *UserService) Register(payload models.RegisterPayload) (*models.Register
 	// Create user
 	user := models.User{
 		Username:      payload.Username,
-		Email:         payload.Email,
+		Email:         &payload.Email,
 		Password:      string(hashedPassword),
 		WalletAddress: payload.WalletAddress,
 	}
@@ -55,7 +96,7 @@ func (s *UserService) Register(payload models.RegisterPayload) (*models.Register
 	return &models.RegisterResult{
 		ID:            user.ID,
 		Username:      user.Username,
-		Email:         user.Email,
+		Email:         user.EmailAddress(),
 		WalletAddress: user.WalletAddress,
 	}, nil
 }
@@ -81,15 +122,11 @@ func (s *UserService) GetProfile(userID uint64) (*models.UserProfile, error) {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	return &models.UserProfile{
-		ID:            user.ID,
-		Username:      user.Username,
-		Email:         user.Email,
-		WalletAddress: user.WalletAddress,
-	}, nil
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
 }
 
-// UpdateProfile 更新用户信息（用户名和邮箱）
+// UpdateProfile 更新用户信息（用户名、邮箱、昵称、头像和简介）
 func (s *UserService) UpdateProfile(userID uint64, payload models.UpdateProfilePayload) (*models.UserProfile, error) {
 	var user models.User
 	if err := database.DB.First(&user, userID).Error; err != nil {
@@ -99,32 +136,39 @@ func (s *UserService) UpdateProfile(userID uint64, payload models.UpdateProfileP
 	// 构建更新字段
 	updates := make(map[string]interface{})
 
-	// 如果提供了用户名，检查是否与其他用户重复
+	// 如果提供了用户名，检查格式及是否与其他用户重复
 	if payload.Username != "" && payload.Username != user.Username {
-		var existingUser models.User
-		if err := database.DB.Where("username = ? AND id != ?", payload.Username, userID).First(&existingUser).Error; err == nil {
-			return nil, errors.New("username already exists")
+		if err := checkUsernameAvailable(userID, payload.Username); err != nil {
+			return nil, err
 		}
 		updates["username"] = payload.Username
 	}
 
-	// 如果提供了邮箱，检查是否与其他用户重复
-	if payload.Email != "" && payload.Email != user.Email {
-		var existingUser models.User
-		if err := database.DB.Where("email = ? AND id != ?", payload.Email, userID).First(&existingUser).Error; err == nil {
-			return nil, errors.New("email already exists")
+	// 如果提供了邮箱，检查是否与其他用户重复，验证后才生效
+	if payload.Email != "" {
+		if err := emailChangeUpdates(&user, payload.Email, updates); err != nil {
+			return nil, err
 		}
-		updates["email"] = payload.Email
+	}
+
+	if payload.DisplayName != nil {
+		updates["display_name"] = strings.TrimSpace(*payload.DisplayName)
+	}
+	if payload.AvatarURL != nil {
+		avatarURL, err := normalizeAvatarURL(*payload.AvatarURL)
+		if err != nil {
+			return nil, err
+		}
+		updates["avatar_url"] = avatarURL
+	}
+	if payload.Bio != nil {
+		updates["bio"] = strings.TrimSpace(*payload.Bio)
 	}
 
 	// 如果没有要更新的字段，直接返回当前用户信息
 	if len(updates) == 0 {
-		return &models.UserProfile{
-			ID:            user.ID,
-			Username:      user.Username,
-			Email:         user.Email,
-			WalletAddress: user.WalletAddress,
-		}, nil
+		profile := models.NewUserProfile(&user)
+		return &profile, nil
 	}
 
 	// 更新用户信息
@@ -137,146 +181,182 @@ func (s *UserService) UpdateProfile(userID uint64, payload models.UpdateProfileP
 		return nil, fmt.Errorf("failed to fetch updated user: %w", err)
 	}
 
-	return &models.UserProfile{
-		ID:            user.ID,
-		Username:      user.Username,
-		Email:         user.Email,
-		WalletAddress: user.WalletAddress,
-	}, nil
+	// 新邮箱需要验证
+	if _, ok := updates["pending_email"].(string); ok {
+		s.sendEmailVerification(user)
+	}
+
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
 }
 
-// RequestNonce 为钱包地址生成 nonce 和消息
+// RequestNonce 为钱包地址生成 nonce 和 SIWE（EIP-4361）登录消息
+// nonce 存储在 Redis 中并绑定钱包地址，有效期为 siwe.nonce_ttl，验证时一次性消费；签名验证通过前不创建用户
 func (s *UserService) RequestNonce(walletAddress string) (*models.WalletLoginRequestNonceResult, error) {
-	// 规范化地址（小写）
+	walletAddress, err := normalizeWalletAddress(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newSIWEMessage(walletAddress, s.siwe.Statement, siweNonceKeyPrefix, walletAddress)
+}
+
+// VerifyWalletLogin 验证 SIWE 消息和钱包签名并登录，主钱包和已关联钱包都登录到同一账户
+// 钱包首次登录时创建用户（需要完成引导设置资料）；暂停、封禁的账户不能登录
+func (s *UserService) VerifyWalletLogin(payload models.WalletLoginVerifyPayload) (*models.User, error) {
+	walletAddress, err := normalizeWalletAddress(payload.WalletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySIWEMessage(walletAddress, payload.Message, payload.Signature, siweNonceKeyPrefix, walletAddress); err != nil {
+		return nil, err
+	}
+
+	// 查找或创建用户
+	user, err := s.GetOrCreateUserByWalletAddress(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := loginStatusError(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// normalizeWalletAddress 校验并规范化钱包地址（0x 开头的小写地址）
+func normalizeWalletAddress(walletAddress string) (string, error) {
 	walletAddress = strings.ToLower(strings.TrimPrefix(walletAddress, "0x"))
 	if len(walletAddress) != 40 {
-		return nil, errors.New("invalid wallet address")
+		return "", errors.New("invalid wallet address")
 	}
-	walletAddress = "0x" + walletAddress
+	return "0x" + walletAddress, nil
+}
 
+// newSIWEMessage 生成 SIWE 消息（地址使用 EIP-55 校验和格式），nonce 以 keyPrefix+nonce 为键存储在 Redis 中，值为 owner
+func (s *UserService) newSIWEMessage(walletAddress, statement, keyPrefix, owner string) (*models.WalletLoginRequestNonceResult, error) {
 	// 生成随机 nonce
-	nonceBytes := make([]byte, 32)
+	nonceBytes := make([]byte, 16)
 	if _, err := rand.Read(nonceBytes); err != nil {
 		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 	nonce := hex.EncodeToString(nonceBytes)
 
-	// 构建消息（EIP-191 格式）
-	message := fmt.Sprintf("Sign this message to authenticate with My Auction Market: %s", nonce)
-	logger.Info("message: %s", message)
-
-	// 查找或创建用户
-	var user models.User
-	err := database.DB.Where("wallet_address = ?", walletAddress).First(&user).Error
-	if err != nil {
-		// 用户不存在，创建新用户
-		// 生成唯一的用户名和邮箱（使用钱包地址的后8位）
-		addrSuffix := walletAddress[2:10] // 去掉 0x 前缀，取前8位
-		username := fmt.Sprintf("user_%s", addrSuffix)
-		email := fmt.Sprintf("%s@wallet.local", addrSuffix)
-
-		// 确保用户名和邮箱唯一（如果冲突，添加时间戳）
-		var count int64
-		database.DB.Model(&models.User{}).Where("username = ?", username).Count(&count)
-		if count > 0 {
-			username = fmt.Sprintf("user_%s_%d", addrSuffix, time.Now().Unix())
-		}
-		database.DB.Model(&models.User{}).Where("email = ?", email).Count(&count)
-		if count > 0 {
-			email = fmt.Sprintf("%s_%d@wallet.local", addrSuffix, time.Now().Unix())
-		}
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	expirationTime := issuedAt.Add(s.siwe.NonceTTL)
+	message := &utils.SIWEMessage{
+		Domain:         s.siwe.Domain,
+		Address:        common.HexToAddress(walletAddress).Hex(),
+		Statement:      statement,
+		URI:            s.siwe.URI,
+		Version:        utils.SIWEVersion,
+		ChainID:        s.chainID,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expirationTime,
+	}
 
-		user = models.User{
-			Username:      username,
-			Email:         email,
-			Password:      "", // 钱包登录不需要密码，但数据库要求非空，使用空字符串
-			WalletAddress: walletAddress,
-			Nonce:         nonce,
-		}
-		if err := database.DB.Create(&user).Error; err != nil {
-			return nil, fmt.Errorf("failed to create user: %w", err)
-		}
-	} else {
-		// 更新 nonce - 使用 Update 方法明确更新字段
-		if err := database.DB.Model(&user).Update("nonce", nonce).Error; err != nil {
-			return nil, fmt.Errorf("failed to update nonce: %w", err)
-		}
-		// 同时更新内存中的 user 对象，以便后续使用
-		user.Nonce = nonce
+	if err := s.redis.Set(context.Background(), keyPrefix+nonce, owner, s.siwe.NonceTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store nonce: %w", err)
 	}
 
 	return &models.WalletLoginRequestNonceResult{
-		Nonce:   nonce,
-		Message: message,
+		Nonce:          nonce,
+		Message:        message.String(),
+		IssuedAt:       issuedAt.Format(time.RFC3339),
+		ExpirationTime: expirationTime.Format(time.RFC3339),
 	}, nil
 }
 
-// VerifyWalletLogin 验证钱包签名并登录
-func (s *UserService) VerifyWalletLogin(payload models.WalletLoginVerifyPayload) (*models.User, error) {
-	// 规范化地址
-	walletAddress := strings.ToLower(strings.TrimPrefix(payload.WalletAddress, "0x"))
-	if len(walletAddress) != 40 {
-		return nil, errors.New("invalid wallet address")
+// verifySIWEMessage 校验 SIWE 消息并验证签名
+// 校验：消息格式、地址与请求一致、domain/URI/链 ID 与配置一致、版本、签发/生效/过期时间、nonce 存在且属于 owner（一次性消费）
+func (s *UserService) verifySIWEMessage(walletAddress, rawMessage, signature, keyPrefix, owner string) error {
+	message, err := utils.ParseSIWEMessage(rawMessage)
+	if err != nil {
+		return err
 	}
-	walletAddress = "0x" + walletAddress
-
-	// 查找用户
-	var user models.User
-	if err := database.DB.Where("wallet_address = ?", walletAddress).First(&user).Error; err != nil {
-		return nil, errors.New("user not found or invalid wallet address")
+	if strings.ToLower(message.Address) != walletAddress {
+		return errors.New("SIWE message address does not match wallet address")
 	}
-
-	// 验证 nonce（从消息中提取）
-	expectedNonce := strings.TrimPrefix(payload.Message, "Sign this message to authenticate with My Auction Market: ")
-	if user.Nonce == "" || user.Nonce != expectedNonce {
-		return nil, errors.New("invalid or expired nonce")
+	if message.Domain != s.siwe.Domain {
+		return fmt.Errorf("SIWE message domain mismatch: %s", message.Domain)
+	}
+	if message.URI != s.siwe.URI {
+		return fmt.Errorf("SIWE message URI mismatch: %s", message.URI)
+	}
+	if message.ChainID != s.chainID {
+		return fmt.Errorf("SIWE message chain ID mismatch: %d", message.ChainID)
+	}
+	now := time.Now()
+	if message.IssuedAt.After(now.Add(siweClockSkew)) {
+		return errors.New("SIWE message issued in the future")
+	}
+	if message.ExpirationTime == nil || !now.Before(*message.ExpirationTime) {
+		return errors.New("SIWE message has expired")
+	}
+	if message.NotBefore != nil && now.Add(siweClockSkew).Before(*message.NotBefore) {
+		return errors.New("SIWE message is not yet valid")
 	}
 
-	// 验证签名
-	isValid, err := utils.VerifySignature(payload.Message, payload.Signature, walletAddress)
+	// 消费 nonce（GETDEL 保证只能使用一次），nonce 必须是为 owner 签发的
+	nonceOwner, err := s.redis.GetDel(context.Background(), keyPrefix+message.Nonce).Result()
 	if err != nil {
-		return nil, fmt.Errorf("signature verification failed: %w", err)
+		if err == redis.Nil {
+			return errors.New("invalid or expired nonce")
+		}
+		return fmt.Errorf("failed to get nonce: %w", err)
 	}
-	if !isValid {
-		return nil, errors.New("invalid signature")
+	if nonceOwner != owner {
+		return errors.New("invalid or expired nonce")
 	}
 
-	// 清除 nonce（防止重放攻击）- 使用 Update 方法明确更新字段
-	if err := database.DB.Model(&user).Update("nonce", "").Error; err != nil {
-		// 即使清除 nonce 失败，也允许登录（记录日志即可）
-		// 但为了安全，最好还是返回错误
-		return nil, fmt.Errorf("failed to clear nonce: %w", err)
+	// 验证签名（EOA 使用 ECDSA，合约钱包使用 EIP-1271 / ERC-6492）
+	isValid, err := s.verifier.Verify(context.Background(), rawMessage, signature, walletAddress)
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
 	}
-
-	// 可选：设置 nonce 过期时间（例如 5 分钟）
-	// 这里简化处理，nonce 使用后立即清除
-
-	return &user, nil
+	if !isValid {
+		return errors.New("invalid signature")
+	}
+	return nil
 }
 
 // GetOrCreateUserByWalletAddress 根据钱包地址获取或创建用户
+// 新用户使用钱包地址生成的临时用户名，不设置邮箱，完成引导时由用户设置
 func (s *UserService) GetOrCreateUserByWalletAddress(walletAddress string) (*models.User, error) {
-	walletAddress = strings.ToLower(strings.TrimPrefix(walletAddress, "0x"))
-	if len(walletAddress) != 40 {
-		return nil, errors.New("invalid wallet address")
+	walletAddress, err := normalizeWalletAddress(walletAddress)
+	if err != nil {
+		return nil, err
 	}
-	walletAddress = "0x" + walletAddress
 
-	var user models.User
-	err := database.DB.Where("wallet_address = ?", walletAddress).First(&user).Error
-	if err != nil {
-		// 用户不存在，创建新用户
-		user = models.User{
-			Username:      fmt.Sprintf("user_%s", walletAddress[2:10]),
-			Email:         fmt.Sprintf("%s@wallet.local", walletAddress[2:10]),
-			Password:      "",
-			WalletAddress: walletAddress,
-		}
-		if err := database.DB.Create(&user).Error; err != nil {
-			return nil, fmt.Errorf("failed to create user: %w", err)
-		}
+	if user, err := findUserByWallet(database.DB, walletAddress); err == nil {
+		return user, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// 用户不存在，创建新用户（用户名使用钱包地址的前8位，冲突时添加时间戳）
+	username := fmt.Sprintf("user_%s", walletAddress[2:10])
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if count > 0 {
+		username = fmt.Sprintf("user_%s_%d", walletAddress[2:10], time.Now().Unix())
 	}
 
+	user := models.User{
+		Username:      username,
+		Password:      "", // 钱包登录不需要密码，但数据库要求非空，使用空字符串
+		WalletAddress: walletAddress,
+		Role:          models.RoleUser,
+		Status:        models.UserStatusActive,
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	logger.Info("user created on first wallet login: userID=%d, wallet=%s", user.ID, walletAddress)
+
 	return &user, nil
 }
 
@@ -286,8 +366,8 @@ func (s *UserService) GetPlatformStats() (*models.PlatformStatsResponse, error)
 	var totalAuctions int64
 	var totalBids int64
 
-	// 统计总用户数
-	if err := database.DB.Model(&models.User{}).Count(&totalUsers).Error; err != nil {
+	// 统计总用户数（不含已注销账户）
+	if err := database.DB.Model(&models.User{}).Where("status != ?", models.UserStatusDeleted).Count(&totalUsers).Error; err != nil {
 		return nil, fmt.Errorf("failed to count users: %w", err)
 	}
 
@@ -302,9 +382,9 @@ func (s *UserService) GetPlatformStats() (*models.PlatformStatsResponse, error)
 	}
 
 	return &models.PlatformStatsResponse{
-		TotalUsers:   uint64(totalUsers),
+		TotalUsers:    uint64(totalUsers),
 		TotalAuctions: uint64(totalAuctions),
-		TotalBids:    uint64(totalBids),
+		TotalBids:     uint64(totalBids),
 	}, nil
 }
 
@@ -324,5 +404,59 @@ func (s *UserService) GetAllWalletAddresses() ([]string, error) {
 		}
 	}
 
+	// 已关联的钱包
+	var linkedWallets []string
+	if err := database.DB.Model(&models.UserWallet{}).Pluck("wallet_address", &linkedWallets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get linked wallet addresses: %w", err)
+	}
+	walletAddresses = append(walletAddresses, linkedWallets...)
+
 	return walletAddresses, nil
 }
+
+// SeedAdmins 将 admin.wallets 配置中的钱包对应的用户设为管理员（用户不存在时创建），用于初始化第一个管理员
+// 只提升角色，不会降级已从配置中移除的管理员（通过角色接口修改）
+func (s *UserService) SeedAdmins(wallets []string) error {
+	for _, wallet := range wallets {
+		wallet = strings.TrimSpace(wallet)
+		if wallet == "" {
+			continue
+		}
+		user, err := s.GetOrCreateUserByWalletAddress(wallet)
+		if err != nil {
+			return fmt.Errorf("failed to seed admin %s: %w", wallet, err)
+		}
+		if user.Role == models.RoleAdmin {
+			continue
+		}
+		if err := database.DB.Model(user).Update("role", models.RoleAdmin).Error; err != nil {
+			return fmt.Errorf("failed to seed admin %s: %w", wallet, err)
+		}
+		logger.Info("admin role granted from config: userID=%d, wallet=%s", user.ID, user.WalletAddress)
+	}
+	return nil
+}
+
+// UpdateRole 管理员修改用户角色（不能修改自己的角色，避免误降级后没有管理员）
+func (s *UserService) UpdateRole(operatorID uint64, userID uint64, role string) (*models.UserProfile, error) {
+	if operatorID == userID {
+		return nil, apperrors.BadRequest("cannot change your own role")
+	}
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound.WithMessage("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Role != role {
+		if err := database.DB.Model(&user).Update("role", role).Error; err != nil {
+			return nil, fmt.Errorf("failed to update role: %w", err)
+		}
+		logger.Info("user role changed: userID=%d, from=%s, to=%s, operatorID=%d", user.ID, user.Role, role, operatorID)
+		user.Role = role
+	}
+
+	profile := models.NewUserProfile(&user)
+	return &profile, nil
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"my-auction-market-api/internal/database"
+	"my-auction-market-api/internal/errors"
+	"my-auction-market-api/internal/logger"
+	"my-auction-market-api/internal/models"
+)
+
+const (
+	// walletLinkNonceKeyPrefix Redis 中关联钱包 nonce 的键前缀，值为 "用户ID:钱包地址"
+	walletLinkNonceKeyPrefix = "auth:siwe:link:"
+	// walletLinkStatement 关联钱包签名消息中的说明文字
+	walletLinkStatement = "Link this wallet to your account"
+	// maxLinkedWallets 每个用户最多关联的钱包数（不含主钱包）
+	maxLinkedWallets = 10
+)
+
+// findUserByWallet 根据钱包地址（主钱包或已关联钱包）查找用户，未找到时返回 gorm.ErrRecordNotFound
+func findUserByWallet(db *gorm.DB, walletAddress string) (*models.User, error) {
+	walletAddress = strings.ToLower(walletAddress)
+
+	var user models.User
+	err := db.Where("wallet_address = ?", walletAddress).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get user by wallet address: %w", err)
+	}
+
+	var linked models.UserWallet
+	if err := db.Where("wallet_address = ?", walletAddress).First(&linked).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get linked wallet: %w", err)
+	}
+	if err := db.First(&user, linked.UserID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// userWalletAddresses 用户的所有钱包地址（小写），主钱包在前，其后为已关联钱包
+func userWalletAddresses(db *gorm.DB, userID uint64) ([]string, error) {
+	var user models.User
+	if err := db.Select("id", "wallet_address").First(&user, userID).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	var wallets []string
+	if user.WalletAddress != "" {
+		wallets = append(wallets, strings.ToLower(user.WalletAddress))
+	}
+
+	var linked []string
+	if err := db.Model(&models.UserWallet{}).Where("user_id = ?", userID).
+		Order("id ASC").Pluck("wallet_address", &linked).Error; err != nil {
+		return nil, fmt.Errorf("failed to get linked wallets: %w", err)
+	}
+	return append(wallets, linked...), nil
+}
+
+// sellerWalletAddress 卖家上架 NFT 使用的钱包：NFT 由用户某个已关联钱包持有时使用该钱包，否则使用主钱包
+func sellerWalletAddress(db *gorm.DB, user *models.User, nftID string) (string, error) {
+	var ownership models.NFTOwnership
+	err := db.Select("owner_address").Where("user_id = ? AND nft_id = ?", user.ID, nftID).First(&ownership).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("failed to get NFT ownership: %w", err)
+	}
+	if ownership.OwnerAddress != "" {
+		var count int64
+		if err := db.Model(&models.UserWallet{}).
+			Where("user_id = ? AND wallet_address = ?", user.ID, strings.ToLower(ownership.OwnerAddress)).
+			Count(&count).Error; err != nil {
+			return "", fmt.Errorf("failed to check linked wallet: %w", err)
+		}
+		if count > 0 {
+			return strings.ToLower(ownership.OwnerAddress), nil
+		}
+	}
+	return strings.ToLower(user.WalletAddress), nil
+}
+
+// ListWallets 获取用户的钱包（主钱包和已关联钱包）
+func (s *UserService) ListWallets(userID uint64) ([]models.UserWalletResponse, error) {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound.WithMessage("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	wallets := make([]models.UserWalletResponse, 0)
+	if user.WalletAddress != "" {
+		wallets = append(wallets, models.UserWalletResponse{WalletAddress: user.WalletAddress, Primary: true})
+	}
+
+	var linked []models.UserWallet
+	if err := database.DB.Where("user_id = ?", userID).Order("id ASC").Find(&linked).Error; err != nil {
+		return nil, fmt.Errorf("failed to get linked wallets: %w", err)
+	}
+	for _, wallet := range linked {
+		wallets = append(wallets, models.UserWalletResponse{WalletAddress: wallet.WalletAddress, LinkedAt: wallet.CreatedAt})
+	}
+	return wallets, nil
+}
+
+// RequestLinkWalletNonce 为待关联的钱包生成 SIWE 消息，nonce 绑定当前用户和该钱包
+func (s *UserService) RequestLinkWalletNonce(userID uint64, walletAddress string) (*models.WalletLoginRequestNonceResult, error) {
+	walletAddress, err := normalizeWalletAddress(walletAddress)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+	if _, err := checkWalletLinkable(database.DB, userID, walletAddress); err != nil {
+		return nil, err
+	}
+
+	return s.newSIWEMessage(walletAddress, walletLinkStatement, walletLinkNonceKeyPrefix, walletLinkOwner(userID, walletAddress))
+}
+
+// LinkWallet 验证待关联钱包的签名后将钱包关联到当前用户
+// 钱包是另一个账户的主钱包时，签名即证明拥有该账户：该账户的钱包和数据合并到当前用户后注销，
+// 响应中返回被合并的账户ID，调用方负责撤销其登录会话。已关联到其他账户的钱包不能关联
+func (s *UserService) LinkWallet(userID uint64, payload models.LinkWalletPayload) (*models.UserWalletResponse, error) {
+	walletAddress, err := normalizeWalletAddress(payload.WalletAddress)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+	if err := s.verifySIWEMessage(walletAddress, payload.Message, payload.Signature,
+		walletLinkNonceKeyPrefix, walletLinkOwner(userID, walletAddress)); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	var wallet models.UserWallet
+	var source *models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行，避免并发关联超出数量限制
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		var err error
+		if source, err = checkWalletLinkable(tx, userID, walletAddress); err != nil {
+			return err
+		}
+		if source != nil {
+			if err := mergeAccount(tx, source, userID); err != nil {
+				return err
+			}
+		} else if err := tx.Create(&models.UserWallet{UserID: userID, WalletAddress: walletAddress}).Error; err != nil {
+			return fmt.Errorf("failed to link wallet: %w", err)
+		}
+		return tx.Where("wallet_address = ?", walletAddress).First(&wallet).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.UserWalletResponse{WalletAddress: wallet.WalletAddress, LinkedAt: wallet.CreatedAt}
+	if source != nil {
+		s.clearAccountStatusCache(source.ID)
+		result.MergedUserID = source.ID
+		logger.Info("account merged: userID=%d, mergedUserID=%d, wallet=%s", userID, source.ID, walletAddress)
+	} else {
+		logger.Info("wallet linked: userID=%d, wallet=%s", userID, walletAddress)
+	}
+	return result, nil
+}
+
+// UnlinkWallet 解除钱包关联
+// 主钱包不能解除；钱包仍有进行中的拍卖、在售 NFT、作为最高出价者或有未结束的密封出价时不能解除
+// 解除后该钱包持有中的 NFT 从用户的 NFT 列表中移除
+func (s *UserService) UnlinkWallet(userID uint64, walletAddress string) error {
+	walletAddress, err := normalizeWalletAddress(walletAddress)
+	if err != nil {
+		return errors.BadRequest(err.Error())
+	}
+
+	var user models.User
+	if err := database.DB.Select("id", "wallet_address").First(&user, userID).Error; err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if strings.EqualFold(user.WalletAddress, walletAddress) {
+		return errors.BadRequest("primary wallet cannot be unlinked")
+	}
+
+	var wallet models.UserWallet
+	if err := database.DB.Where("user_id = ? AND wallet_address = ?", userID, walletAddress).First(&wallet).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrNotFound.WithMessage("linked wallet not found")
+		}
+		return fmt.Errorf("failed to get linked wallet: %w", err)
+	}
+
+	if err := checkWalletUnlinkable(database.DB, userID, walletAddress); err != nil {
+		return err
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&wallet).Error; err != nil {
+			return fmt.Errorf("failed to unlink wallet: %w", err)
+		}
+		if err := tx.Where("user_id = ? AND owner_address = ? AND status = ?",
+			userID, walletAddress, models.NFTOwnershipStatusHolding).
+			Delete(&models.NFTOwnership{}).Error; err != nil {
+			return fmt.Errorf("failed to remove NFT ownerships of unlinked wallet: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("wallet unlinked: userID=%d, wallet=%s", userID, walletAddress)
+	return nil
+}
+
+func walletLinkOwner(userID uint64, walletAddress string) string {
+	return strconv.FormatUint(userID, 10) + ":" + walletAddress
+}
+
+// checkWalletLinkable 检查钱包是否可以关联到用户，未超过关联数量上限
+// 钱包未被任何账户使用时返回 nil；钱包是另一个账户的主钱包时返回该账户（关联时合并到当前用户），
+// 该账户需为正常状态的普通用户且没有进行中的交易；已关联到其他账户的钱包需先在原账户解除关联
+func checkWalletLinkable(db *gorm.DB, userID uint64, walletAddress string) (*models.User, error) {
+	adding := int64(1)
+	source, err := findUserByWallet(db, walletAddress)
+	if err == nil {
+		if source.ID == userID {
+			return nil, errors.BadRequest("wallet is already linked to your account")
+		}
+		if err := checkAccountMergeable(db, source, userID, walletAddress); err != nil {
+			return nil, err
+		}
+		wallets, err := userWalletAddresses(db, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		adding = int64(len(wallets))
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var count int64
+	if err := db.Model(&models.UserWallet{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count linked wallets: %w", err)
+	}
+	if count+adding > maxLinkedWallets {
+		return nil, errors.BadRequest(fmt.Sprintf("at most %d wallets can be linked", maxLinkedWallets))
+	}
+	return source, nil
+}
+
+// checkAccountMergeable 检查钱包所属的账户是否可以合并到用户
+func checkAccountMergeable(db *gorm.DB, source *models.User, userID uint64, walletAddress string) error {
+	if !strings.EqualFold(source.WalletAddress, walletAddress) {
+		return errors.BadRequest("wallet is linked to another account, unlink it from that account first")
+	}
+	if source.Role != models.RoleUser {
+		return errors.BadRequest("accounts with a moderator or admin role cannot be merged")
+	}
+	if source.EffectiveStatus(time.Now()) != models.UserStatusActive {
+		return errors.BadRequest("wallet belongs to a suspended or banned account")
+	}
+
+	wallets, err := userWalletAddresses(db, source.ID)
+	if err != nil {
+		return err
+	}
+	for _, wallet := range wallets {
+		if err := checkWalletUnlinkable(db, source.ID, wallet); err != nil {
+			return err
+		}
+	}
+
+	// 同一场密封拍卖每个用户只能有一个出价，两个账户都出过价时无法合并
+	var count int64
+	if err := db.Model(&models.SealedBid{}).
+		Where("user_id = ? AND auction_id IN (?)", source.ID,
+			db.Model(&models.SealedBid{}).Select("auction_id").Where("user_id = ?", userID)).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check sealed bids: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("both accounts have sealed bids in the same auction")
+	}
+	return nil
+}
+
+// mergeAccount 将钱包所属账户合并到用户：钱包、NFT、拍卖、出价、报价、模板、关注和白名单成员转移到用户，
+// 撤销该账户的 API Key 后注销该账户（需在事务中调用）
+func mergeAccount(tx *gorm.DB, source *models.User, userID uint64) error {
+	// 锁定被合并的账户，避免同时合并到多个账户
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status != ?", source.ID, models.UserStatusDeleted).First(source).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.BadRequest("wallet no longer belongs to another account")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	wallets, err := userWalletAddresses(tx, source.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := retireUser(tx, source, fmt.Sprintf("merged into user %d", userID), now); err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", source.ID).Delete(&models.UserWallet{}).Error; err != nil {
+		return fmt.Errorf("failed to remove wallets of merged account: %w", err)
+	}
+	for _, wallet := range wallets {
+		if err := tx.Create(&models.UserWallet{UserID: userID, WalletAddress: wallet}).Error; err != nil {
+			return fmt.Errorf("failed to link wallet: %w", err)
+		}
+	}
+
+	// 唯一索引冲突的记录：NFT 由合并账户持有时保留其记录，否则保留用户已有的记录；关注和白名单成员保留用户已有的记录
+	var heldNFTIDs, staleNFTIDs []string
+	if err := tx.Model(&models.NFTOwnership{}).
+		Where("user_id = ? AND nft_id IN (?)", source.ID,
+			tx.Model(&models.NFTOwnership{}).Select("nft_id").Where("user_id = ?", userID)).
+		Where("status = ?", models.NFTOwnershipStatusHolding).
+		Pluck("nft_id", &heldNFTIDs).Error; err != nil {
+		return fmt.Errorf("failed to get NFT ownerships: %w", err)
+	}
+	if err := tx.Model(&models.NFTOwnership{}).
+		Where("user_id = ? AND nft_id IN (?)", source.ID,
+			tx.Model(&models.NFTOwnership{}).Select("nft_id").Where("user_id = ?", userID)).
+		Where("status != ?", models.NFTOwnershipStatusHolding).
+		Pluck("nft_id", &staleNFTIDs).Error; err != nil {
+		return fmt.Errorf("failed to get NFT ownerships: %w", err)
+	}
+	if len(heldNFTIDs) > 0 {
+		if err := tx.Where("user_id = ? AND nft_id IN ?", userID, heldNFTIDs).Delete(&models.NFTOwnership{}).Error; err != nil {
+			return fmt.Errorf("failed to delete NFT ownerships: %w", err)
+		}
+	}
+	if len(staleNFTIDs) > 0 {
+		if err := tx.Where("user_id = ? AND nft_id IN ?", source.ID, staleNFTIDs).Delete(&models.NFTOwnership{}).Error; err != nil {
+			return fmt.Errorf("failed to delete NFT ownerships: %w", err)
+		}
+	}
+
+	var watchedAuctionIDs []string
+	if err := tx.Model(&models.AuctionWatch{}).Where("user_id = ?", userID).Pluck("auction_id", &watchedAuctionIDs).Error; err != nil {
+		return fmt.Errorf("failed to get auction watches: %w", err)
+	}
+	if len(watchedAuctionIDs) > 0 {
+		if err := tx.Where("user_id = ? AND auction_id IN ?", source.ID, watchedAuctionIDs).Delete(&models.AuctionWatch{}).Error; err != nil {
+			return fmt.Errorf("failed to delete auction watches: %w", err)
+		}
+	}
+
+	var members []models.AuctionAllowlistEntry
+	if err := tx.Select("auction_id", "wallet_address").Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return fmt.Errorf("failed to get allowlist members: %w", err)
+	}
+	for _, member := range members {
+		if err := tx.Where("user_id = ? AND auction_id = ? AND wallet_address = ?", source.ID, member.AuctionID, member.WalletAddress).
+			Delete(&models.AuctionAllowlistEntry{}).Error; err != nil {
+			return fmt.Errorf("failed to delete allowlist members: %w", err)
+		}
+	}
+
+	for _, model := range []interface{}{
+		&models.NFTOwnership{}, &models.NFT{}, &models.Auction{}, &models.Bid{}, &models.SealedBid{},
+		&models.AuctionTemplate{}, &models.AuctionWatch{}, &models.AuctionAllowlistEntry{},
+	} {
+		if err := tx.Model(model).Where("user_id = ?", source.ID).Update("user_id", userID).Error; err != nil {
+			return fmt.Errorf("failed to move user data: %w", err)
+		}
+	}
+	for _, column := range []string{"owner_user_id", "buyer_user_id"} {
+		if err := tx.Model(&models.Offer{}).Where(column+" = ?", source.ID).Update(column, userID).Error; err != nil {
+			return fmt.Errorf("failed to move offers: %w", err)
+		}
+	}
+	if err := tx.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", source.ID).
+		Update("revoked_at", now).Error; err != nil {
+		return fmt.Errorf("failed to revoke API keys: %w", err)
+	}
+	return nil
+}
+
+// checkWalletUnlinkable 检查钱包是否可以解除关联（没有依赖该钱包的进行中交易）
+func checkWalletUnlinkable(db *gorm.DB, userID uint64, walletAddress string) error {
+	var count int64
+	if err := db.Model(&models.Auction{}).
+		Where("user_id = ? AND owner_address = ? AND status IN ?", userID, walletAddress,
+			[]string{AuctionStatusPending, AuctionStatusUpcoming, AuctionStatusActive}).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check auctions: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("wallet has auctions in progress")
+	}
+
+	if err := db.Model(&models.NFTOwnership{}).
+		Where("user_id = ? AND owner_address = ? AND status = ?", userID, walletAddress, models.NFTOwnershipStatusSelling).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check NFT ownerships: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("wallet has NFTs listed for sale")
+	}
+
+	if err := db.Model(&models.Auction{}).
+		Where("highest_bidder = ? AND status IN ?", walletAddress,
+			[]string{AuctionStatusUpcoming, AuctionStatusActive}).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check bids: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("wallet is the highest bidder of auctions in progress")
+	}
+
+	if err := db.Model(&models.SealedBid{}).
+		Joins("JOIN auctions ON auctions.auction_id = sealed_bids.auction_id").
+		Where("sealed_bids.wallet_address = ? AND auctions.status IN ?", walletAddress,
+			[]string{AuctionStatusUpcoming, AuctionStatusActive}).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check sealed bids: %w", err)
+	}
+	if count > 0 {
+		return errors.BadRequest("wallet has sealed bids in auctions in progress")
+	}
+	return nil
+}
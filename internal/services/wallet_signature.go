@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
+	"my-auction-market-api/internal/utils"
+)
+
+var (
+	// erc1271MagicValue isValidSignature(bytes32,bytes) 验证通过时的返回值
+	erc1271MagicValue = common.FromHex("0x1626ba7e")
+	// erc6492MagicSuffix ERC-6492 包装签名的结尾标识
+	erc6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+	// erc6492ValidatorBytecode 无需部署的签名验证合约（eth_call 以创建合约方式执行）
+	// 构造参数紧跟在字节码之后：factory(32) | account(32) | len(factoryCalldata)(32) | len(validateCalldata)(32) | factoryCalldata | validateCalldata
+	// 逻辑：将构造参数复制到内存；account 无代码时先调用 factory 部署钱包（忽略失败）；
+	// 再 staticcall account.isValidSignature，成功时返回结果的前 32 字节，失败时 revert
+	//
+	//	PUSH2 0x004a CODESIZE SUB PUSH2 0x004a PUSH1 0 CODECOPY
+	//	PUSH1 0x20 MLOAD EXTCODESIZE PUSH2 0x0024 JUMPI
+	//	PUSH1 0 PUSH1 0 PUSH1 0x40 MLOAD PUSH1 0x80 PUSH1 0 PUSH1 0 MLOAD GAS CALL POP
+	//	0x0024: JUMPDEST PUSH1 0 PUSH1 0 MSTORE
+	//	PUSH1 0x20 PUSH1 0 PUSH1 0x60 MLOAD PUSH1 0x40 MLOAD PUSH1 0x80 ADD PUSH1 0x20 MLOAD GAS STATICCALL
+	//	PUSH2 0x0044 JUMPI PUSH1 0 DUP1 REVERT
+	//	0x0044: JUMPDEST PUSH1 0x20 PUSH1 0 RETURN
+	erc6492ValidatorBytecode = common.FromHex("0x61004a380361004a6000396020513b6100245760006000604051608060006000515af1505b6000600052602060006060516040516080016020515afa61004457600080fd5b60206000f3")
+
+	erc1271ABI = mustParseABI(`[{"type":"function","name":"isValidSignature","stateMutability":"view","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"magicValue","type":"bytes4"}]}]`)
+
+	// erc6492SignatureArgs ERC-6492 包装签名的内容：abi.encode(factory, factoryCalldata, signature)
+	erc6492SignatureArgs = abi.Arguments{
+		{Type: mustNewABIType("address")},
+		{Type: mustNewABIType("bytes")},
+		{Type: mustNewABIType("bytes")},
+	}
+)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func mustNewABIType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// WalletSignatureVerifier 验证钱包对 personal_sign 消息的签名，支持：
+//   - EOA：ECDSA 恢复地址
+//   - 合约钱包（Safe 等多签）：EIP-1271 isValidSignature
+//   - 未部署的合约钱包（counterfactual）：ERC-6492 包装签名，通过无需部署的验证合约模拟部署后验证
+type WalletSignatureVerifier struct {
+	ethClient *ethclientwrapper.Client
+}
+
+func NewWalletSignatureVerifier(ethClient *ethclientwrapper.Client) *WalletSignatureVerifier {
+	return &WalletSignatureVerifier{ethClient: ethClient}
+}
+
+// Verify 按 ERC-6492 规定的顺序验证签名：包装签名 -> 地址上有代码时走 EIP-1271 -> ECDSA
+func (v *WalletSignatureVerifier) Verify(ctx context.Context, message, signature, walletAddress string) (bool, error) {
+	sig := common.FromHex(signature)
+	if len(sig) == 0 {
+		return false, errors.New("invalid signature")
+	}
+	account := common.HexToAddress(walletAddress)
+	hash := accounts.TextHash([]byte(message))
+
+	if bytes.HasSuffix(sig, erc6492MagicSuffix) {
+		return v.verifyERC6492(ctx, account, hash, sig[:len(sig)-len(erc6492MagicSuffix)])
+	}
+
+	code, err := v.ethClient.CodeAt(ctx, account)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code at wallet address: %w", err)
+	}
+	if len(code) > 0 {
+		valid, err := v.verifyERC1271(ctx, account, hash, sig)
+		if err != nil || valid || len(sig) != 65 {
+			return valid, err
+		}
+		// 有代码但合约不认可且签名为 65 字节（如 EIP-7702 委托的 EOA），继续尝试 ECDSA
+	}
+
+	return utils.VerifySignature(message, signature, walletAddress)
+}
+
+// verifyERC1271 调用已部署合约钱包的 isValidSignature
+func (v *WalletSignatureVerifier) verifyERC1271(ctx context.Context, account common.Address, hash []byte, sig []byte) (bool, error) {
+	data, err := erc1271ABI.Pack("isValidSignature", common.BytesToHash(hash), sig)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature call: %w", err)
+	}
+	result, err := v.ethClient.CallContract(ctx, ethereum.CallMsg{To: &account, Data: data}, nil)
+	return isERC1271MagicValue(result, err)
+}
+
+// verifyERC6492 验证 ERC-6492 包装签名：钱包已部署时直接用内层签名走 EIP-1271，
+// 否则用 erc6492ValidatorBytecode 在一次 eth_call 中模拟 factory 部署并验证
+func (v *WalletSignatureVerifier) verifyERC6492(ctx context.Context, account common.Address, hash []byte, wrapped []byte) (bool, error) {
+	factory, factoryCalldata, innerSig, err := decodeERC6492Signature(wrapped)
+	if err != nil {
+		return false, err
+	}
+
+	code, err := v.ethClient.CodeAt(ctx, account)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code at wallet address: %w", err)
+	}
+	if len(code) > 0 {
+		return v.verifyERC1271(ctx, account, hash, innerSig)
+	}
+
+	validateCalldata, err := erc1271ABI.Pack("isValidSignature", common.BytesToHash(hash), innerSig)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature call: %w", err)
+	}
+	data := make([]byte, 0, len(erc6492ValidatorBytecode)+128+len(factoryCalldata)+len(validateCalldata))
+	data = append(data, erc6492ValidatorBytecode...)
+	data = append(data, common.LeftPadBytes(factory.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(account.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(factoryCalldata))).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(validateCalldata))).Bytes(), 32)...)
+	data = append(data, factoryCalldata...)
+	data = append(data, validateCalldata...)
+
+	result, err := v.ethClient.CallContract(ctx, ethereum.CallMsg{Data: data}, nil)
+	return isERC1271MagicValue(result, err)
+}
+
+// decodeERC6492Signature 解析去掉结尾标识后的 ERC-6492 包装签名，返回 factory、factory 调用数据和内层签名
+func decodeERC6492Signature(wrapped []byte) (common.Address, []byte, []byte, error) {
+	values, err := erc6492SignatureArgs.Unpack(wrapped)
+	if err != nil {
+		return common.Address{}, nil, nil, fmt.Errorf("invalid ERC-6492 signature: %w", err)
+	}
+	return values[0].(common.Address), values[1].([]byte), values[2].([]byte), nil
+}
+
+// isERC1271MagicValue 判断 isValidSignature 调用结果，合约 revert 视为签名无效，其他错误（如 RPC 不可用）原样返回
+func isERC1271MagicValue(result []byte, err error) (bool, error) {
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to call isValidSignature: %w", err)
+	}
+	return len(result) >= 4 && bytes.Equal(result[:4], erc1271MagicValue), nil
+}
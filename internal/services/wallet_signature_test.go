@@ -0,0 +1,267 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"my-auction-market-api/internal/config"
+	ethclientwrapper "my-auction-market-api/internal/ethereum"
+)
+
+func TestIsERC1271MagicValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  []byte
+		err     error
+		want    bool
+		wantErr bool
+	}{
+		{name: "abi encoded magic value", result: common.RightPadBytes(erc1271MagicValue, 32), want: true},
+		{name: "bare magic value", result: erc1271MagicValue, want: true},
+		{name: "zero word", result: make([]byte, 32)},
+		{name: "other selector", result: common.RightPadBytes(common.FromHex("0xffffffff"), 32)},
+		{name: "magic value right aligned", result: common.LeftPadBytes(erc1271MagicValue, 32)},
+		{name: "short result", result: erc1271MagicValue[:3]},
+		{name: "empty result"},
+		{name: "reverted", err: errors.New("execution reverted"), want: false},
+		{name: "reverted with reason", err: errors.New("execution reverted: invalid signer"), want: false},
+		{name: "rpc error", err: errors.New("connection refused"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isERC1271MagicValue(tt.result, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isERC1271MagicValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("isERC1271MagicValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeERC6492Signature(t *testing.T) {
+	factory := common.HexToAddress("0x00000000000000000000000000000000000000fa")
+	factoryCalldata := common.FromHex("0x5fbfb9cf000000000000000000000000000000000000000000000000000000000000002a")
+	innerSig := append(make([]byte, 64), 27)
+
+	encoded, err := erc6492SignatureArgs.Pack(factory, factoryCalldata, innerSig)
+	if err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+	empty, err := erc6492SignatureArgs.Pack(factory, []byte{}, []byte{})
+	if err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		wrapped      []byte
+		wantCalldata []byte
+		wantInnerSig []byte
+		wantErr      bool
+	}{
+		{name: "valid", wrapped: encoded, wantCalldata: factoryCalldata, wantInnerSig: innerSig},
+		{name: "empty calldata and signature", wrapped: empty, wantCalldata: []byte{}, wantInnerSig: []byte{}},
+		{name: "truncated", wrapped: encoded[:len(encoded)-32], wantErr: true},
+		{name: "head only", wrapped: encoded[:96], wantErr: true},
+		{name: "raw ecdsa signature", wrapped: innerSig, wantErr: true},
+		{name: "empty", wrapped: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFactory, gotCalldata, gotInnerSig, err := decodeERC6492Signature(tt.wrapped)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeERC6492Signature() = %s, %x, %x, want error", gotFactory.Hex(), gotCalldata, gotInnerSig)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeERC6492Signature() error = %v", err)
+			}
+			if gotFactory != factory || !bytes.Equal(gotCalldata, tt.wantCalldata) || !bytes.Equal(gotInnerSig, tt.wantInnerSig) {
+				t.Errorf("decodeERC6492Signature() = %s, %x, %x, want %s, %x, %x",
+					gotFactory.Hex(), gotCalldata, gotInnerSig, factory.Hex(), tt.wantCalldata, tt.wantInnerSig)
+			}
+		})
+	}
+}
+
+// TestWalletSignatureVerifierEVM 在内存 EVM 上验证 EIP-1271、ERC-6492（erc6492ValidatorBytecode）和 ECDSA 回退
+func TestWalletSignatureVerifierEVM(t *testing.T) {
+	const message = "Sign in to My Auction Market"
+	hash := common.BytesToHash(accounts.TextHash([]byte(message)))
+
+	eoaKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	delegatedKey, _ := crypto.GenerateKey()
+	eoa := crypto.PubkeyToAddress(eoaKey.PublicKey)
+	delegated := crypto.PubkeyToAddress(delegatedKey.PublicKey)
+
+	// wallet 只认可 hash 的合约钱包；delegated 是部署了拒绝所有签名的代码的 EOA（类似 EIP-7702 委托）
+	wallet := common.HexToAddress("0x0000000000000000000000000000000000001271")
+	factory := common.HexToAddress("0x0000000000000000000000000000000000006492")
+	// 合约账户的 nonce 从 1 开始，factory 第一次 CREATE 部署的地址即未部署钱包的地址
+	counterfactual := crypto.CreateAddress(factory, 1)
+	walletCode := erc1271WalletRuntime(hash)
+
+	chain := newTestEVMChain(t, map[common.Address][]byte{
+		wallet:    walletCode,
+		delegated: erc1271WalletRuntime(common.Hash{}),
+		factory:   createFactoryRuntime(deployInitCode(walletCode)),
+	})
+	verifier := NewWalletSignatureVerifier(ethclientwrapper.NewClientFromEthClient(chain, config.EthereumConfig{}))
+
+	signWith := func(t *testing.T, msg string, key *ecdsa.PrivateKey) string {
+		t.Helper()
+		sig, err := crypto.Sign(accounts.TextHash([]byte(msg)), key)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		sig[64] += 27
+		return hexutil.Encode(sig)
+	}
+	wrap := func(t *testing.T, factory common.Address, factoryCalldata []byte, innerSig string) string {
+		t.Helper()
+		encoded, err := erc6492SignatureArgs.Pack(factory, factoryCalldata, common.FromHex(innerSig))
+		if err != nil {
+			t.Fatalf("Pack() error = %v", err)
+		}
+		return hexutil.Encode(append(encoded, erc6492MagicSuffix...))
+	}
+	contractSig := "0x" + common.Bytes2Hex(make([]byte, 130))
+	deploy := common.FromHex("0x00")
+
+	tests := []struct {
+		name      string
+		message   string
+		signature string
+		wallet    common.Address
+		want      bool
+		wantErr   bool
+	}{
+		{name: "eoa", message: message, signature: signWith(t, message, eoaKey), wallet: eoa, want: true},
+		{name: "eoa wrong signer", message: message, signature: signWith(t, message, otherKey), wallet: eoa},
+		{name: "eip-1271 valid", message: message, signature: contractSig, wallet: wallet, want: true},
+		{name: "eip-1271 wrong message", message: "other message", signature: contractSig, wallet: wallet},
+		{name: "eip-1271 rejected falls back to ecdsa", message: message, signature: signWith(t, message, delegatedKey), wallet: delegated, want: true},
+		{name: "eip-1271 rejected and ecdsa wrong signer", message: message, signature: signWith(t, message, otherKey), wallet: delegated},
+		{name: "erc-6492 undeployed wallet", message: message, signature: wrap(t, factory, deploy, contractSig), wallet: counterfactual, want: true},
+		{name: "erc-6492 undeployed wallet wrong message", message: "other message", signature: wrap(t, factory, deploy, contractSig), wallet: counterfactual},
+		{name: "erc-6492 factory does not deploy", message: message, signature: wrap(t, eoa, deploy, contractSig), wallet: counterfactual},
+		{name: "erc-6492 deployed wallet", message: message, signature: wrap(t, factory, deploy, contractSig), wallet: wallet, want: true},
+		{name: "erc-6492 malformed", message: message, signature: hexutil.Encode(append(common.FromHex("0x1234"), erc6492MagicSuffix...)), wallet: counterfactual, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(context.Background(), tt.message, tt.signature, tt.wallet.Hex())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// erc1271WalletRuntime 合约钱包运行时代码：isValidSignature 的 hash 参数等于 accepted 时返回 magic value，否则返回 0
+func erc1271WalletRuntime(accepted common.Hash) []byte {
+	code := []byte{byte(vm.PUSH1), 0x04, byte(vm.CALLDATALOAD), byte(vm.PUSH32)}
+	code = append(code, accepted.Bytes()...)
+	code = append(code, byte(vm.EQ), byte(vm.PUSH1), 50, byte(vm.JUMPI),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+		byte(vm.JUMPDEST), byte(vm.PUSH4))
+	code = append(code, erc1271MagicValue...)
+	return append(code, byte(vm.PUSH1), 0xe0, byte(vm.SHL), byte(vm.PUSH1), 0x00, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.RETURN))
+}
+
+// deployInitCode 返回部署 runtime 的创建代码
+func deployInitCode(runtime []byte) []byte {
+	code := []byte{
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.PUSH1), 12, byte(vm.PUSH1), 0x00, byte(vm.CODECOPY),
+		byte(vm.PUSH1), byte(len(runtime)), byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+	}
+	return append(code, runtime...)
+}
+
+// createFactoryRuntime 工厂合约运行时代码：每次调用都用 initCode 执行一次 CREATE
+func createFactoryRuntime(initCode []byte) []byte {
+	code := []byte{
+		byte(vm.PUSH1), byte(len(initCode)), byte(vm.PUSH1), 16, byte(vm.PUSH1), 0x00, byte(vm.CODECOPY),
+		byte(vm.PUSH1), byte(len(initCode)), byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.CREATE),
+		byte(vm.POP), byte(vm.STOP),
+	}
+	return append(code, initCode...)
+}
+
+// testEVMChain 通过进程内 JSON-RPC 提供 eth_getCode 和 eth_call，调用在内存 EVM 的状态副本上执行
+type testEVMChain struct {
+	state *state.StateDB
+}
+
+type testCallArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// newTestEVMChain 部署 contracts（地址 -> 运行时代码）并返回连接到该链的 ethclient
+func newTestEVMChain(t *testing.T, contracts map[common.Address][]byte) *ethclient.Client {
+	t.Helper()
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatalf("state.New() error = %v", err)
+	}
+	for address, code := range contracts {
+		statedb.SetCode(address, code)
+		// 合约账户的 nonce 从 1 开始（EIP-161）
+		statedb.SetNonce(address, 1)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &testEVMChain{state: statedb}); err != nil {
+		t.Fatalf("RegisterName() error = %v", err)
+	}
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return client
+}
+
+func (c *testEVMChain) GetCode(address common.Address, block string) hexutil.Bytes {
+	return c.state.GetCode(address)
+}
+
+func (c *testEVMChain) Call(args testCallArgs, block string) (hexutil.Bytes, error) {
+	cfg := &runtime.Config{State: c.state.Copy(), GasLimit: 30_000_000}
+	var (
+		ret []byte
+		err error
+	)
+	if args.To == nil {
+		ret, _, _, err = runtime.Create(args.Input, cfg)
+	} else {
+		ret, _, err = runtime.Call(*args.To, args.Input, cfg)
+	}
+	return ret, err
+}
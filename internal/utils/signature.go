@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // VerifySignature 验证以太坊签名
@@ -63,6 +64,43 @@ func VerifySignature(message, signature, expectedAddress string) (bool, error) {
 	return true, nil
 }
 
+// VerifyTypedDataSignature 验证 EIP-712 结构化数据签名（eth_signTypedData_v4）
+// typedData: 结构化数据（包含 domain、types、primaryType、message）
+// signature: 签名（十六进制字符串，0x开头）
+// expectedAddress: 期望的地址
+func VerifyTypedDataSignature(typedData apitypes.TypedData, signature, expectedAddress string) (bool, error) {
+	sig := strings.TrimPrefix(signature, "0x")
+	if len(sig) != 130 {
+		return false, errors.New("invalid signature length")
+	}
+
+	sigBytes, err := hex.DecodeString(sig)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	// 计算 EIP-712 摘要: keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	v := sigBytes[64]
+	if v < 27 {
+		v += 27
+	}
+	sigBytes[64] = v - 27
+
+	pubKey, err := crypto.SigToPub(digest, sigBytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to recover public key: %w", err)
+	}
+
+	recoveredAddress := crypto.PubkeyToAddress(*pubKey)
+	expectedAddr := common.HexToAddress(expectedAddress)
+	return strings.EqualFold(recoveredAddress.Hex(), expectedAddr.Hex()), nil
+}
+
 // VerifySignatureWithPublicKey 使用公钥验证签名（备用方法）
 func VerifySignatureWithPublicKey(message, signature string, publicKey *ecdsa.PublicKey) (bool, error) {
 	// 移除签名前缀
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SIWE（Sign-In with Ethereum，EIP-4361）登录消息
+// 格式：
+//
+//	${domain} wants you to sign in with your Ethereum account:
+//	${address}
+//
+//	${statement}
+//
+//	URI: ${uri}
+//	Version: 1
+//	Chain ID: ${chainId}
+//	Nonce: ${nonce}
+//	Issued At: ${issuedAt}
+//	Expiration Time: ${expirationTime}
+//	Not Before: ${notBefore}
+//	Request ID: ${requestId}
+//	Resources:
+//	- ${resource}
+//
+// statement 及 Expiration Time 之后的字段均为可选
+type SIWEMessage struct {
+	Domain         string
+	Address        string // EIP-55 校验和地址
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+const (
+	siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+	// SIWEVersion 当前支持的 SIWE 消息版本
+	SIWEVersion = "1"
+)
+
+var siweNoncePattern = regexp.MustCompile(`^[a-zA-Z0-9]{8,}$`)
+
+// String 按 EIP-4361 格式生成待签名消息
+func (m *SIWEMessage) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address + "\n")
+	b.WriteString("\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatInt(m.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// ParseSIWEMessage 严格解析 EIP-4361 消息：字段顺序、必填字段、地址校验和、nonce 和时间格式都必须符合规范
+func ParseSIWEMessage(message string) (*SIWEMessage, error) {
+	lines := strings.Split(message, "\n")
+	next := 0
+	line := func() (string, bool) {
+		if next >= len(lines) {
+			return "", false
+		}
+		next++
+		return lines[next-1], true
+	}
+
+	m := &SIWEMessage{}
+
+	header, ok := line()
+	if !ok || !strings.HasSuffix(header, siweHeaderSuffix) {
+		return nil, errors.New("invalid SIWE message: missing header")
+	}
+	m.Domain = strings.TrimSuffix(header, siweHeaderSuffix)
+	if m.Domain == "" || strings.ContainsAny(m.Domain, " \t") {
+		return nil, errors.New("invalid SIWE message: invalid domain")
+	}
+
+	address, ok := line()
+	if !ok || !common.IsHexAddress(address) || common.HexToAddress(address).Hex() != address {
+		return nil, errors.New("invalid SIWE message: address must be an EIP-55 checksummed address")
+	}
+	m.Address = address
+
+	if blank, ok := line(); !ok || blank != "" {
+		return nil, errors.New("invalid SIWE message: expected blank line after address")
+	}
+	statement, ok := line()
+	if !ok {
+		return nil, errors.New("invalid SIWE message: unexpected end of message")
+	}
+	if statement != "" {
+		if strings.HasPrefix(statement, "URI: ") {
+			return nil, errors.New("invalid SIWE message: expected blank line before URI")
+		}
+		m.Statement = statement
+		if blank, ok := line(); !ok || blank != "" {
+			return nil, errors.New("invalid SIWE message: expected blank line after statement")
+		}
+	}
+
+	field := func(name string, required bool) (string, error) {
+		if next >= len(lines) || !strings.HasPrefix(lines[next], name+": ") {
+			if required {
+				return "", fmt.Errorf("invalid SIWE message: missing %s", name)
+			}
+			return "", nil
+		}
+		value := strings.TrimPrefix(lines[next], name+": ")
+		next++
+		if value == "" {
+			return "", fmt.Errorf("invalid SIWE message: empty %s", name)
+		}
+		return value, nil
+	}
+	timeField := func(name string, required bool) (*time.Time, error) {
+		value, err := field(name, required)
+		if err != nil || value == "" {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SIWE message: %s is not an RFC 3339 timestamp", name)
+		}
+		return &t, nil
+	}
+
+	var err error
+	if m.URI, err = field("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = field("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != SIWEVersion {
+		return nil, fmt.Errorf("invalid SIWE message: unsupported version %s", m.Version)
+	}
+	chainID, err := field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil || m.ChainID <= 0 {
+		return nil, errors.New("invalid SIWE message: invalid chain ID")
+	}
+	if m.Nonce, err = field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if !siweNoncePattern.MatchString(m.Nonce) {
+		return nil, errors.New("invalid SIWE message: nonce must be at least 8 alphanumeric characters")
+	}
+	issuedAt, err := timeField("Issued At", true)
+	if err != nil {
+		return nil, err
+	}
+	m.IssuedAt = *issuedAt
+	if m.ExpirationTime, err = timeField("Expiration Time", false); err != nil {
+		return nil, err
+	}
+	if m.NotBefore, err = timeField("Not Before", false); err != nil {
+		return nil, err
+	}
+	if m.RequestID, err = field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if next < len(lines) && lines[next] == "Resources:" {
+		next++
+		for next < len(lines) && strings.HasPrefix(lines[next], "- ") {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[next], "- "))
+			next++
+		}
+	}
+
+	if next != len(lines) {
+		return nil, fmt.Errorf("invalid SIWE message: unexpected line %q", lines[next])
+	}
+	return m, nil
+}
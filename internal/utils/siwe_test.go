@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSIWEAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func testSIWEMessage() *SIWEMessage {
+	expiration := time.Date(2026, 1, 2, 3, 14, 5, 0, time.UTC)
+	notBefore := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	return &SIWEMessage{
+		Domain:         "auction.example.com",
+		Address:        testSIWEAddress,
+		Statement:      "Sign in to My Auction Market",
+		URI:            "https://auction.example.com/login",
+		Version:        SIWEVersion,
+		ChainID:        11155111,
+		Nonce:          "abcd1234efgh",
+		IssuedAt:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ExpirationTime: &expiration,
+		NotBefore:      &notBefore,
+		RequestID:      "req-1",
+		Resources:      []string{"https://auction.example.com/terms", "ipfs://bafybeigdyrzt"},
+	}
+}
+
+func TestSIWEMessageRoundTrip(t *testing.T) {
+	minimal := testSIWEMessage()
+	minimal.Statement = ""
+	minimal.ExpirationTime = nil
+	minimal.NotBefore = nil
+	minimal.RequestID = ""
+	minimal.Resources = nil
+
+	tests := []struct {
+		name    string
+		message *SIWEMessage
+	}{
+		{"all fields", testSIWEMessage()},
+		{"required fields only", minimal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.message.String()
+			parsed, err := ParseSIWEMessage(raw)
+			if err != nil {
+				t.Fatalf("ParseSIWEMessage() error = %v\n%s", err, raw)
+			}
+			if !reflect.DeepEqual(parsed, tt.message) {
+				t.Errorf("ParseSIWEMessage() = %+v, want %+v", parsed, tt.message)
+			}
+			if got := parsed.String(); got != raw {
+				t.Errorf("String() after parse = %q, want %q", got, raw)
+			}
+		})
+	}
+}
+
+func TestParseSIWEMessageRejects(t *testing.T) {
+	valid := testSIWEMessage().String()
+	replace := func(old, new string) string {
+		if !strings.Contains(valid, old) {
+			t.Fatalf("test message does not contain %q", old)
+		}
+		return strings.Replace(valid, old, new, 1)
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		wantErr string
+	}{
+		{"empty", "", "missing header"},
+		{"missing header", replace(" wants you to sign in with your Ethereum account:", ""), "missing header"},
+		{"domain with space", replace("auction.example.com wants", "auction example.com wants"), "invalid domain"},
+		{"lowercase address", replace(testSIWEAddress, strings.ToLower(testSIWEAddress)), "EIP-55"},
+		{"invalid address", replace(testSIWEAddress, "0x1234"), "EIP-55"},
+		{"no blank line after address", replace(testSIWEAddress+"\n\n", testSIWEAddress+"\n"), "expected blank line after address"},
+		{"no blank line after statement", replace("Market\n\n", "Market\n"), "expected blank line after statement"},
+		{"no blank line before URI", replace("Sign in to My Auction Market\n\n", ""), "expected blank line before URI"},
+		{"missing URI", replace("URI: https://auction.example.com/login\n", ""), "missing URI"},
+		{"unsupported version", replace("Version: 1", "Version: 2"), "unsupported version"},
+		{"zero chain ID", replace("Chain ID: 11155111", "Chain ID: 0"), "invalid chain ID"},
+		{"non-numeric chain ID", replace("Chain ID: 11155111", "Chain ID: sepolia"), "invalid chain ID"},
+		{"short nonce", replace("Nonce: abcd1234efgh", "Nonce: abc123"), "nonce"},
+		{"non-alphanumeric nonce", replace("Nonce: abcd1234efgh", "Nonce: abcd-1234-efgh"), "nonce"},
+		{"empty field", replace("Request ID: req-1", "Request ID: "), "empty Request ID"},
+		{"bad issued at", replace("Issued At: 2026-01-02T03:04:05Z", "Issued At: 2026-01-02 03:04:05"), "Issued At is not an RFC 3339 timestamp"},
+		{"bad expiration", replace("Expiration Time: 2026-01-02T03:14:05Z", "Expiration Time: tomorrow"), "Expiration Time is not an RFC 3339 timestamp"},
+		{"fields out of order", replace("Version: 1\nChain ID: 11155111", "Chain ID: 11155111\nVersion: 1"), "missing Version"},
+		{"trailing line", valid + "\nextra", "unexpected line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSIWEMessage(tt.message)
+			if err == nil {
+				t.Fatalf("ParseSIWEMessage() error = nil, want %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSIWEMessage() error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -21,10 +21,10 @@ var upgrader = websocket.Upgrader{
 
 // ServeWS 处理 WebSocket 连接请求
 func ServeWS(hub *Hub, c *gin.Context) {
-	// 可选：验证 JWT token（如果需要认证）
+	// 可选：验证 JWT token（如果需要认证），已撤销的 token 按未登录处理
 	var userID uint = 0
 	if token := c.Query("token"); token != "" {
-		claims, err := appJWT.ParseToken(token)
+		claims, err := appJWT.ValidateToken(c.Request.Context(), token)
 		if err == nil {
 			userID = uint(claims.UserID)
 		}
@@ -55,8 +55,14 @@ type Hub struct {
 
 	// 保护 clients 和 rooms map 的互斥锁
 	mu sync.RWMutex
+
+	// 订阅校验函数（可选），返回 false 时拒绝客户端订阅房间
+	subscribeAuthorizer SubscribeAuthorizer
 }
 
+// SubscribeAuthorizer 订阅校验函数，userID 为 0 表示未携带 token 的连接
+type SubscribeAuthorizer func(userID uint, roomID string) bool
+
 // NewHub 创建新的 Hub
 func NewHub() *Hub {
 	return &Hub{
@@ -131,6 +137,11 @@ func (h *Hub) Run() {
 	}
 }
 
+// SetSubscribeAuthorizer 设置订阅校验函数（必须在客户端连接前调用）
+func (h *Hub) SetSubscribeAuthorizer(authorizer SubscribeAuthorizer) {
+	h.subscribeAuthorizer = authorizer
+}
+
 // BroadcastMessage 广播消息给所有客户端
 func (h *Hub) BroadcastMessage(message interface{}) error {
 	data, err := json.Marshal(message)
@@ -199,6 +210,36 @@ func (h *Hub) BroadcastToRoom(roomID string, message interface{}) error {
 	return nil
 }
 
+// SendToUser 向指定用户的所有连接推送消息（仅对携带 token 连接的客户端有效）
+func (h *Hub) SendToUser(userID uint, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	clientList := make([]*Client, 0)
+	for client := range h.clients {
+		if client.userID != 0 && client.userID == userID {
+			clientList = append(clientList, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	sentCount := 0
+	for _, client := range clientList {
+		select {
+		case client.send <- data:
+			sentCount++
+		default:
+			logger.Warn("websocket: client send channel is full, dropping message for user %d", userID)
+		}
+	}
+
+	logger.Debug("sent message to user: %d, sent to %d connections", userID, sentCount)
+	return nil
+}
+
 // SubscribeRoom 订阅房间
 func (h *Hub) SubscribeRoom(client *Client, roomID string) {
 	select {
@@ -286,7 +327,20 @@ func (c *Client) readPump() {
 					}
 				}
 
-				if subMsg.RoomID != "" {
+				if subMsg.RoomID != "" && c.hub.subscribeAuthorizer != nil && !c.hub.subscribeAuthorizer(c.userID, subMsg.RoomID) {
+					// 无权订阅（如邀请制拍卖的非白名单用户），返回错误消息
+					errorMsg := NewMessage(MessageTypeError, map[string]interface{}{
+						"room_id": subMsg.RoomID,
+						"message": "subscription denied",
+					})
+					errorData, _ := json.Marshal(errorMsg)
+					select {
+					case c.send <- errorData:
+					default:
+						logger.Warn("websocket: client send channel is full, dropping subscribe denied message")
+					}
+					logger.Warn("websocket: client (userID=%d) denied subscription to room: %s", c.userID, subMsg.RoomID)
+				} else if subMsg.RoomID != "" {
 					c.hub.SubscribeRoom(c, subMsg.RoomID)
 					// 发送订阅成功响应
 					successMsg := NewMessage(MessageTypeSubscribeSuccess, map[string]interface{}{
@@ -11,6 +11,10 @@ const (
 	// 当新的拍卖被创建时发送，广播给所有客户端
 	MessageTypeAuctionCreated MessageType = "auction_created"
 
+	// MessageTypeAuctionStarted 拍卖开始事件
+	// 当 upcoming 拍卖到达开始时间、开放出价时发送，广播给所有客户端
+	MessageTypeAuctionStarted MessageType = "auction_started"
+
 	// MessageTypeAuctionBidPlaced 出价事件
 	// 当有新的出价时发送，仅推送给订阅了该拍卖房间的客户端
 	MessageTypeAuctionBidPlaced MessageType = "auction_bid_placed"
@@ -27,6 +31,22 @@ const (
 	// 当拍卖被强制结束时发送，广播给所有客户端
 	MessageTypeAuctionForceEnded MessageType = "auction_force_ended"
 
+	// MessageTypeAuctionPriceTick 荷兰式拍卖价格推送
+	// 荷兰式拍卖进行期间定时发送当前价格，仅推送给订阅了该拍卖房间的客户端
+	MessageTypeAuctionPriceTick MessageType = "auction_price_tick"
+
+	// MessageTypeAuctionEndingSoon 拍卖即将结束提醒
+	// 在拍卖结束前的配置时间点发送，仅推送给订阅了该拍卖房间的客户端
+	MessageTypeAuctionEndingSoon MessageType = "auction_ending_soon"
+
+	// MessageTypeNotification 用户通知
+	// 定向推送给指定用户（需要携带 token 连接）
+	MessageTypeNotification MessageType = "notification"
+
+	// MessageTypeSealedAuctionResult 密封拍卖揭示结果
+	// 揭示截止后发送获胜者和成交价，仅推送给订阅了该拍卖房间的客户端
+	MessageTypeSealedAuctionResult MessageType = "sealed_auction_result"
+
 	// MessageTypeNFTApproved NFT授权事件
 	// 当NFT被授权给拍卖合约时发送，广播给所有客户端
 	MessageTypeNFTApproved MessageType = "nft_approved"
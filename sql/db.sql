@@ -19,6 +19,144 @@
 CREATE DATABASE IF NOT EXISTS `auction_market_db` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci */;
 USE `auction_market_db`;
 
+-- 导出  表 auction_market_db.api_keys 结构
+CREATE TABLE IF NOT EXISTS `api_keys` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `name` varchar(64) NOT NULL COMMENT '名称',
+  `key_prefix` varchar(20) NOT NULL COMMENT 'Key 前缀(用于识别和查找)',
+  `key_hash` varchar(64) NOT NULL COMMENT 'Key 的 SHA-256 哈希',
+  `scopes` varchar(255) NOT NULL COMMENT '权限范围(逗号分隔)',
+  `rate_limit` int(11) NOT NULL COMMENT '每分钟最大请求数',
+  `last_used_at` datetime DEFAULT NULL COMMENT '最近使用时间',
+  `last_used_ip` varchar(45) NOT NULL DEFAULT '' COMMENT '最近使用 IP',
+  `expires_at` datetime DEFAULT NULL COMMENT '过期时间(为空表示不过期)',
+  `revoked_at` datetime DEFAULT NULL COMMENT '撤销时间',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_api_keys_key_prefix` (`key_prefix`),
+  KEY `idx_api_keys_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户 API Key 表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_allowlists 结构
+CREATE TABLE IF NOT EXISTS `auction_allowlists` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `wallet_address` varchar(42) NOT NULL DEFAULT '' COMMENT '白名单钱包地址(小写)',
+  `user_id` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '白名单用户ID',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_auction_allowlists_member` (`auction_id`,`wallet_address`,`user_id`),
+  KEY `idx_auction_allowlists_wallet` (`wallet_address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='邀请制拍卖白名单表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_bundle_items 结构
+CREATE TABLE IF NOT EXISTS `auction_bundle_items` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `position` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '在打包中的顺序(0为链上拍卖的主NFT)',
+  `nft_id` varchar(64) NOT NULL COMMENT 'NFT唯一标识',
+  `nft_address` varchar(42) NOT NULL COMMENT 'NFT合约地址',
+  `token_id` bigint(20) unsigned NOT NULL COMMENT 'NFT的Token ID',
+  `contract_name` varchar(255) DEFAULT NULL COMMENT '合约名称',
+  `contract_symbol` varchar(64) DEFAULT NULL COMMENT '合约符号',
+  `token_uri` text DEFAULT NULL COMMENT 'Token URI',
+  `nft_name` varchar(255) DEFAULT NULL COMMENT 'NFT名称',
+  `image` text DEFAULT NULL COMMENT 'NFT图片URL',
+  `description` text DEFAULT NULL COMMENT 'NFT描述',
+  `delivery_status` varchar(20) NOT NULL DEFAULT '' COMMENT '成员转移状态(pending,submitted,delivered,failed)，主NFT由合约转移为空',
+  `delivery_tx_hash` varchar(66) NOT NULL DEFAULT '' COMMENT '成员转移交易哈希',
+  `delivery_error` varchar(255) NOT NULL DEFAULT '' COMMENT '成员转移失败原因',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_auction_bundle_items_auction_id` (`auction_id`),
+  KEY `idx_auction_bundle_items_delivery_status` (`delivery_status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='打包拍卖成员NFT表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_cancellations 结构
+CREATE TABLE IF NOT EXISTS `auction_cancellations` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '取消请求ID',
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `contract_auction_id` bigint(20) unsigned NOT NULL COMMENT '合约拍卖ID',
+  `mode` varchar(20) NOT NULL COMMENT '取消方式(seller,admin,system)',
+  `requested_by` bigint(20) unsigned NOT NULL COMMENT '发起取消的用户ID',
+  `reason` varchar(255) NOT NULL DEFAULT '' COMMENT '取消原因',
+  `status` varchar(20) NOT NULL DEFAULT 'requested' COMMENT '状态(requested,submitted,confirmed,failed)',
+  `tx_hash` varchar(66) NOT NULL DEFAULT '' COMMENT '取消交易哈希',
+  `error` varchar(255) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_auction_cancellations_auction_id` (`auction_id`),
+  KEY `idx_auction_cancellations_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='拍卖取消请求表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_templates 结构
+CREATE TABLE IF NOT EXISTS `auction_templates` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '模板ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '卖家ID',
+  `name` varchar(100) NOT NULL COMMENT '模板名称',
+  `auction_type` varchar(20) NOT NULL DEFAULT 'english' COMMENT '拍卖类型(english,dutch,sealed)',
+  `payment_token` varchar(42) NOT NULL COMMENT '支付代币地址',
+  `start_price` decimal(65,30) NOT NULL COMMENT '起拍价(单位由PaymentToken指定)',
+  `duration` bigint(20) unsigned NOT NULL COMMENT '拍卖时长(秒)',
+  `floor_price` decimal(65,30) DEFAULT NULL COMMENT '荷兰式拍卖底价',
+  `price_decay_interval` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '荷兰式拍卖降价间隔(秒)',
+  `reserve_price` decimal(65,30) DEFAULT NULL COMMENT '保留价(英式和密封拍卖)',
+  `bid_increment_percent` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '英式拍卖最小加价幅度(百分比)',
+  `commit_duration` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '密封拍卖提交阶段时长(秒)',
+  `reveal_duration` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '密封拍卖揭示阶段时长(秒)',
+  `auto_relist` tinyint(1) NOT NULL DEFAULT 0 COMMENT '流拍后是否自动重新上架',
+  `relist_duration` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '重新上架的拍卖时长(秒)',
+  `relist_price_reduction` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '重新上架的起拍价下调百分比',
+  `visibility` varchar(20) NOT NULL DEFAULT 'public' COMMENT '可见性(public,unlisted,allowlist)',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_auction_templates_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='拍卖模板表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_transitions 结构
+CREATE TABLE IF NOT EXISTS `auction_transitions` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `from_status` varchar(20) NOT NULL DEFAULT '' COMMENT '变更前状态(空表示新建)',
+  `to_status` varchar(20) NOT NULL COMMENT '变更后状态',
+  `actor` varchar(64) NOT NULL DEFAULT '' COMMENT '操作者(user:用户ID/链上地址/system)',
+  `cause` varchar(20) NOT NULL COMMENT '触发来源(api,event,task)',
+  `reason` varchar(64) NOT NULL DEFAULT '' COMMENT '变更原因',
+  `tx_hash` varchar(66) NOT NULL DEFAULT '' COMMENT '关联的链上交易哈希',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '变更时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_auction_transitions_auction_id` (`auction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='拍卖状态变更记录表（只追加）';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.auction_watches 结构
+CREATE TABLE IF NOT EXISTS `auction_watches` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '关注者ID',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_auction_watches_auction_user` (`auction_id`,`user_id`),
+  KEY `idx_auction_watches_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='拍卖关注表';
+
+-- 数据导出被取消选择。
+
 -- 导出  表 auction_market_db.auctions 结构
 CREATE TABLE IF NOT EXISTS `auctions` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
@@ -36,7 +174,8 @@ CREATE TABLE IF NOT EXISTS `auctions` (
   `image` text DEFAULT NULL COMMENT 'NFT图片URL',
   `description` text DEFAULT NULL COMMENT 'NFT描述',
   `metadata` longtext CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL COMMENT '完整元数据JSON',
-  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '状态(pending,active,ended,cancelled)',
+  `auction_type` varchar(20) NOT NULL DEFAULT 'english' COMMENT '拍卖类型(english,dutch,sealed,fixed)',
+  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '状态(draft,pending,upcoming,active,ended,cancelled,expired)',
   `online_lock` varchar(100) DEFAULT NULL COMMENT 'NFT在线标志 nft_id:1,也作为一个锁字段，解锁就改成其他值',
   `online` bigint(20) DEFAULT NULL COMMENT '1表示在线 其他值表示下线',
   `start_time` datetime NOT NULL COMMENT '开始时间',
@@ -46,7 +185,24 @@ CREATE TABLE IF NOT EXISTS `auctions` (
   `start_price` decimal(65,30) DEFAULT NULL COMMENT '起拍价(单位由PaymentToken指定:0x0=ETH,其他=ERC20代币)',
   `payment_token` varchar(42) DEFAULT NULL COMMENT '起拍价链上交易代币地址(0x0表示ETH,其他地址表示ERC20代币)',
   `start_price_usd` decimal(65,30) DEFAULT NULL COMMENT '起拍价USD',
-  `start_price_unit_usd` bigint(20) DEFAULT NULL COMMENT '起拍价USD预言机价格（小数点起拍价USD*10**8）',
+  `start_price_unit_usd` bigint(20) DEFAULT NULL COMMENT '起拍价USD预言机价格（小数点起拍价USD*10**8，作为合约 createAuction 的起拍价，荷兰式拍卖按底价换算）',
+  `floor_price` decimal(65,30) DEFAULT NULL COMMENT '荷兰式拍卖底价(单位由PaymentToken指定)',
+  `price_decay_interval` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '荷兰式拍卖降价间隔(秒,0表示连续降价)',
+  `reserve_price` decimal(65,30) DEFAULT NULL COMMENT '保留价(英式和密封拍卖,单位由PaymentToken指定,最高出价低于保留价时不成交)',
+  `bid_increment_percent` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '英式拍卖最小加价幅度(相对上一个最高出价的百分比,0表示不限制)',
+  `commit_end_time` datetime DEFAULT NULL COMMENT '密封拍卖提交承诺截止时间',
+  `reveal_end_time` datetime DEFAULT NULL COMMENT '密封拍卖揭示截止时间',
+  `sealed_winner` varchar(42) DEFAULT NULL COMMENT '密封拍卖获胜者地址(揭示结束后确定)',
+  `sealed_payment_token` varchar(42) DEFAULT NULL COMMENT '密封拍卖获胜者出价代币地址',
+  `sealed_clearing_price` decimal(65,30) DEFAULT NULL COMMENT '密封拍卖成交价(第二高价,单位由SealedPaymentToken指定)',
+  `sealed_clearing_price_usd` decimal(65,30) DEFAULT NULL COMMENT '密封拍卖成交价USD',
+  `auto_relist` tinyint(1) NOT NULL DEFAULT 0 COMMENT '流拍后是否自动重新上架',
+  `relist_duration` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '重新上架的拍卖时长(秒,0表示沿用原时长)',
+  `relist_price_reduction` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '重新上架的起拍价下调百分比(0-90)',
+  `relist_generation` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '重新上架代数(0表示首次上架)',
+  `relisted_from` varchar(50) DEFAULT NULL COMMENT '前一代拍卖ID(自动重新上架时设置)',
+  `bundle_size` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '打包拍卖的NFT数量(0表示单个NFT拍卖)',
+  `visibility` varchar(20) NOT NULL DEFAULT 'public' COMMENT '可见性(public,unlisted,allowlist)',
   `highest_bidder` varchar(42) DEFAULT NULL COMMENT '最高出价者地址',
   `highest_bid_payment_token` varchar(42) DEFAULT NULL COMMENT '最高出价使用链上交易代币地址(0x0=ETH,其他=ERC20代币,可能与拍卖PaymentToken不同)',
   `highest_bid` decimal(65,30) unsigned DEFAULT NULL COMMENT '最高出价金额(单位由HighestBidPaymentToken指定,可能与拍卖PaymentToken不同)',
@@ -62,9 +218,12 @@ CREATE TABLE IF NOT EXISTS `auctions` (
   KEY `idx_auctions_nft_id` (`nft_id`),
   KEY `idx_auctions_owner` (`owner_address`),
   KEY `idx_auctions_status` (`status`),
+  KEY `idx_auctions_type` (`auction_type`),
   KEY `idx_auctions_nft_address` (`nft_address`),
   KEY `idx_auctions_token_id` (`token_id`),
   KEY `idx_auctions_contract_id` (`contract_auction_id`) USING BTREE,
+  KEY `idx_auctions_relisted_from` (`relisted_from`),
+  KEY `idx_auctions_visibility` (`visibility`),
   KEY `online` (`online`),
   KEY `start_timestamp` (`start_timestamp`),
   KEY `end_timestamp` (`end_timestamp`),
@@ -93,6 +252,8 @@ CREATE TABLE IF NOT EXISTS `bids` (
   `block_number` bigint(20) unsigned DEFAULT NULL COMMENT '区块号',
   `timestamp` bigint(20) unsigned DEFAULT NULL COMMENT '链上时间',
   `is_highest` tinyint(1) DEFAULT 0 COMMENT '是否为最高出价',
+  `flagged` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否被标记(邀请制拍卖中非白名单钱包的出价、英式拍卖中未达到加价幅度的出价)',
+  `flag_reason` varchar(32) NOT NULL DEFAULT '' COMMENT '标记原因(not_allowlisted,below_bid_increment)',
   `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
   PRIMARY KEY (`id`),
   KEY `idx_bids_user_id` (`user_id`),
@@ -103,6 +264,40 @@ CREATE TABLE IF NOT EXISTS `bids` (
 
 -- 数据导出被取消选择。
 
+-- 导出  表 auction_market_db.contract_actions 结构
+CREATE TABLE IF NOT EXISTS `contract_actions` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '操作ID',
+  `action` varchar(32) NOT NULL COMMENT '操作类型',
+  `target` varchar(50) NOT NULL DEFAULT '' COMMENT '操作对象（如拍卖ID）',
+  `params` text DEFAULT NULL COMMENT '操作参数(JSON)',
+  `reason` varchar(255) NOT NULL DEFAULT '' COMMENT '操作原因',
+  `requested_by` bigint(20) unsigned NOT NULL COMMENT '发起操作的管理员用户ID',
+  `tx_hash` varchar(66) NOT NULL DEFAULT '' COMMENT '交易哈希',
+  `status` varchar(20) NOT NULL DEFAULT 'requested' COMMENT '状态(requested,submitted,confirmed,failed)',
+  `block_number` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '交易所在区块',
+  `error` varchar(255) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_contract_actions_action` (`action`),
+  KEY `idx_contract_actions_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='合约管理操作表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.contract_settings 结构
+CREATE TABLE IF NOT EXISTS `contract_settings` (
+  `setting_key` varchar(100) NOT NULL COMMENT '设置键',
+  `value` varchar(255) NOT NULL DEFAULT '' COMMENT '设置值',
+  `block_number` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '来源区块',
+  `log_index` int(10) unsigned NOT NULL DEFAULT 0 COMMENT '来源日志索引',
+  `tx_hash` varchar(66) NOT NULL DEFAULT '' COMMENT '来源交易哈希',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`setting_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='合约设置表';
+
+-- 数据导出被取消选择。
+
 -- 导出  表 auction_market_db.nfts 结构
 CREATE TABLE IF NOT EXISTS `nfts` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
@@ -131,6 +326,17 @@ CREATE TABLE IF NOT EXISTS `nfts` (
 
 -- 数据导出被取消选择。
 
+-- 导出  表 auction_market_db.nft_locks 结构
+CREATE TABLE IF NOT EXISTS `nft_locks` (
+  `nft_id` varchar(64) NOT NULL COMMENT 'NFT唯一标识',
+  `auction_id` varchar(50) NOT NULL COMMENT '锁定该NFT的拍卖ID',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '加锁时间',
+  PRIMARY KEY (`nft_id`),
+  KEY `idx_nft_locks_auction_id` (`auction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='NFT在线锁表';
+
+-- 数据导出被取消选择。
+
 -- 导出  表 auction_market_db.nft_ownerships 结构
 CREATE TABLE IF NOT EXISTS `nft_ownerships` (
   `id` int(11) NOT NULL AUTO_INCREMENT,
@@ -150,20 +356,106 @@ CREATE TABLE IF NOT EXISTS `nft_ownerships` (
 
 -- 数据导出被取消选择。
 
+-- 导出  表 auction_market_db.offers 结构
+CREATE TABLE IF NOT EXISTS `offers` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '报价ID',
+  `nft_id` varchar(64) NOT NULL COMMENT 'NFT唯一标识',
+  `nft_address` varchar(42) NOT NULL COMMENT 'NFT合约地址',
+  `token_id` bigint(20) unsigned NOT NULL COMMENT 'Token ID',
+  `owner_user_id` bigint(20) unsigned NOT NULL COMMENT '报价时NFT持有者ID',
+  `buyer_user_id` bigint(20) unsigned NOT NULL COMMENT '买家ID',
+  `buyer_address` varchar(42) NOT NULL COMMENT '买家钱包地址',
+  `payment_token` varchar(42) NOT NULL COMMENT '报价代币地址(仅支持ERC20)',
+  `amount` decimal(65,30) NOT NULL COMMENT '报价金额(单位由PaymentToken指定)',
+  `amount_usd` decimal(65,30) DEFAULT NULL COMMENT '报价时的USD价值',
+  `raw_amount` varchar(80) NOT NULL COMMENT '报价金额(代币最小单位，参与签名)',
+  `expiry` bigint(20) NOT NULL COMMENT '过期时间戳(秒，参与签名)',
+  `nonce` varchar(80) NOT NULL COMMENT '买家随机数(参与签名)',
+  `offer_hash` varchar(66) NOT NULL COMMENT 'EIP-712摘要',
+  `signature` varchar(132) NOT NULL COMMENT '买家EIP-712签名',
+  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '状态(pending,accepted,rejected,expired,cancelled)',
+  `listing_auction_id` varchar(50) DEFAULT NULL COMMENT '接受后创建的固定价格出售拍卖ID',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_offers_offer_hash` (`offer_hash`),
+  KEY `idx_offers_nft_id` (`nft_id`),
+  KEY `idx_offers_owner_user_id` (`owner_user_id`),
+  KEY `idx_offers_buyer_user_id` (`buyer_user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='NFT链下签名报价表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.sealed_bids 结构
+CREATE TABLE IF NOT EXISTS `sealed_bids` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '密封出价ID',
+  `auction_id` varchar(50) NOT NULL COMMENT '拍卖ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '出价者ID',
+  `wallet_address` varchar(42) NOT NULL COMMENT '出价者钱包地址',
+  `commit_hash` varchar(66) NOT NULL COMMENT '出价承诺哈希 keccak256(auctionId|bidder|paymentToken|amount|salt)',
+  `commit_signature` varchar(132) NOT NULL COMMENT '出价者对承诺消息的签名',
+  `prev_chain_hash` varchar(66) NOT NULL COMMENT '上一条承诺的链式哈希',
+  `chain_hash` varchar(66) NOT NULL COMMENT '链式哈希(防篡改)',
+  `committed_at` datetime(6) NOT NULL COMMENT '承诺提交时间',
+  `status` varchar(20) NOT NULL DEFAULT 'committed' COMMENT '状态(committed,revealed,invalid)',
+  `payment_token` varchar(42) DEFAULT NULL COMMENT '揭示的出价代币地址',
+  `amount` decimal(65,30) DEFAULT NULL COMMENT '揭示的出价金额(单位由PaymentToken指定)',
+  `amount_usd` decimal(65,30) DEFAULT NULL COMMENT '揭示时的出价USD价值',
+  `raw_amount` varchar(80) DEFAULT NULL COMMENT '揭示的原始金额字符串(参与承诺哈希计算)',
+  `salt` varchar(128) DEFAULT NULL COMMENT '揭示的随机盐',
+  `reveal_signature` varchar(132) DEFAULT NULL COMMENT '出价者对揭示消息的签名',
+  `revealed_at` datetime DEFAULT NULL COMMENT '揭示时间',
+  `rank` bigint(20) unsigned NOT NULL DEFAULT 0 COMMENT '揭示结束后的排名(1为最高)',
+  `winner` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否为获胜者',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_sealed_bids_auction_user` (`auction_id`,`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='密封拍卖出价承诺表';
+
+-- 数据导出被取消选择。
+
+-- 导出  表 auction_market_db.user_wallets 结构
+CREATE TABLE IF NOT EXISTS `user_wallets` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` bigint(20) unsigned NOT NULL COMMENT '用户ID',
+  `wallet_address` varchar(42) NOT NULL COMMENT '钱包地址（小写）',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '关联时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_user_wallets_wallet_address` (`wallet_address`),
+  KEY `idx_user_wallets_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户关联钱包表';
+
+-- 数据导出被取消选择。
+
 -- 导出  表 auction_market_db.users 结构
 CREATE TABLE IF NOT EXISTS `users` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '用户ID',
   `username` varchar(64) NOT NULL COMMENT '用户名',
-  `email` varchar(128) NOT NULL COMMENT '邮箱',
+  `email` varchar(128) DEFAULT NULL COMMENT '邮箱(可选)',
+  `email_verified` tinyint(1) NOT NULL DEFAULT 0 COMMENT '邮箱是否已验证',
+  `pending_email` varchar(128) DEFAULT NULL COMMENT '待验证的新邮箱(验证后写入email)',
   `password` varchar(255) NOT NULL COMMENT '密码哈希',
   `wallet_address` varchar(42) DEFAULT NULL COMMENT '钱包地址',
   `nonce` varchar(64) DEFAULT NULL COMMENT '登录Nonce',
+  `role` varchar(20) NOT NULL DEFAULT 'user' COMMENT '角色(user,moderator,admin)',
+  `display_name` varchar(64) NOT NULL DEFAULT '' COMMENT '昵称',
+  `avatar_url` varchar(255) NOT NULL DEFAULT '' COMMENT '头像URL',
+  `bio` varchar(500) NOT NULL DEFAULT '' COMMENT '个人简介',
+  `onboarded_at` datetime DEFAULT NULL COMMENT '完成引导时间',
+  `status` varchar(20) NOT NULL DEFAULT 'active' COMMENT '状态(active,suspended,banned,deleted)',
+  `status_reason` varchar(255) NOT NULL DEFAULT '' COMMENT '暂停/封禁原因',
+  `suspended_until` datetime DEFAULT NULL COMMENT '暂停截止时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '注销时间',
   `created_at` datetime NOT NULL DEFAULT current_timestamp() COMMENT '创建时间',
   `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp() COMMENT '更新时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_users_username` (`username`),
   UNIQUE KEY `idx_users_email` (`email`),
-  KEY `idx_users_wallet_address` (`wallet_address`)
+  KEY `idx_users_pending_email` (`pending_email`),
+  KEY `idx_users_wallet_address` (`wallet_address`),
+  KEY `idx_users_role` (`role`),
+  KEY `idx_users_status` (`status`)
 ) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
 
 -- 数据导出被取消选择。